@@ -416,6 +416,7 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 	}
 
 	var handler outbound.Handler
+	var fallbackRoute routing.FallbackRoute
 
 	routingLink := routing_session.AsRoutingContext(ctx)
 	inTag := routingLink.GetInboundTag()
@@ -443,6 +444,7 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 					errors.LogInfo(ctx, "Hit route rule: [", route.GetRuleTag(), "] so taking detour [", outTag, "] for [", destination, "]")
 				}
 				handler = h
+				fallbackRoute, _ = route.(routing.FallbackRoute)
 			} else {
 				errors.LogWarning(ctx, "non existing outTag: ", outTag)
 			}
@@ -462,6 +464,13 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 		return
 	}
 
+	if fallbackRoute != nil && destination.Network == net.Network_TCP {
+		if handlers := d.fallbackHandlers(handler, fallbackRoute.GetFallbackTags()); len(handlers) > 1 {
+			d.fallbackDispatch(ctx, link, handlers, fallbackRoute.GetAttemptTimeout(), inTag)
+			return
+		}
+	}
+
 	ob.Tag = handler.Tag()
 	if accessMessage := log.AccessMessageFromContext(ctx); accessMessage != nil {
 		if tag := handler.Tag(); tag != "" {
@@ -478,5 +487,83 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 		log.Record(accessMessage)
 	}
 
+	if reporter, ok := d.router.(routing.HealthReporter); ok {
+		feedback := &dialFeedback{reporter: reporter, tag: handler.Tag()}
+		handler.Dispatch(session.ContextWithDialFeedback(ctx, feedback), link)
+		feedback.finish()
+		return
+	}
 	handler.Dispatch(ctx, link)
 }
+
+// dialFeedback collects dial results of an outbound, and reports them to the
+// passive health tracker of the router if any. The first successful dial is
+// reported at once, while a failure is reported only if no dial of the
+// connection has succeeded.
+type dialFeedback struct {
+	reporter  routing.HealthReporter
+	tag       string
+	onSuccess func()
+	cancel    context.CancelFunc
+
+	access    sync.Mutex
+	succeeded bool
+	active    bool
+	done      bool
+	err       error
+}
+
+// SubmitDialResult implements session.DialFeedback.
+func (f *dialFeedback) SubmitDialResult(err error) {
+	f.access.Lock()
+	defer f.access.Unlock()
+	if f.succeeded || f.done {
+		return
+	}
+	if err != nil {
+		f.err = err
+		return
+	}
+	f.succeeded = true
+	if f.reporter != nil {
+		f.reporter.ReportSuccess(f.tag)
+	}
+	if f.onSuccess != nil {
+		f.onSuccess()
+	}
+}
+
+// activate marks the outbound as carrying traffic, so that it no longer expires
+// even if no dial result is submitted.
+func (f *dialFeedback) activate() {
+	f.access.Lock()
+	defer f.access.Unlock()
+	f.active = true
+}
+
+// expire fails the outbound and cancels its dialing, if it has neither
+// connected nor carried any traffic yet.
+func (f *dialFeedback) expire(timeout time.Duration) {
+	f.access.Lock()
+	defer f.access.Unlock()
+	if f.succeeded || f.active || f.done {
+		return
+	}
+	f.err = errors.New("failed to connect in ", timeout)
+	f.done = true
+	f.cancel()
+}
+
+// finish stops collecting dial results, and returns whether the outbound has
+// failed to connect.
+func (f *dialFeedback) finish() bool {
+	f.access.Lock()
+	defer f.access.Unlock()
+	failed := !f.succeeded && f.err != nil
+	if failed && f.reporter != nil {
+		f.reporter.ReportFailure(f.tag, f.err)
+	}
+	// dials after the connection is closed are not reported
+	f.done = true
+	return failed
+}
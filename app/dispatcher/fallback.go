@@ -0,0 +1,240 @@
+package dispatcher
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/log"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/features/outbound"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport"
+)
+
+// attemptLink wraps the link of an attempt in a fallback chain. Until
+// committed, it holds back closing the underlying link, so that the link can
+// be handed over to the next outbound unless any payload has passed through.
+type attemptLink struct {
+	link *transport.Link
+	// done is called once the outbound of the committed attempt closes or
+	// interrupts the writer of the link.
+	done func()
+	// active is called once the outbound of the attempt first reads or
+	// writes the link.
+	active     func()
+	activeOnce sync.Once
+
+	access            sync.Mutex
+	used              bool
+	committed         bool
+	readerInterrupted bool
+	writerInterrupted bool
+	writerClosed      bool
+}
+
+type attemptReader struct {
+	*attemptLink
+}
+
+type attemptWriter struct {
+	*attemptLink
+}
+
+func newAttemptLink(link *transport.Link, done func(), active func()) (*attemptLink, *transport.Link) {
+	l := &attemptLink{link: link, done: done, active: active}
+	return l, &transport.Link{
+		Reader: &attemptReader{l},
+		Writer: &attemptWriter{l},
+	}
+}
+
+func (l *attemptLink) activate() {
+	l.activeOnce.Do(l.active)
+}
+
+func (l *attemptLink) use(mb buf.MultiBuffer) {
+	if !mb.IsEmpty() {
+		l.access.Lock()
+		l.used = true
+		l.access.Unlock()
+	}
+}
+
+// isUsed returns whether any payload has passed through the link.
+func (l *attemptLink) isUsed() bool {
+	l.access.Lock()
+	defer l.access.Unlock()
+	return l.used
+}
+
+// commit gives up handing over the link, and applies the closing held back.
+func (l *attemptLink) commit() {
+	l.access.Lock()
+	defer l.access.Unlock()
+	l.committed = true
+	if l.readerInterrupted {
+		common.Interrupt(l.link.Reader)
+	}
+	if l.writerInterrupted {
+		common.Interrupt(l.link.Writer)
+		l.done()
+	} else if l.writerClosed {
+		common.Close(l.link.Writer)
+		l.done()
+	}
+}
+
+// ReadMultiBuffer implements buf.Reader.
+func (r *attemptReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	r.activate()
+	mb, err := r.link.Reader.ReadMultiBuffer()
+	r.use(mb)
+	return mb, err
+}
+
+// ReadMultiBufferTimeout implements buf.TimeoutReader.
+func (r *attemptReader) ReadMultiBufferTimeout(timeout time.Duration) (buf.MultiBuffer, error) {
+	tr, ok := r.link.Reader.(buf.TimeoutReader)
+	if !ok {
+		return r.ReadMultiBuffer()
+	}
+	r.activate()
+	mb, err := tr.ReadMultiBufferTimeout(timeout)
+	r.use(mb)
+	return mb, err
+}
+
+// Interrupt implements common.Interruptible.
+func (r *attemptReader) Interrupt() {
+	r.access.Lock()
+	defer r.access.Unlock()
+	if r.committed {
+		common.Interrupt(r.link.Reader)
+		return
+	}
+	r.readerInterrupted = true
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *attemptWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	w.activate()
+	w.use(mb)
+	return w.link.Writer.WriteMultiBuffer(mb)
+}
+
+// Close implements common.Closable.
+func (w *attemptWriter) Close() error {
+	w.access.Lock()
+	defer w.access.Unlock()
+	if w.committed {
+		defer w.done()
+		return common.Close(w.link.Writer)
+	}
+	w.writerClosed = true
+	return nil
+}
+
+// Interrupt implements common.Interruptible.
+func (w *attemptWriter) Interrupt() {
+	w.access.Lock()
+	defer w.access.Unlock()
+	if w.committed {
+		common.Interrupt(w.link.Writer)
+		w.done()
+		return
+	}
+	w.writerInterrupted = true
+}
+
+// fallbackHandlers returns the handlers to try in turn, starting with handler.
+func (d *DefaultDispatcher) fallbackHandlers(handler outbound.Handler, tags []string) []outbound.Handler {
+	handlers := []outbound.Handler{handler}
+	for _, tag := range tags {
+		if tag == handler.Tag() {
+			continue
+		}
+		if h := d.ohm.GetHandler(tag); h != nil {
+			handlers = append(handlers, h)
+		} else {
+			errors.LogWarning(context.Background(), "non existing fallback outTag: ", tag)
+		}
+	}
+	return handlers
+}
+
+// fallbackDispatch dispatches the link to handlers in turn, until one of them
+// connects, or any payload has passed through. The outbounds tried are
+// recorded in the access log, separated by "~>".
+func (d *DefaultDispatcher) fallbackDispatch(ctx context.Context, link *transport.Link, handlers []outbound.Handler, timeout time.Duration, inTag string) {
+	outbounds := session.OutboundsFromContext(ctx)
+	ob := outbounds[len(outbounds)-1]
+	reporter, _ := d.router.(routing.HealthReporter)
+
+	var tried []string
+	var recordOnce sync.Once
+	record := func() {
+		recordOnce.Do(func() {
+			accessMessage := log.AccessMessageFromContext(ctx)
+			if accessMessage == nil {
+				return
+			}
+			accessMessage.Detour = strings.Join(tried, " ~> ")
+			if inTag != "" {
+				accessMessage.Detour = inTag + " -> " + accessMessage.Detour
+			}
+			log.Record(accessMessage)
+		})
+	}
+	defer record()
+
+	for i, handler := range handlers {
+		ob.Tag = handler.Tag()
+		tried = append(tried, handler.Tag())
+		feedback := &dialFeedback{reporter: reporter, tag: handler.Tag(), onSuccess: record}
+		attemptCtx, cancel := context.WithCancel(session.ContextWithDialFeedback(ctx, feedback))
+		feedback.cancel = cancel
+
+		// the last outbound has nothing to fall back to, so it is never
+		// timed out
+		last := i == len(handlers)-1
+		var timer *time.Timer
+		if timeout > 0 && !last {
+			timer = time.AfterFunc(timeout, func() {
+				feedback.expire(timeout)
+			})
+		}
+		attempt, attemptedLink := newAttemptLink(link, cancel, func() {
+			feedback.activate()
+			if timer != nil {
+				timer.Stop()
+			}
+		})
+		if last {
+			attempt.commit()
+		}
+		handler.Dispatch(attemptCtx, attemptedLink)
+		if timer != nil {
+			timer.Stop()
+		}
+
+		// attemptCtx is not canceled until the link is done unless the
+		// attempt is dropped, as handlers like mux keep using it after
+		// Dispatch returns
+		failed := feedback.finish()
+		if last {
+			return
+		}
+		if failed && !attempt.isUsed() {
+			feedback.cancel()
+			errors.LogInfoInner(ctx, feedback.err, "failed to connect [", handler.Tag(), "], falling back to [", handlers[i+1].Tag(), "]")
+			continue
+		}
+		attempt.commit()
+		return
+	}
+}
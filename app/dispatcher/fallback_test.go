@@ -0,0 +1,175 @@
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/features/outbound"
+	"github.com/xtls/xray-core/transport"
+	"github.com/xtls/xray-core/transport/pipe"
+)
+
+type testHandler struct {
+	tag      string
+	dial     func(ctx context.Context) error
+	received buf.MultiBuffer
+	ctx      context.Context
+}
+
+func (h *testHandler) Start() error { return nil }
+func (h *testHandler) Close() error { return nil }
+func (h *testHandler) Tag() string  { return h.tag }
+
+// Dispatch works like Handler.Dispatch of proxyman, which dials before
+// reading any payload.
+func (h *testHandler) Dispatch(ctx context.Context, link *transport.Link) {
+	h.ctx = ctx
+	err := h.dial(ctx)
+	session.SubmitDialResult(ctx, err)
+	if err != nil {
+		common.Interrupt(link.Writer)
+		common.Interrupt(link.Reader)
+		return
+	}
+	h.received, _ = link.Reader.ReadMultiBuffer()
+	link.Writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("pong")))
+	common.Close(link.Writer)
+	common.Interrupt(link.Reader)
+}
+
+func newTestLinks() (*transport.Link, *transport.Link) {
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	return &transport.Link{Reader: uplinkReader, Writer: downlinkWriter},
+		&transport.Link{Reader: downlinkReader, Writer: uplinkWriter}
+}
+
+func TestFallbackDispatch(t *testing.T) {
+	errDial := errors.New("connection refused")
+	failed := &testHandler{tag: "failed", dial: func(context.Context) error { return errDial }}
+	timeout := &testHandler{tag: "timeout", dial: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	working := &testHandler{tag: "working", dial: func(context.Context) error { return nil }}
+
+	link, inbound := newTestLinks()
+	common.Must(inbound.Writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("ping"))))
+
+	ctx := session.ContextWithOutbounds(context.Background(), []*session.Outbound{{}})
+	d := new(DefaultDispatcher)
+	d.fallbackDispatch(ctx, link, []outbound.Handler{failed, timeout, working}, 100*time.Millisecond, "")
+
+	if r := working.received.String(); r != "ping" {
+		t.Errorf("expected payload ping, actual %q", r)
+	}
+	mb, err := inbound.Reader.ReadMultiBuffer()
+	if err != nil || mb.String() != "pong" {
+		t.Errorf("expected response pong, actual %q, %v", mb.String(), err)
+	}
+	if _, err := inbound.Reader.ReadMultiBuffer(); err == nil {
+		t.Error("expected downlink closed")
+	}
+	if tag := session.OutboundsFromContext(ctx)[0].Tag; tag != "working" {
+		t.Errorf("expected outbound tag working, actual %v", tag)
+	}
+	if working.ctx.Err() == nil {
+		t.Error("expected context of the attempt canceled with the link done")
+	}
+}
+
+// muxHandler keeps using the link after Dispatch returns, like mux.
+type muxHandler struct {
+	testHandler
+	link *transport.Link
+}
+
+func (h *muxHandler) Dispatch(ctx context.Context, link *transport.Link) {
+	h.ctx = ctx
+	session.SubmitDialResult(ctx, nil)
+	h.link = link
+}
+
+func TestFallbackDispatchCancel(t *testing.T) {
+	for _, n := range []int{1, 2} {
+		mux := &muxHandler{testHandler: testHandler{tag: "mux"}}
+		handlers := []outbound.Handler{mux}
+		if n > 1 {
+			handlers = append(handlers, &testHandler{tag: "unused", dial: func(context.Context) error { return nil }})
+		}
+
+		link, _ := newTestLinks()
+		ctx := session.ContextWithOutbounds(context.Background(), []*session.Outbound{{}})
+		d := new(DefaultDispatcher)
+		d.fallbackDispatch(ctx, link, handlers, 0, "")
+
+		if mux.ctx.Err() != nil {
+			t.Fatal("expected context of the attempt alive with the link in use")
+		}
+		common.Close(mux.link.Writer)
+		if mux.ctx.Err() == nil {
+			t.Error("expected context of the attempt canceled with the link done")
+		}
+	}
+}
+
+func TestFallbackDispatchAllFailed(t *testing.T) {
+	errDial := errors.New("connection refused")
+	a := &testHandler{tag: "a", dial: func(context.Context) error { return errDial }}
+	b := &testHandler{tag: "b", dial: func(context.Context) error { return errDial }}
+
+	link, inbound := newTestLinks()
+	ctx := session.ContextWithOutbounds(context.Background(), []*session.Outbound{{}})
+	d := new(DefaultDispatcher)
+	d.fallbackDispatch(ctx, link, []outbound.Handler{a, b}, 0, "")
+
+	if _, err := inbound.Reader.ReadMultiBuffer(); err == nil {
+		t.Error("expected downlink interrupted")
+	}
+}
+
+// silentHandler carries traffic without submitting any dial result, like
+// outbounds that do not dial.
+type silentHandler struct {
+	testHandler
+	err error
+}
+
+func (h *silentHandler) Dispatch(ctx context.Context, link *transport.Link) {
+	h.ctx = ctx
+	h.received, _ = link.Reader.ReadMultiBuffer()
+	time.Sleep(200 * time.Millisecond)
+	h.err = ctx.Err()
+	link.Writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("pong")))
+	common.Close(link.Writer)
+	common.Interrupt(link.Reader)
+}
+
+func TestFallbackDispatchNoDialResult(t *testing.T) {
+	for _, n := range []int{1, 2} {
+		silent := &silentHandler{testHandler: testHandler{tag: "silent"}}
+		handlers := []outbound.Handler{silent}
+		if n > 1 {
+			handlers = append(handlers, &testHandler{tag: "unused", dial: func(context.Context) error { return nil }})
+		}
+
+		link, inbound := newTestLinks()
+		common.Must(inbound.Writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("ping"))))
+		ctx := session.ContextWithOutbounds(context.Background(), []*session.Outbound{{}})
+		d := new(DefaultDispatcher)
+		d.fallbackDispatch(ctx, link, handlers, 50*time.Millisecond, "")
+
+		if silent.err != nil {
+			t.Errorf("expected context of the attempt alive with traffic, actual %v", silent.err)
+		}
+		mb, err := inbound.Reader.ReadMultiBuffer()
+		if err != nil || mb.String() != "pong" {
+			t.Errorf("expected response pong, actual %q, %v", mb.String(), err)
+		}
+	}
+}
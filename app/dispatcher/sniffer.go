@@ -10,6 +10,7 @@ import (
 	"github.com/xtls/xray-core/common/protocol/http"
 	"github.com/xtls/xray-core/common/protocol/quic"
 	"github.com/xtls/xray-core/common/protocol/tls"
+	"github.com/xtls/xray-core/common/session"
 )
 
 type SniffResult interface {
@@ -36,7 +37,7 @@ func NewSniffer(ctx context.Context) *Sniffer {
 	ret := &Sniffer{
 		sniffer: []protocolSnifferWithMetadata{
 			{func(c context.Context, b []byte) (SniffResult, error) { return http.SniffHTTP(b, c) }, false, net.Network_TCP},
-			{func(c context.Context, b []byte) (SniffResult, error) { return tls.SniffTLS(b) }, false, net.Network_TCP},
+			{func(c context.Context, b []byte) (SniffResult, error) { return sniffTLS(c, b) }, false, net.Network_TCP},
 			{func(c context.Context, b []byte) (SniffResult, error) { return bittorrent.SniffBittorrent(b) }, false, net.Network_TCP},
 			{func(c context.Context, b []byte) (SniffResult, error) { return quic.SniffQUIC(b) }, false, net.Network_UDP},
 			{func(c context.Context, b []byte) (SniffResult, error) { return bittorrent.SniffUTP(b) }, false, net.Network_UDP},
@@ -55,6 +56,25 @@ func NewSniffer(ctx context.Context) *Sniffer {
 
 var errUnknownContent = errors.New("unknown content")
 
+// sniffTLS sniffs a TLS client hello and exposes its ALPN, version, server
+// name and fingerprints as routing attributes. A client hello without server
+// name is not sniffed as TLS, but still exposes its attributes.
+func sniffTLS(c context.Context, b []byte) (SniffResult, error) {
+	h, err := tls.SniffClientHello(b)
+	if err != nil {
+		return nil, err
+	}
+	if content := session.ContentFromContext(c); content != nil {
+		for key, value := range h.Attributes() {
+			content.SetAttribute(key, value)
+		}
+	}
+	if !h.HasServerName() {
+		return nil, errUnknownContent
+	}
+	return h, nil
+}
+
 func (s *Sniffer) Sniff(c context.Context, payload []byte, network net.Network) (SniffResult, error) {
 	var pendingSniffer []protocolSnifferWithMetadata
 	for _, si := range s.sniffer {
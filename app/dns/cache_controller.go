@@ -0,0 +1,357 @@
+package dns
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/signal/pubsub"
+	"github.com/xtls/xray-core/common/task"
+	"github.com/xtls/xray-core/core"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// defaultStaleMaxAge is the default time an expired record is served,
+	// within 1 to 3 days suggested by RFC 8767.
+	defaultStaleMaxAge = 24 * time.Hour
+	// prefetchHits is the number of hits during its TTL that makes a record
+	// worth prefetching.
+	prefetchHits = 2
+	// refreshTimeout is the timeout of refreshing a record in background.
+	refreshTimeout = 8 * time.Second
+)
+
+// CacheOptions controls how expiring records are refreshed.
+type CacheOptions struct {
+	// Prefetch refreshes records hit frequently in background, when they are
+	// going to expire in a tenth of their TTL.
+	Prefetch bool
+	// ServeStale answers from expired records while refreshing them in
+	// background, as described in RFC 8767.
+	ServeStale bool
+	// StaleMaxAge is the time after expiry an expired record is served.
+	StaleMaxAge time.Duration
+}
+
+// CacheController holds the records resolved by a name server.
+type CacheController struct {
+	sync.RWMutex
+	name       string
+	ips        map[string]*record
+	pub        *pubsub.Service
+	cleanup    *task.Periodic
+	refreshing map[string]time.Time
+	options    CacheOptions
+}
+
+// NewCacheController creates a new CacheController for the name server.
+func NewCacheController(name string) *CacheController {
+	c := &CacheController{
+		name:       name,
+		ips:        make(map[string]*record),
+		pub:        pubsub.NewService(),
+		refreshing: make(map[string]time.Time),
+	}
+	c.cleanup = &task.Periodic{
+		Interval: time.Minute,
+		Execute:  c.CacheCleanup,
+	}
+	return c
+}
+
+// SetCacheOptions sets how expiring records are refreshed.
+func (c *CacheController) SetCacheOptions(options CacheOptions) {
+	if options.ServeStale && options.StaleMaxAge <= 0 {
+		options.StaleMaxAge = defaultStaleMaxAge
+	}
+	c.Lock()
+	c.options = options
+	c.Unlock()
+}
+
+// staleMaxAge returns the time after expiry a record is kept.
+func (c *CacheController) staleMaxAge() time.Duration {
+	if c.options.ServeStale {
+		return c.options.StaleMaxAge
+	}
+	return 0
+}
+
+// CacheCleanup clears expired items from cache
+func (c *CacheController) CacheCleanup() error {
+	now := time.Now()
+	c.Lock()
+	defer c.Unlock()
+
+	if len(c.ips) == 0 {
+		return errors.New("nothing to do. stopping...")
+	}
+
+	// records are kept during their stale age after expiry
+	deadline := now.Add(-c.staleMaxAge())
+	for domain, record := range c.ips {
+		if record.A != nil && record.A.Expire.Before(deadline) {
+			record.A = nil
+		}
+		if record.AAAA != nil && record.AAAA.Expire.Before(deadline) {
+			record.AAAA = nil
+		}
+
+		if record.A == nil && record.AAAA == nil {
+			errors.LogDebug(context.Background(), c.name, " cleanup ", domain)
+			delete(c.ips, domain)
+		} else {
+			c.ips[domain] = record
+		}
+	}
+
+	if len(c.ips) == 0 {
+		c.ips = make(map[string]*record)
+	}
+
+	for domain, start := range c.refreshing {
+		if now.Sub(start) > refreshTimeout {
+			delete(c.refreshing, domain)
+		}
+	}
+
+	return nil
+}
+
+func (c *CacheController) updateIP(req *dnsRequest, ipRec *IPRecord) {
+	elapsed := time.Since(req.start)
+
+	c.Lock()
+	rec, found := c.ips[req.domain]
+	if !found {
+		rec = &record{}
+	}
+	updated := false
+
+	switch req.reqType {
+	case dnsmessage.TypeA:
+		if isNewer(rec.A, ipRec) {
+			rec.A = ipRec
+			rec.ttlA = time.Until(ipRec.Expire)
+			updated = true
+		}
+	case dnsmessage.TypeAAAA:
+		addr := make([]net.Address, 0, len(ipRec.IP))
+		for _, ip := range ipRec.IP {
+			if len(ip.IP()) == net.IPv6len {
+				addr = append(addr, ip)
+			}
+		}
+		ipRec.IP = addr
+		if isNewer(rec.AAAA, ipRec) {
+			rec.AAAA = ipRec
+			rec.ttlAAAA = time.Until(ipRec.Expire)
+			updated = true
+		}
+	}
+	errors.LogInfo(context.Background(), c.name, " got answer: ", req.domain, " ", req.reqType, " -> ", ipRec.IP, " ", elapsed)
+
+	if updated {
+		atomic.StoreUint32(&rec.hits, 0)
+		c.ips[req.domain] = rec
+	}
+	delete(c.refreshing, req.domain)
+	switch req.reqType {
+	case dnsmessage.TypeA:
+		c.pub.Publish(req.domain+"4", nil)
+	case dnsmessage.TypeAAAA:
+		c.pub.Publish(req.domain+"6", nil)
+	}
+	c.Unlock()
+	common.Must(c.cleanup.Start())
+}
+
+// findIPsForDomain returns the cached IPs of the domain, and whether the
+// records should be refreshed in background.
+func (c *CacheController) findIPsForDomain(domain string, option dns_feature.IPOption) ([]net.IP, bool, error) {
+	return c.findIPs(domain, option, true)
+}
+
+// findFreshIPsForDomain returns the cached IPs of the domain like
+// findIPsForDomain, but never the stale ones, for waiting for the answers of
+// a query.
+func (c *CacheController) findFreshIPsForDomain(domain string, option dns_feature.IPOption) ([]net.IP, error) {
+	ips, _, err := c.findIPs(domain, option, false)
+	return ips, err
+}
+
+func (c *CacheController) findIPs(domain string, option dns_feature.IPOption, serveStale bool) ([]net.IP, bool, error) {
+	c.RLock()
+	rec, found := c.ips[domain]
+	var recA, recAAAA *IPRecord
+	var ttlA, ttlAAAA time.Duration
+	if found {
+		recA, recAAAA = rec.A, rec.AAAA
+		ttlA, ttlAAAA = rec.ttlA, rec.ttlAAAA
+	}
+	options := c.options
+	c.RUnlock()
+
+	if !found {
+		return nil, false, errRecordNotFound
+	}
+
+	now := time.Now()
+	staleDeadline := now.Add(-options.StaleMaxAge)
+	var stale, expiring bool
+	getIPs := func(r *IPRecord, ttl time.Duration) ([]net.Address, error) {
+		if r == nil {
+			return nil, errRecordNotFound
+		}
+		if r.Expire.Before(now) {
+			if !serveStale || !options.ServeStale || r.Expire.Before(staleDeadline) {
+				return nil, errRecordNotFound
+			}
+			stale = true
+		} else if options.Prefetch && r.Expire.Sub(now) < ttl/10 {
+			expiring = true
+		}
+		if r.RCode != dnsmessage.RCodeSuccess {
+			return nil, dns_feature.RCodeError(r.RCode)
+		}
+		return r.IP, nil
+	}
+
+	var err4 error
+	var err6 error
+	var ips []net.Address
+	var ip6 []net.Address
+
+	if option.IPv4Enable {
+		ips, err4 = getIPs(recA, ttlA)
+	}
+
+	if option.IPv6Enable {
+		ip6, err6 = getIPs(recAAAA, ttlAAAA)
+		ips = append(ips, ip6...)
+	}
+
+	hits := atomic.AddUint32(&rec.hits, 1)
+	refresh := stale || (expiring && hits >= prefetchHits)
+
+	if len(ips) > 0 {
+		netIPs, err := toNetIP(ips)
+		return netIPs, refresh, err
+	}
+
+	if err4 != nil {
+		return nil, false, err4
+	}
+
+	if err6 != nil {
+		return nil, false, err6
+	}
+
+	if (option.IPv4Enable && recA != nil) || (option.IPv6Enable && recAAAA != nil) {
+		return nil, refresh, dns_feature.ErrEmptyResponse
+	}
+
+	return nil, false, errRecordNotFound
+}
+
+// refresh queries the domain in background with query to update its records,
+// unless a refresh of the domain is in progress.
+func (c *CacheController) refresh(ctx context.Context, domain string, option dns_feature.IPOption, query func(context.Context)) {
+	c.Lock()
+	if start, found := c.refreshing[domain]; found && time.Since(start) < refreshTimeout {
+		c.Unlock()
+		return
+	}
+	c.refreshing[domain] = time.Now()
+	c.Unlock()
+
+	errors.LogDebug(ctx, c.name, " refreshing ", domain, " in background")
+	var subs []*pubsub.Subscriber
+	if option.IPv4Enable {
+		subs = append(subs, c.pub.Subscribe(domain+"4"))
+	}
+	if option.IPv6Enable {
+		subs = append(subs, c.pub.Subscribe(domain+"6"))
+	}
+	detached := context.Background()
+	if core.FromContext(ctx) != nil {
+		detached = core.ToBackgroundDetachedContext(ctx)
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(detached, refreshTimeout)
+		defer cancel()
+		query(ctx)
+		for _, sub := range subs {
+			select {
+			case <-sub.Wait():
+			case <-ctx.Done():
+			}
+			sub.Close()
+		}
+	}()
+}
+
+// cached returns whether the domain can be answered from the cache.
+func (c *CacheController) cached(domain string) bool {
+	c.RLock()
+	defer c.RUnlock()
+	rec, found := c.ips[domain]
+	if !found {
+		return false
+	}
+	deadline := time.Now().Add(-c.staleMaxAge())
+	return (rec.A != nil && rec.A.Expire.After(deadline)) || (rec.AAAA != nil && rec.AAAA.Expire.After(deadline))
+}
+
+// entries returns the cached answers of the domain, or all if domain is empty.
+func (c *CacheController) entries(domain string) []*dns_feature.CacheEntry {
+	c.RLock()
+	defer c.RUnlock()
+	var entries []*dns_feature.CacheEntry
+	appendEntry := func(domain string, reqType string, r *IPRecord) {
+		if r == nil {
+			return
+		}
+		ips, _ := toNetIP(r.IP)
+		entries = append(entries, &dns_feature.CacheEntry{
+			Server: c.name,
+			Domain: strings.TrimSuffix(domain, "."),
+			Type:   reqType,
+			IPs:    ips,
+			Expire: r.Expire,
+			RCode:  uint16(r.RCode),
+		})
+	}
+	for d, rec := range c.ips {
+		if domain != "" && d != Fqdn(domain) {
+			continue
+		}
+		appendEntry(d, "A", rec.A)
+		appendEntry(d, "AAAA", rec.AAAA)
+	}
+	return entries
+}
+
+// flush removes the cached answers of the domain, or all if domain is empty,
+// and returns the number of domains removed.
+func (c *CacheController) flush(domain string) int {
+	c.Lock()
+	defer c.Unlock()
+	if domain == "" {
+		n := len(c.ips)
+		c.ips = make(map[string]*record)
+		return n
+	}
+	if _, found := c.ips[Fqdn(domain)]; !found {
+		return 0
+	}
+	delete(c.ips, Fqdn(domain))
+	return 1
+}
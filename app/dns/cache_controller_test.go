@@ -0,0 +1,142 @@
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func cacheRecord(c *CacheController, domain string, ttl time.Duration, ip string) {
+	c.updateIP(&dnsRequest{
+		reqType: dnsmessage.TypeA,
+		domain:  domain,
+		start:   time.Now(),
+	}, &IPRecord{
+		IP:     []net.Address{net.ParseAddress(ip)},
+		Expire: time.Now().Add(ttl),
+		RCode:  dnsmessage.RCodeSuccess,
+	})
+}
+
+func TestCacheControllerServeStale(t *testing.T) {
+	option := dns_feature.IPOption{IPv4Enable: true}
+
+	c := NewCacheController("test")
+	c.SetCacheOptions(CacheOptions{ServeStale: true, StaleMaxAge: time.Minute})
+	cacheRecord(c, "expired.example.", -time.Second, "1.2.3.4")
+	ips, refresh, err := c.findIPsForDomain("expired.example.", option)
+	if err != nil || len(ips) != 1 || ips[0].String() != "1.2.3.4" {
+		t.Errorf("expected stale record, actual %v, %v", ips, err)
+	}
+	if !refresh {
+		t.Error("expected stale record refreshed")
+	}
+	if _, err := c.findFreshIPsForDomain("expired.example.", option); err != errRecordNotFound {
+		t.Errorf("expected stale record not found waiting for answers, actual %v", err)
+	}
+
+	cacheRecord(c, "old.example.", -2*time.Minute, "1.2.3.4")
+	if _, _, err := c.findIPsForDomain("old.example.", option); err != errRecordNotFound {
+		t.Errorf("expected record beyond stale age not found, actual %v", err)
+	}
+
+	c.SetCacheOptions(CacheOptions{})
+	if _, _, err := c.findIPsForDomain("expired.example.", option); err != errRecordNotFound {
+		t.Errorf("expected expired record not found without serve-stale, actual %v", err)
+	}
+}
+
+func TestCacheControllerPrefetch(t *testing.T) {
+	option := dns_feature.IPOption{IPv4Enable: true}
+	c := NewCacheController("test")
+	c.SetCacheOptions(CacheOptions{Prefetch: true})
+
+	cacheRecord(c, "fresh.example.", time.Hour, "1.2.3.4")
+	for i := 0; i < 3; i++ {
+		if _, refresh, _ := c.findIPsForDomain("fresh.example.", option); refresh {
+			t.Error("fresh record refreshed")
+		}
+	}
+
+	cacheRecord(c, "expiring.example.", time.Hour, "1.2.3.4")
+	c.ips["expiring.example."].A.Expire = time.Now().Add(time.Minute)
+	if _, refresh, _ := c.findIPsForDomain("expiring.example.", option); refresh {
+		t.Error("record hit once refreshed")
+	}
+	if _, refresh, _ := c.findIPsForDomain("expiring.example.", option); !refresh {
+		t.Error("hot record near expiry not refreshed")
+	}
+}
+
+func TestCacheControllerRefresh(t *testing.T) {
+	option := dns_feature.IPOption{IPv4Enable: true}
+	c := NewCacheController("test")
+	queries := make(chan struct{}, 2)
+	query := func(context.Context) {
+		queries <- struct{}{}
+	}
+
+	c.refresh(context.Background(), "example.", option, query)
+	// a refresh in progress is not repeated
+	c.refresh(context.Background(), "example.", option, query)
+	<-queries
+	cacheRecord(c, "example.", time.Hour, "5.6.7.8")
+	select {
+	case <-queries:
+		t.Error("duplicated refresh")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	ips, _, err := c.findIPsForDomain("example.", option)
+	if err != nil || ips[0].String() != "5.6.7.8" {
+		t.Errorf("expected refreshed record, actual %v, %v", ips, err)
+	}
+}
+
+func TestCacheControllerPersist(t *testing.T) {
+	option := dns_feature.IPOption{IPv4Enable: true}
+	c := NewCacheController("test")
+	cacheRecord(c, "example.", time.Hour, "1.2.3.4")
+	cacheRecord(c, "expired.example.", -time.Second, "1.2.3.4")
+
+	data, err := json.Marshal(c.snapshot())
+	common.Must(err)
+	var records map[string]*persistedRecord
+	common.Must(json.Unmarshal(data, &records))
+
+	restored := NewCacheController("test")
+	restored.restore(records)
+	ips, _, err := restored.findIPsForDomain("example.", option)
+	if err != nil || len(ips) != 1 || ips[0].String() != "1.2.3.4" {
+		t.Errorf("expected restored record, actual %v, %v", ips, err)
+	}
+	if _, found := restored.ips["expired.example."]; found {
+		t.Error("expired record restored")
+	}
+}
+
+func TestCacheControllerFlush(t *testing.T) {
+	c := NewCacheController("test")
+	cacheRecord(c, "example.com.", time.Hour, "1.2.3.4")
+	cacheRecord(c, "example.org.", time.Hour, "5.6.7.8")
+
+	entries := c.entries("example.com")
+	if len(entries) != 1 || entries[0].Domain != "example.com" || entries[0].Type != "A" || entries[0].IPs[0].String() != "1.2.3.4" {
+		t.Errorf("unexpected entries %v", entries)
+	}
+	if n := c.flush("example.com"); n != 1 {
+		t.Error("expected 1 domain flushed, actual ", n)
+	}
+	if n := len(c.entries("")); n != 1 {
+		t.Error("expected 1 entry left, actual ", n)
+	}
+	if n := c.flush(""); n != 1 {
+		t.Error("expected 1 domain flushed, actual ", n)
+	}
+}
@@ -0,0 +1,178 @@
+package dns
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/platform/filesystem"
+	"github.com/xtls/xray-core/common/task"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const defaultPersistInterval = time.Minute
+
+// persistedCache is the on-disk form of the caches, keyed by name server.
+type persistedCache struct {
+	Servers map[string]map[string]*persistedRecord `json:"servers"`
+}
+
+type persistedRecord struct {
+	A    *persistedIPRecord `json:"a,omitempty"`
+	AAAA *persistedIPRecord `json:"aaaa,omitempty"`
+}
+
+type persistedIPRecord struct {
+	IP     []string      `json:"ip"`
+	Expire time.Time     `json:"expire"`
+	RCode  uint16        `json:"rcode"`
+	TTL    time.Duration `json:"ttl"`
+}
+
+// cachePersister is implemented by name servers holding a CacheController.
+type cachePersister interface {
+	snapshot() map[string]*persistedRecord
+	restore(records map[string]*persistedRecord)
+}
+
+func toPersistedIPRecord(r *IPRecord, ttl time.Duration) *persistedIPRecord {
+	if r == nil {
+		return nil
+	}
+	ips := make([]string, 0, len(r.IP))
+	for _, ip := range r.IP {
+		ips = append(ips, ip.String())
+	}
+	return &persistedIPRecord{IP: ips, Expire: r.Expire, RCode: uint16(r.RCode), TTL: ttl}
+}
+
+func (r *persistedIPRecord) toIPRecord() *IPRecord {
+	if r == nil {
+		return nil
+	}
+	ips := make([]net.Address, 0, len(r.IP))
+	for _, ip := range r.IP {
+		if addr := net.ParseAddress(ip); addr.Family().IsIP() {
+			ips = append(ips, addr)
+		}
+	}
+	return &IPRecord{IP: ips, Expire: r.Expire, RCode: dnsmessage.RCode(r.RCode)}
+}
+
+// snapshot returns the cached records for persistence.
+func (c *CacheController) snapshot() map[string]*persistedRecord {
+	c.RLock()
+	defer c.RUnlock()
+	records := make(map[string]*persistedRecord, len(c.ips))
+	for domain, rec := range c.ips {
+		records[domain] = &persistedRecord{
+			A:    toPersistedIPRecord(rec.A, rec.ttlA),
+			AAAA: toPersistedIPRecord(rec.AAAA, rec.ttlAAAA),
+		}
+	}
+	return records
+}
+
+// restore caches the persisted records, except those not servable anymore.
+func (c *CacheController) restore(records map[string]*persistedRecord) {
+	c.Lock()
+	deadline := time.Now().Add(-c.staleMaxAge())
+	for domain, r := range records {
+		if _, found := c.ips[domain]; found {
+			continue
+		}
+		rec := &record{}
+		if r.A != nil && !r.A.Expire.Before(deadline) {
+			rec.A, rec.ttlA = r.A.toIPRecord(), r.A.TTL
+		}
+		if r.AAAA != nil && !r.AAAA.Expire.Before(deadline) {
+			rec.AAAA, rec.ttlAAAA = r.AAAA.toIPRecord(), r.AAAA.TTL
+		}
+		if rec.A != nil || rec.AAAA != nil {
+			c.ips[domain] = rec
+		}
+	}
+	empty := len(c.ips) == 0
+	c.Unlock()
+	if !empty {
+		common.Must(c.cleanup.Start())
+	}
+}
+
+// startPersist restores the caches saved before, and saves them periodically.
+func (s *DNS) startPersist() error {
+	if err := s.loadCache(); err != nil {
+		errors.LogWarningInner(s.ctx, err, "failed to restore DNS cache from ", s.persistPath)
+	}
+	interval := s.persistInterval
+	if interval <= 0 {
+		interval = defaultPersistInterval
+	}
+	s.persist = &task.Periodic{
+		Interval: interval,
+		Execute: func() error {
+			if err := s.saveCache(); err != nil {
+				errors.LogWarningInner(s.ctx, err, "failed to save DNS cache to ", s.persistPath)
+			}
+			return nil
+		},
+	}
+	return s.persist.Start()
+}
+
+// stopPersist stops saving periodically, and saves the caches for the last time.
+func (s *DNS) stopPersist() error {
+	if s.persist == nil {
+		return nil
+	}
+	s.persist.Close()
+	s.persist = nil
+	return s.saveCache()
+}
+
+func (s *DNS) loadCache() error {
+	data, err := filesystem.ReadFile(s.persistPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var cache persistedCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return err
+	}
+	for _, client := range s.clients {
+		if p, ok := client.server.(cachePersister); ok {
+			if records, found := cache.Servers[client.Name()]; found {
+				p.restore(records)
+			}
+		}
+	}
+	errors.LogInfo(s.ctx, "restored DNS cache from ", s.persistPath)
+	return nil
+}
+
+func (s *DNS) saveCache() error {
+	cache := persistedCache{Servers: make(map[string]map[string]*persistedRecord)}
+	for _, client := range s.clients {
+		if p, ok := client.server.(cachePersister); ok {
+			records := p.snapshot()
+			if existing, found := cache.Servers[client.Name()]; found {
+				// clients of the same name server share records
+				for domain, rec := range existing {
+					records[domain] = rec
+				}
+			}
+			cache.Servers[client.Name()] = records
+		}
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	return filesystem.WriteFileAtomic(s.persistPath, data)
+}
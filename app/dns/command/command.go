@@ -0,0 +1,152 @@
+package command
+
+import (
+	"context"
+	"strings"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/dns"
+	"google.golang.org/grpc"
+)
+
+// dnsServer is an implementation of DnsService.
+type dnsServer struct {
+	client dns.Client
+	v      *core.Instance
+}
+
+// NewDnsServer creates a DnsService server with the DNS client.
+func NewDnsServer(client dns.Client, v *core.Instance) DnsServiceServer {
+	return &dnsServer{
+		client: client,
+		v:      v,
+	}
+}
+
+func toIPOption(queryStrategy string, fakeEnable bool) (dns.IPOption, error) {
+	option := dns.IPOption{FakeEnable: fakeEnable}
+	switch strings.ToLower(queryStrategy) {
+	case "", "useip":
+		option.IPv4Enable, option.IPv6Enable = true, true
+	case "useipv4", "useip4":
+		option.IPv4Enable = true
+	case "useipv6", "useip6":
+		option.IPv6Enable = true
+	default:
+		return option, errors.New("unknown query strategy: ", queryStrategy)
+	}
+	return option, nil
+}
+
+func mapIPsToBytes(ips []net.IP) [][]byte {
+	var bytes [][]byte
+	for _, ip := range ips {
+		bytes = append(bytes, []byte(ip))
+	}
+	return bytes
+}
+
+func (s *dnsServer) Resolve(ctx context.Context, request *ResolveRequest) (*ResolveResponse, error) {
+	option, err := toIPOption(request.QueryStrategy, request.FakeEnable)
+	if err != nil {
+		return nil, err
+	}
+	resolver, ok := s.client.(dns.Resolver)
+	if !ok {
+		ips, err := s.client.LookupIP(request.Domain, option)
+		if err != nil {
+			return nil, err
+		}
+		return &ResolveResponse{Domain: request.Domain, Ips: mapIPsToBytes(ips)}, nil
+	}
+	resolution, err := resolver.Resolve(request.Domain, option)
+	if err != nil {
+		return nil, err
+	}
+	return &ResolveResponse{
+		Domain: resolution.Domain,
+		Ips:    mapIPsToBytes(resolution.IPs),
+		Server: resolution.Server,
+	}, nil
+}
+
+func (s *dnsServer) ListCache(ctx context.Context, request *ListCacheRequest) (*ListCacheResponse, error) {
+	cm, ok := s.client.(dns.CacheManager)
+	if !ok {
+		return nil, errors.New("unsupported DNS implementation")
+	}
+	resp := &ListCacheResponse{}
+	for _, entry := range cm.ListCache(request.Domain) {
+		resp.Entries = append(resp.Entries, &CacheEntry{
+			Server: entry.Server,
+			Domain: entry.Domain,
+			Type:   entry.Type,
+			Ips:    mapIPsToBytes(entry.IPs),
+			Expire: entry.Expire.Unix(),
+			Rcode:  uint32(entry.RCode),
+		})
+	}
+	return resp, nil
+}
+
+func (s *dnsServer) FlushCache(ctx context.Context, request *FlushCacheRequest) (*FlushCacheResponse, error) {
+	cm, ok := s.client.(dns.CacheManager)
+	if !ok {
+		return nil, errors.New("unsupported DNS implementation")
+	}
+	return &FlushCacheResponse{Flushed: uint32(cm.FlushCache(request.Domain))}, nil
+}
+
+func (s *dnsServer) AddHosts(ctx context.Context, request *AddHostsRequest) (*AddHostsResponse, error) {
+	hm, ok := s.client.(dns.HostsManager)
+	if !ok {
+		return nil, errors.New("unsupported DNS implementation")
+	}
+	addrs := make([]net.Address, 0, len(request.Addresses))
+	for _, addr := range request.Addresses {
+		addrs = append(addrs, net.ParseAddress(addr))
+	}
+	return &AddHostsResponse{}, hm.AddHosts(request.Domain, addrs)
+}
+
+func (s *dnsServer) RemoveHosts(ctx context.Context, request *RemoveHostsRequest) (*RemoveHostsResponse, error) {
+	hm, ok := s.client.(dns.HostsManager)
+	if !ok {
+		return nil, errors.New("unsupported DNS implementation")
+	}
+	return &RemoveHostsResponse{}, hm.RemoveHosts(request.Domain)
+}
+
+func (s *dnsServer) LookupFakeDNS(ctx context.Context, request *LookupFakeDNSRequest) (*LookupFakeDNSResponse, error) {
+	fakedns, ok := s.v.GetFeature((*dns.FakeDNSEngine)(nil)).(dns.FakeDNSEngine)
+	if !ok {
+		return nil, errors.New("FakeDNS not enabled")
+	}
+	ip := net.IPAddress(request.Ip)
+	if ip == nil {
+		return nil, errors.New("invalid IP: ", request.Ip)
+	}
+	return &LookupFakeDNSResponse{Domain: fakedns.GetDomainFromFakeDNS(ip)}, nil
+}
+
+func (s *dnsServer) mustEmbedUnimplementedDnsServiceServer() {}
+
+type service struct {
+	v *core.Instance
+}
+
+func (s *service) Register(server *grpc.Server) {
+	common.Must(s.v.RequireFeatures(func(client dns.Client) {
+		RegisterDnsServiceServer(server, NewDnsServer(client, s.v))
+	}, false))
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := core.MustFromContext(ctx)
+		return &service{v: s}, nil
+	}))
+}
@@ -0,0 +1,99 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xtls/xray-core/app/dns"
+	. "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/app/dns/fakedns"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/core"
+	feature_dns "github.com/xtls/xray-core/features/dns"
+)
+
+func newTestServer(t *testing.T) (DnsServiceServer, *core.Instance) {
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dns.Config{
+				StaticHosts: []*dns.Config_HostMapping{{
+					Type:   dns.DomainMatchingType_Full,
+					Domain: "example.com",
+					Ip:     [][]byte{{1, 2, 3, 4}},
+				}},
+			}),
+			serial.ToTypedMessage(&fakedns.FakeDnsPoolMulti{
+				Pools: []*fakedns.FakeDnsPool{{
+					IpPool:  feature_dns.FakeIPv4Pool,
+					LruSize: 256,
+				}},
+			}),
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	t.Cleanup(func() { v.Close() })
+
+	client := v.GetFeature(feature_dns.ClientType()).(feature_dns.Client)
+	return NewDnsServer(client, v), v
+}
+
+func TestDnsServiceHosts(t *testing.T) {
+	s, _ := newTestServer(t)
+	ctx := context.Background()
+
+	resp, err := s.Resolve(ctx, &ResolveRequest{Domain: "example.com", QueryStrategy: "UseIPv4"})
+	common.Must(err)
+	if resp.Server != "hosts" {
+		t.Error("expected answered by hosts, actual ", resp.Server)
+	}
+	if r := cmp.Diff(resp.Ips, [][]byte{{1, 2, 3, 4}}); r != "" {
+		t.Error(r)
+	}
+
+	// runtime hosts take precedence over configured ones
+	_, err = s.AddHosts(ctx, &AddHostsRequest{Domain: "example.com", Addresses: []string{"5.6.7.8"}})
+	common.Must(err)
+	resp, err = s.Resolve(ctx, &ResolveRequest{Domain: "example.com", QueryStrategy: "UseIPv4"})
+	common.Must(err)
+	if r := cmp.Diff(resp.Ips, [][]byte{{5, 6, 7, 8}}); r != "" {
+		t.Error(r)
+	}
+
+	_, err = s.RemoveHosts(ctx, &RemoveHostsRequest{Domain: "example.com"})
+	common.Must(err)
+	resp, err = s.Resolve(ctx, &ResolveRequest{Domain: "example.com", QueryStrategy: "UseIPv4"})
+	common.Must(err)
+	if r := cmp.Diff(resp.Ips, [][]byte{{1, 2, 3, 4}}); r != "" {
+		t.Error(r)
+	}
+
+	if _, err := s.RemoveHosts(ctx, &RemoveHostsRequest{Domain: "example.com"}); err == nil {
+		t.Error("expected error removing configured hosts")
+	}
+	if _, err := s.Resolve(ctx, &ResolveRequest{Domain: "example.com", QueryStrategy: "UseIPv5"}); err == nil {
+		t.Error("expected error of unknown query strategy")
+	}
+}
+
+func TestDnsServiceFakeDNS(t *testing.T) {
+	s, v := newTestServer(t)
+	ctx := context.Background()
+
+	engine := v.GetFeature((*feature_dns.FakeDNSEngine)(nil)).(feature_dns.FakeDNSEngine)
+	ip := engine.GetFakeIPForDomain("fake.example.com")[0]
+
+	resp, err := s.LookupFakeDNS(ctx, &LookupFakeDNSRequest{Ip: ip.IP()})
+	common.Must(err)
+	if resp.Domain != "fake.example.com" {
+		t.Error("expected domain fake.example.com, actual ", resp.Domain)
+	}
+
+	resp, err = s.LookupFakeDNS(ctx, &LookupFakeDNSRequest{Ip: []byte{1, 2, 3, 4}})
+	common.Must(err)
+	if resp.Domain != "" {
+		t.Error("expected no domain, actual ", resp.Domain)
+	}
+}
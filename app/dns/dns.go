@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/xtls/xray-core/app/router"
 	"github.com/xtls/xray-core/common"
@@ -13,6 +14,7 @@ import (
 	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/common/strmatcher"
+	"github.com/xtls/xray-core/common/task"
 	"github.com/xtls/xray-core/features/dns"
 )
 
@@ -29,6 +31,15 @@ type DNS struct {
 	ctx                    context.Context
 	domainMatcher          strmatcher.IndexMatcher
 	matcherInfos           []*DomainMatcherInfo
+	persistPath            string
+	persistInterval        time.Duration
+	persist                *task.Periodic
+	parallelQuery          ParallelQuery
+	parallelLimit          uint32
+	rules                  []*responseRule
+	hostsFiles             []string
+	hostsReloadInterval    time.Duration
+	hostsReload            *task.Periodic
 }
 
 // DomainMatcherInfo contains information attached to index returned by Server.domainMatcher
@@ -80,6 +91,11 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 	if err != nil {
 		return nil, errors.New("failed to create hosts").Base(err)
 	}
+	if len(config.HostsFile) > 0 {
+		if err := hosts.LoadFiles(config.HostsFile); err != nil {
+			return nil, errors.New("failed to load hosts files").Base(err)
+		}
+	}
 
 	clients := []*Client{}
 	domainRuleCount := 0
@@ -92,6 +108,20 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 	domainMatcher := &strmatcher.MatcherGroup{}
 	geoipContainer := router.GeoIPMatcherContainer{}
 
+	cacheOptions := CacheOptions{
+		Prefetch:    config.Prefetch,
+		ServeStale:  config.ServeStale,
+		StaleMaxAge: time.Duration(config.StaleMaxAge),
+	}
+
+	var anchors trustAnchors
+	if config.Dnssec {
+		anchors, err = parseTrustAnchors(config.DnssecTrustAnchor)
+		if err != nil {
+			return nil, errors.New("failed to create DNSSEC trust anchors").Base(err)
+		}
+	}
+
 	for _, ns := range config.NameServer {
 		clientIdx := len(clients)
 		updateDomain := func(domainRule strmatcher.Matcher, originalRuleIdx int, matcherInfos []*DomainMatcherInfo) error {
@@ -108,7 +138,7 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 		case net.IPv4len, net.IPv6len:
 			myClientIP = net.IP(ns.ClientIp)
 		}
-		client, err := NewClient(ctx, ns, myClientIP, geoipContainer, &matcherInfos, updateDomain)
+		client, err := NewClient(ctx, ns, myClientIP, geoipContainer, &matcherInfos, updateDomain, cacheOptions, anchors)
 		if err != nil {
 			return nil, errors.New("failed to create client").Base(err)
 		}
@@ -120,6 +150,11 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 		clients = append(clients, NewLocalDNSClient())
 	}
 
+	rules, err := newResponseRules(config.ResponseRule, &geoipContainer)
+	if err != nil {
+		return nil, errors.New("failed to create response rules").Base(err)
+	}
+
 	return &DNS{
 		tag:                    tag,
 		hosts:                  hosts,
@@ -131,6 +166,13 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 		disableCache:           config.DisableCache,
 		disableFallback:        config.DisableFallback,
 		disableFallbackIfMatch: config.DisableFallbackIfMatch,
+		persistPath:            config.PersistPath,
+		persistInterval:        time.Duration(config.PersistInterval),
+		parallelQuery:          config.ParallelQuery,
+		parallelLimit:          config.ParallelLimit,
+		rules:                  rules,
+		hostsFiles:             config.HostsFile,
+		hostsReloadInterval:    time.Duration(config.HostsReloadInterval),
 	}, nil
 }
 
@@ -141,11 +183,25 @@ func (*DNS) Type() interface{} {
 
 // Start implements common.Runnable.
 func (s *DNS) Start() error {
+	if len(s.hostsFiles) > 0 {
+		if err := s.startWatchFiles(); err != nil {
+			return err
+		}
+	}
+	if s.persistPath != "" {
+		return s.startPersist()
+	}
 	return nil
 }
 
 // Close implements common.Closable.
 func (s *DNS) Close() error {
+	if s.hostsReload != nil {
+		s.hostsReload.Close()
+	}
+	if err := s.stopPersist(); err != nil {
+		errors.LogWarningInner(s.ctx, err, "failed to save DNS cache to ", s.persistPath)
+	}
 	return nil
 }
 
@@ -157,6 +213,15 @@ func (s *DNS) IsOwnLink(ctx context.Context) bool {
 
 // LookupIP implements dns.Client.
 func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, error) {
+	resolution, err := s.Resolve(domain, option)
+	if err != nil {
+		return nil, err
+	}
+	return resolution.IPs, nil
+}
+
+// Resolve implements dns.Resolver.
+func (s *DNS) Resolve(domain string, option dns.IPOption) (*dns.Resolution, error) {
 	if domain == "" {
 		return nil, errors.New("empty domain name")
 	}
@@ -182,20 +247,50 @@ func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, error) {
 		domain = addrs[0].Domain()
 	default: // Successfully found ip records in static host
 		errors.LogInfo(s.ctx, "returning ", len(addrs), " IP(s) for domain ", domain, " -> ", addrs)
-		return toNetIP(addrs)
+		ips, err := toNetIP(addrs)
+		if err != nil {
+			return nil, err
+		}
+		return &dns.Resolution{Domain: domain, IPs: ips, Server: hostsServerName}, nil
+	}
+
+	// Response rules
+	queried := domain
+	domain, option, resolution, err := s.applyQueryRules(domain, option)
+	if resolution != nil || err != nil {
+		return resolution, err
 	}
 
-	// Name servers lookup
+	resolution, err = s.lookupServers(domain, option)
+	if err != nil || len(s.rules) == 0 {
+		return resolution, err
+	}
+	resolution.Rewritten = domain != queried
+	if resolution.IPs, err = s.applyAnswerRules(queried, resolution.IPs); err != nil {
+		return nil, err
+	}
+	return resolution, nil
+}
+
+// lookupServers queries the name servers for domain.
+func (s *DNS) lookupServers(domain string, option dns.IPOption) (*dns.Resolution, error) {
 	errs := []error{}
 	ctx := session.ContextWithInbound(s.ctx, &session.Inbound{Tag: s.tag})
+	clients := make([]*Client, 0, len(s.clients))
 	for _, client := range s.sortClients(domain) {
 		if !option.FakeEnable && strings.EqualFold(client.Name(), "FakeDNS") {
 			errors.LogDebug(s.ctx, "skip DNS resolution for domain ", domain, " at server ", client.Name())
 			continue
 		}
+		clients = append(clients, client)
+	}
+	if s.parallelQuery != ParallelQuery_SEQUENTIAL {
+		return s.lookupParallel(ctx, domain, clients, option)
+	}
+	for _, client := range clients {
 		ips, err := client.QueryIP(ctx, domain, option, s.disableCache)
 		if len(ips) > 0 {
-			return ips, nil
+			return &dns.Resolution{Domain: domain, IPs: ips, Server: client.Name()}, nil
 		}
 		if err != nil {
 			errors.LogInfoInner(s.ctx, err, "failed to lookup ip for domain ", domain, " at server ", client.Name())
@@ -203,6 +298,9 @@ func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, error) {
 		}
 		// 5 for RcodeRefused in miekg/dns, hardcode to reduce binary size
 		if err != context.Canceled && err != context.DeadlineExceeded && err != errExpectedIPNonMatch && err != dns.ErrEmptyResponse && dns.RCodeFromError(err) != 5 {
+			if err == nil {
+				return &dns.Resolution{Domain: domain, Server: client.Name()}, nil
+			}
 			return nil, err
 		}
 	}
@@ -226,6 +324,50 @@ func (s *DNS) LookupHosts(domain string) *net.Address {
 	return nil
 }
 
+// hostsServerName is the server name of answers from static hosts.
+const hostsServerName = "hosts"
+
+// cacheInspector is implemented by name servers holding a CacheController.
+type cacheInspector interface {
+	entries(domain string) []*dns.CacheEntry
+	flush(domain string) int
+}
+
+// ListCache implements dns.CacheManager.
+func (s *DNS) ListCache(domain string) []*dns.CacheEntry {
+	domain = strings.TrimSuffix(domain, ".")
+	var entries []*dns.CacheEntry
+	for _, client := range s.clients {
+		if c, ok := client.server.(cacheInspector); ok {
+			entries = append(entries, c.entries(domain)...)
+		}
+	}
+	return entries
+}
+
+// FlushCache implements dns.CacheManager.
+func (s *DNS) FlushCache(domain string) int {
+	domain = strings.TrimSuffix(domain, ".")
+	flushed := 0
+	for _, client := range s.clients {
+		if c, ok := client.server.(cacheInspector); ok {
+			flushed += c.flush(domain)
+		}
+	}
+	errors.LogInfo(s.ctx, "flushed ", flushed, " cached domains")
+	return flushed
+}
+
+// AddHosts implements dns.HostsManager.
+func (s *DNS) AddHosts(domain string, addrs []net.Address) error {
+	return s.hosts.AddHosts(strings.TrimSuffix(domain, "."), addrs)
+}
+
+// RemoveHosts implements dns.HostsManager.
+func (s *DNS) RemoveHosts(domain string) error {
+	return s.hosts.RemoveHosts(strings.TrimSuffix(domain, "."))
+}
+
 // GetIPOption implements ClientWithIPOption.
 func (s *DNS) GetIPOption() *dns.IPOption {
 	return s.ipOption
@@ -266,12 +408,19 @@ func (s *DNS) sortClients(domain string) []*Client {
 
 	if !(s.disableFallback || s.disableFallbackIfMatch && hasMatch) {
 		// Default round-robin query
+		matched := len(clients)
 		for idx, client := range s.clients {
 			if clientUsed[idx] || client.skipFallback {
 				continue
 			}
 			clientUsed[idx] = true
 			clients = append(clients, client)
+		}
+		// Fallback servers are ordered by latency when queried concurrently
+		if s.parallelQuery != ParallelQuery_SEQUENTIAL {
+			sortByLatency(clients[matched:])
+		}
+		for _, client := range clients[matched:] {
 			clientNames = append(clientNames, client.Name())
 		}
 	}
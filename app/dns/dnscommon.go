@@ -3,10 +3,12 @@ package dns
 import (
 	"context"
 	"encoding/binary"
+	"io"
 	"strings"
 	"time"
 
 	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/log"
 	"github.com/xtls/xray-core/common/net"
@@ -27,6 +29,12 @@ func Fqdn(domain string) string {
 type record struct {
 	A    *IPRecord
 	AAAA *IPRecord
+
+	// TTLs of the records when they are cached
+	ttlA    time.Duration
+	ttlAAAA time.Duration
+	// hits counts cache hits since the records are updated
+	hits uint32
 }
 
 // IPRecord is a cacheable item for a resolved domain
@@ -65,6 +73,8 @@ type dnsRequest struct {
 	start   time.Time
 	expire  time.Time
 	msg     *dnsmessage.Message
+	// response receives the raw response of a request sent by exchange.
+	response chan []byte
 }
 
 func genEDNS0Options(clientIP net.IP) *dnsmessage.Resource {
@@ -230,6 +240,32 @@ L:
 	return ipRecord, nil
 }
 
+// writeStreamMessage writes the DNS message prefixed with its length, as
+// described in RFC 1035 4.2.2.
+func writeStreamMessage(w io.Writer, msg []byte) error {
+	b := buf.New()
+	defer b.Release()
+	binary.Write(b, binary.BigEndian, uint16(len(msg)))
+	b.Write(msg)
+	if _, err := w.Write(b.Bytes()); err != nil {
+		return errors.New("failed to send query").Base(err)
+	}
+	return nil
+}
+
+// readStreamMessage reads a DNS message prefixed with its length.
+func readStreamMessage(r io.Reader) ([]byte, error) {
+	var length uint16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, errors.New("failed to read response length").Base(err)
+	}
+	msg := make([]byte, length)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, errors.New("failed to read response").Base(err)
+	}
+	return msg, nil
+}
+
 // toDnsContext create a new background context with parent inbound, session and dns log
 func toDnsContext(ctx context.Context, addr string) context.Context {
 	dnsCtx := core.ToBackgroundDetachedContext(ctx)
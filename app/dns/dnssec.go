@@ -0,0 +1,710 @@
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// bogusTTL is the time a response failed validation is cached as SERVFAIL.
+	bogusTTL = 30 * time.Second
+	// maxTrustTTL is the maximum time the trust of a zone is cached.
+	maxTrustTTL = time.Hour
+)
+
+// rootTrustAnchors are the DS records of the root KSKs published by IANA.
+var rootTrustAnchors = []string{
+	". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBB683457104237C7F8EC8D",
+	". IN DS 38696 8 2 683D2D0ACB8C9B712A1948B27F741219298D0A450D612C483AF444A4C0FB2B16",
+}
+
+var errBogus = errors.New("DNSSEC validation failed")
+
+// trustAnchors are the DS records the chain of trust starts from.
+type trustAnchors []*dsRecord
+
+// parseTrustAnchors parses DS records in presentation format, or returns the
+// root trust anchors if anchors is empty.
+func parseTrustAnchors(anchors []string) (trustAnchors, error) {
+	if len(anchors) == 0 {
+		anchors = rootTrustAnchors
+	}
+	var ds trustAnchors
+	for _, anchor := range anchors {
+		d, err := parseDSText(anchor)
+		if err != nil {
+			return nil, errors.New("invalid trust anchor: ", anchor).Base(err)
+		}
+		ds = append(ds, d)
+	}
+	return ds, nil
+}
+
+// zoneTrust is the result of following the chain of trust to a name.
+type zoneTrust struct {
+	// zone is the name of the zone, empty if the name is not a zone cut.
+	zone string
+	// keys are the validated DNSKEYs of a signed zone.
+	keys []*dnskeyRecord
+	// insecure indicates an unsigned zone proven by its parent.
+	insecure bool
+	expire   time.Time
+}
+
+// dnssecValidator validates DNS responses of a name server, following the
+// chain of trust from the trust anchors with DS and DNSKEY queries sent to
+// the same name server.
+type dnssecValidator struct {
+	name     string
+	anchors  trustAnchors
+	exchange func(ctx context.Context, msg []byte) ([]byte, error)
+	now      func() time.Time
+
+	access sync.Mutex
+	trusts map[string]*zoneTrust
+}
+
+func newDNSSECValidator(name string, anchors trustAnchors, exchange func(context.Context, []byte) ([]byte, error)) *dnssecValidator {
+	return &dnssecValidator{
+		name:     name,
+		anchors:  anchors,
+		exchange: exchange,
+		now:      time.Now,
+		trusts:   make(map[string]*zoneTrust),
+	}
+}
+
+// reqOptions returns the EDNS0 options of queries, with the DO bit set when
+// validating, so that RRSIGs are answered.
+func (v *dnssecValidator) reqOptions(clientIP net.IP) *dnsmessage.Resource {
+	opt := genEDNS0Options(clientIP)
+	if opt == nil && v != nil {
+		opt = &dnsmessage.Resource{Body: &dnsmessage.OPTResource{}}
+		common.Must(opt.Header.SetEDNS0(1350, 0xfe00, true))
+	}
+	return opt
+}
+
+// check validates the response rec is parsed from, and returns a SERVFAIL
+// record instead if it is bogus.
+func (v *dnssecValidator) check(ctx context.Context, payload []byte, rec *IPRecord) *IPRecord {
+	if v == nil {
+		return rec
+	}
+	if err := v.validate(ctx, payload); err != nil {
+		errors.LogWarningInner(ctx, err, v.name, " got bogus answer")
+		return &IPRecord{
+			ReqID:  rec.ReqID,
+			RCode:  dnsmessage.RCodeServerFailure,
+			Expire: time.Now().Add(bogusTTL),
+		}
+	}
+	return rec
+}
+
+// validate authenticates the answer of a response, or the denial in its
+// authority section if there is no answer.
+func (v *dnssecValidator) validate(ctx context.Context, payload []byte) error {
+	msg, err := parseMessage(payload)
+	if err != nil {
+		return err
+	}
+	if msg.header.Truncated {
+		return errors.New("truncated response").Base(errBogus)
+	}
+	if msg.header.RCode != dnsmessage.RCodeSuccess && msg.header.RCode != dnsmessage.RCodeNameError {
+		return nil
+	}
+	if len(msg.answer) == 0 {
+		return v.validateDenial(ctx, msg)
+	}
+
+	sets, sigs := groupRRsets(msg.answer)
+	for key, rrset := range sets {
+		sig, err := v.verifyOwned(ctx, key.name, rrset, sigs[key])
+		if err == nil && sig != nil && isExpanded(key.name, sig) {
+			err = v.verifyExpansion(ctx, key.name, sig, msg.ns)
+		}
+		if err != nil {
+			return errors.New("invalid ", typeString(key.rrtype), " records of ", key.name).Base(err)
+		}
+	}
+	return nil
+}
+
+// validateDenial authenticates a response without answer, which must prove
+// the nonexistence of the name or the type queried by NSEC or NSEC3 records
+// in a signed zone.
+func (v *dnssecValidator) validateDenial(ctx context.Context, msg *message) error {
+	if len(msg.questions) == 0 {
+		return errors.New("no question").Base(errBogus)
+	}
+	q := msg.questions[0]
+	name := canonicalName(q.Name.String())
+	qtype := uint16(q.Type)
+	trust, err := v.trustOf(ctx, name)
+	if err != nil {
+		return err
+	}
+	if trust.insecure {
+		return nil
+	}
+
+	nsecs, nsec3s, err := v.denialProofs(ctx, msg.ns, trust.zone)
+	if err != nil {
+		return err
+	}
+	nxdomain := msg.header.RCode == dnsmessage.RCodeNameError
+	if nsecProvesDenial(nsecs, name, qtype, nxdomain) || nsec3ProvesDenial(nsec3s, name, qtype, nxdomain) {
+		return nil
+	}
+	return errors.New("no proof of nonexistence of ", typeString(qtype), " records of ", name).Base(errBogus)
+}
+
+// denialProofs verifies the denial records in the authority section ns, and
+// returns the NSEC and NSEC3 records of zone among them. Denial records are
+// never expanded from a wildcard, so such signatures are bogus.
+func (v *dnssecValidator) denialProofs(ctx context.Context, ns []*resource, zone string) ([]*nsecRecord, []*nsec3Record, error) {
+	denial := denialRecords(ns)
+	sets, sigs := groupRRsets(denial)
+	for key, rrset := range sets {
+		sig, err := v.verifyOwned(ctx, key.name, rrset, sigs[key])
+		if err == nil && sig != nil && isExpanded(key.name, sig) {
+			err = errors.New("expanded from wildcard").Base(errBogus)
+		}
+		if err != nil {
+			return nil, nil, errors.New("invalid ", typeString(key.rrtype), " records of ", key.name).Base(err)
+		}
+	}
+	nsecs, nsec3s := nsecRecords(denial, zone)
+	return nsecs, nsec3s, nil
+}
+
+// verifyExpansion checks the records of name expanded from a wildcard by sig
+// come with the proof that no closer match of name exists, as required by
+// RFC 4035, section 5.3.4.
+func (v *dnssecValidator) verifyExpansion(ctx context.Context, name string, sig *rrsigRecord, ns []*resource) error {
+	nsecs, nsec3s, err := v.denialProofs(ctx, ns, sig.signer)
+	if err != nil {
+		return err
+	}
+	labels := splitLabels(name)
+	nextCloser := joinLabels(labels[len(labels)-int(sig.labels)-1:])
+	for _, r := range nsecs {
+		if r.covers(name) {
+			return nil
+		}
+	}
+	for _, r := range nsec3s {
+		if r.cover(nextCloser) {
+			return nil
+		}
+	}
+	return errors.New("no proof of nonexistence of ", name, " expanded from wildcard").Base(errBogus)
+}
+
+// isExpanded checks whether the records of name signed by sig are expanded
+// from a wildcard, of which the label count of sig is less than that of name.
+func isExpanded(name string, sig *rrsigRecord) bool {
+	labels := splitLabels(name)
+	n := len(labels)
+	if n > 0 && labels[0] == "*" {
+		// the asterisk label of a wildcard owner is not counted
+		n--
+	}
+	return int(sig.labels) < n
+}
+
+// verifyOwned verifies rrset owned by name with its signatures, and returns
+// the valid signature, or nil in an unsigned zone.
+func (v *dnssecValidator) verifyOwned(ctx context.Context, name string, rrset []*resource, sigs []*rrsigRecord) (*rrsigRecord, error) {
+	if len(sigs) == 0 {
+		// unsigned records are only valid in an unsigned zone
+		trust, err := v.trustOf(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if trust.insecure {
+			return nil, nil
+		}
+		return nil, errors.New("unsigned records in signed zone ", trust.zone).Base(errBogus)
+	}
+
+	signer := sigs[0].signer
+	if !isSubDomain(signer, name) {
+		return nil, errors.New("signer ", signer, " is not a parent").Base(errBogus)
+	}
+	trust, err := v.trustOf(ctx, signer)
+	if err != nil {
+		return nil, err
+	}
+	if trust.insecure {
+		return nil, nil
+	}
+	if trust.zone != signer {
+		return nil, errors.New("signer ", signer, " is not a signed zone").Base(errBogus)
+	}
+	return v.verifyRRset(rrset, sigs, trust.keys)
+}
+
+// trustOf follows the chain of trust to the closest zone enclosing name.
+func (v *dnssecValidator) trustOf(ctx context.Context, name string) (*zoneTrust, error) {
+	trust, err := v.cached(".", func() (*zoneTrust, error) {
+		keys, ttl, err := v.zoneKeys(ctx, ".", v.anchors)
+		if err != nil {
+			return nil, err
+		}
+		return &zoneTrust{zone: ".", keys: keys, expire: v.expire(ttl)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	labels := splitLabels(canonicalName(name))
+	for i := len(labels) - 1; i >= 0; i-- {
+		child := joinLabels(labels[i:])
+		parent := trust
+		t, err := v.cached(child, func() (*zoneTrust, error) {
+			return v.delegation(ctx, child, parent)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if t.insecure {
+			return t, nil
+		}
+		if t.zone != "" {
+			trust = t
+		}
+	}
+	return trust, nil
+}
+
+// cached returns the trust of name cached, or resolves it with resolve.
+func (v *dnssecValidator) cached(name string, resolve func() (*zoneTrust, error)) (*zoneTrust, error) {
+	name = canonicalName(name)
+	v.access.Lock()
+	trust, found := v.trusts[name]
+	v.access.Unlock()
+	if found && trust.expire.After(v.now()) {
+		return trust, nil
+	}
+
+	trust, err := resolve()
+	if err != nil {
+		return nil, err
+	}
+	v.access.Lock()
+	v.trusts[name] = trust
+	v.access.Unlock()
+	return trust, nil
+}
+
+func (v *dnssecValidator) expire(ttl uint32) time.Time {
+	return v.now().Add(min(time.Duration(ttl)*time.Second, maxTrustTTL))
+}
+
+// delegation checks whether name is a zone cut under the zone of parent
+// with its DS records. Unless the DS records are answered, the parent must
+// prove either an insecure delegation, or no zone cut at name.
+func (v *dnssecValidator) delegation(ctx context.Context, name string, parent *zoneTrust) (*zoneTrust, error) {
+	name = canonicalName(name)
+	resp, err := v.query(ctx, name, typeDS)
+	if err != nil {
+		return nil, err
+	}
+
+	sets, sigs := groupRRsets(resp.answer)
+	key := rrsetKey{name: name, rrtype: typeDS}
+	if rrset := sets[key]; len(rrset) > 0 {
+		if _, err := v.verifyRRset(rrset, sigs[key], parent.keys); err != nil {
+			return nil, errors.New("invalid DS records of ", name).Base(err)
+		}
+		var ds []*dsRecord
+		for _, rr := range rrset {
+			d, err := parseDS(rr.rdata)
+			if err != nil {
+				return nil, errors.New("invalid DS records of ", name).Base(err)
+			}
+			ds = append(ds, d)
+		}
+		keys, ttl, err := v.zoneKeys(ctx, name, ds)
+		if err != nil {
+			return nil, err
+		}
+		return &zoneTrust{zone: name, keys: keys, expire: v.expire(ttl)}, nil
+	}
+
+	// the denial of DS must be signed by the parent
+	denial := denialRecords(resp.ns)
+	sets, sigs = groupRRsets(denial)
+	ttl := uint32(maxTrustTTL / time.Second)
+	for key, rrset := range sets {
+		sig, err := v.verifyRRset(rrset, sigs[key], parent.keys)
+		if err == nil && isExpanded(key.name, sig) {
+			err = errors.New("expanded from wildcard").Base(errBogus)
+		}
+		if err != nil {
+			return nil, errors.New("invalid denial of DS records of ", name).Base(err)
+		}
+		ttl = min(ttl, rrset[0].ttl)
+	}
+	nsecs, nsec3s := nsecRecords(denial, parent.zone)
+	switch {
+	case provesInsecureDelegation(nsecs, nsec3s, name):
+		return &zoneTrust{zone: name, insecure: true, expire: v.expire(ttl)}, nil
+	case provesNoDelegation(nsecs, nsec3s, name):
+		return &zoneTrust{expire: v.expire(ttl)}, nil
+	}
+	return nil, errors.New("no proof of nonexistence of DS records of ", name).Base(errBogus)
+}
+
+// zoneKeys returns the DNSKEYs of zone, which must be signed by a key
+// matching one of ds.
+func (v *dnssecValidator) zoneKeys(ctx context.Context, zone string, ds []*dsRecord) ([]*dnskeyRecord, uint32, error) {
+	resp, err := v.query(ctx, zone, typeDNSKEY)
+	if err != nil {
+		return nil, 0, err
+	}
+	sets, sigs := groupRRsets(resp.answer)
+	key := rrsetKey{name: canonicalName(zone), rrtype: typeDNSKEY}
+	rrset := sets[key]
+	var keys []*dnskeyRecord
+	for _, rr := range rrset {
+		if k, err := parseDNSKEY(rr); err == nil {
+			keys = append(keys, k)
+		}
+	}
+
+	for _, k := range keys {
+		for _, d := range ds {
+			if !k.matches(d) {
+				continue
+			}
+			if _, err := v.verifyRRset(rrset, sigs[key], []*dnskeyRecord{k}); err == nil {
+				return keys, rrset[0].ttl, nil
+			}
+		}
+	}
+	return nil, 0, errors.New("no valid DNSKEY of ", zone, " matches DS records").Base(errBogus)
+}
+
+// verifyRRset checks rrset has a valid signature of keys, and returns it.
+func (v *dnssecValidator) verifyRRset(rrset []*resource, sigs []*rrsigRecord, keys []*dnskeyRecord) (*rrsigRecord, error) {
+	if len(sigs) == 0 {
+		return nil, errors.New("no signature").Base(errBogus)
+	}
+	now := v.now()
+	labels := len(splitLabels(rrset[0].name))
+	for _, sig := range sigs {
+		if !sig.validAt(now) || int(sig.labels) > labels {
+			continue
+		}
+		for _, k := range keys {
+			if k.flags&flagZone == 0 || k.owner != sig.signer || k.keyTag() != sig.keyTag || k.algorithm != sig.algorithm {
+				continue
+			}
+			if verifySignature(sig, k, rrset) == nil {
+				return sig, nil
+			}
+		}
+	}
+	return nil, errors.New("no valid signature").Base(errBogus)
+}
+
+// query sends a query with the DO bit set to the name server.
+func (v *dnssecValidator) query(ctx context.Context, name string, qtype uint16) (*message, error) {
+	req, err := packQuery(name, qtype, true)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := v.exchange(ctx, req)
+	if err != nil {
+		return nil, errors.New("failed to query ", typeString(qtype), " records of ", name).Base(err)
+	}
+	resp, err := parseMessage(payload)
+	if err != nil {
+		return nil, err
+	}
+	if resp.header.Truncated {
+		return nil, errors.New("truncated response of ", typeString(qtype), " records of ", name)
+	}
+	if resp.header.RCode != dnsmessage.RCodeSuccess && resp.header.RCode != dnsmessage.RCodeNameError {
+		return nil, dns_feature.RCodeError(resp.header.RCode)
+	}
+	return resp, nil
+}
+
+type rrsetKey struct {
+	name   string
+	rrtype uint16
+}
+
+// groupRRsets groups records into RRsets, with the signatures covering them.
+func groupRRsets(rrs []*resource) (map[rrsetKey][]*resource, map[rrsetKey][]*rrsigRecord) {
+	sets := make(map[rrsetKey][]*resource)
+	sigs := make(map[rrsetKey][]*rrsigRecord)
+	for _, rr := range rrs {
+		switch rr.rrtype {
+		case typeRRSIG:
+			sig, err := parseRRSIG(rr.rdata)
+			if err != nil {
+				continue
+			}
+			key := rrsetKey{name: rr.name, rrtype: sig.typeCovered}
+			sigs[key] = append(sigs[key], sig)
+		case typeOPT:
+		default:
+			key := rrsetKey{name: rr.name, rrtype: rr.rrtype}
+			sets[key] = append(sets[key], rr)
+		}
+	}
+	return sets, sigs
+}
+
+// denialRecords returns the records of authority section denying existence.
+func denialRecords(ns []*resource) []*resource {
+	var rrs []*resource
+	for _, rr := range ns {
+		switch rr.rrtype {
+		case typeSOA, typeNSEC, typeNSEC3:
+			rrs = append(rrs, rr)
+		case typeRRSIG:
+			if len(rr.rdata) < 2 {
+				continue
+			}
+			switch binary.BigEndian.Uint16(rr.rdata) {
+			case typeSOA, typeNSEC, typeNSEC3:
+				rrs = append(rrs, rr)
+			}
+		}
+	}
+	return rrs
+}
+
+// nsecRecords returns the NSEC and NSEC3 records of zone in rrs.
+func nsecRecords(rrs []*resource, zone string) ([]*nsecRecord, []*nsec3Record) {
+	var nsecs []*nsecRecord
+	var nsec3s []*nsec3Record
+	for _, rr := range rrs {
+		// only the records of the zone may deny names in it
+		if !isSubDomain(zone, rr.name) {
+			continue
+		}
+		switch rr.rrtype {
+		case typeNSEC:
+			if r, err := parseNSEC(rr); err == nil {
+				nsecs = append(nsecs, r)
+			}
+		case typeNSEC3:
+			if r, err := parseNSEC3(rr); err == nil {
+				nsec3s = append(nsec3s, r)
+			}
+		}
+	}
+	return nsecs, nsec3s
+}
+
+// hasType checks whether the type bitmap of NSEC or NSEC3 contains t.
+func hasType(bitmap []uint16, t uint16) bool {
+	return slices.Contains(bitmap, t)
+}
+
+// isDelegation checks whether the type bitmap is of a zone cut in the parent
+// zone, which denies nothing but DS of the name, and nothing below it.
+func isDelegation(bitmap []uint16) bool {
+	return hasType(bitmap, typeNS) && !hasType(bitmap, typeSOA)
+}
+
+// deniesType checks whether the type bitmap of name proves that qtype does not
+// exist at it.
+func deniesType(bitmap []uint16, qtype uint16) bool {
+	if hasType(bitmap, qtype) || hasType(bitmap, typeCNAME) {
+		return false
+	}
+	return qtype == typeDS || !isDelegation(bitmap)
+}
+
+// compareCanonical compares names in the canonical order of RFC 4034.
+func compareCanonical(a, b string) int {
+	la := splitLabels(canonicalName(a))
+	lb := splitLabels(canonicalName(b))
+	for i, j := len(la)-1, len(lb)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if c := strings.Compare(la[i], lb[j]); c != 0 {
+			return c
+		}
+	}
+	return len(la) - len(lb)
+}
+
+// commonAncestor returns the longest common ancestor of a and b.
+func commonAncestor(a, b string) string {
+	la := splitLabels(canonicalName(a))
+	lb := splitLabels(canonicalName(b))
+	n := 0
+	for n < len(la) && n < len(lb) && la[len(la)-1-n] == lb[len(lb)-1-n] {
+		n++
+	}
+	return joinLabels(la[len(la)-n:])
+}
+
+func wildcardOf(name string) string {
+	if name == "." {
+		return "*."
+	}
+	return "*." + name
+}
+
+// covers checks whether name is between the owner and the next name of r,
+// which may wrap around to the apex of the zone.
+func (r *nsecRecord) covers(name string) bool {
+	if isDelegation(r.types) && isSubDomain(r.owner, name) {
+		return false
+	}
+	if compareCanonical(r.owner, name) >= 0 {
+		return false
+	}
+	return compareCanonical(name, r.next) < 0 || compareCanonical(r.next, r.owner) <= 0
+}
+
+// nsecProvesDenial checks whether the NSEC records prove that name does not
+// exist if nxdomain, or it has no records of qtype otherwise, including the
+// wildcard at the closest encloser of name.
+func nsecProvesDenial(nsecs []*nsecRecord, name string, qtype uint16, nxdomain bool) bool {
+	if !nxdomain {
+		for _, r := range nsecs {
+			if r.owner == name {
+				return deniesType(r.types, qtype)
+			}
+		}
+	}
+	for _, r := range nsecs {
+		if !r.covers(name) {
+			continue
+		}
+		encloser := commonAncestor(name, r.owner)
+		if next := commonAncestor(name, r.next); len(next) > len(encloser) {
+			encloser = next
+		}
+		wildcard := wildcardOf(encloser)
+		for _, w := range nsecs {
+			if nxdomain && w.covers(wildcard) {
+				return true
+			}
+			if !nxdomain && w.owner == wildcard && deniesType(w.types, qtype) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nsec3ProvesDenial checks whether the NSEC3 records prove that name does not
+// exist if nxdomain, or it has no records of qtype otherwise, by the closest
+// encloser proof of RFC 5155.
+func nsec3ProvesDenial(nsec3s []*nsec3Record, name string, qtype uint16, nxdomain bool) bool {
+	if len(nsec3s) == 0 {
+		return false
+	}
+	if !nxdomain {
+		for _, r := range nsec3s {
+			if r.match(name) {
+				return deniesType(r.types, qtype)
+			}
+		}
+	}
+
+	matches := func(name string) *nsec3Record {
+		for _, r := range nsec3s {
+			if r.match(name) {
+				return r
+			}
+		}
+		return nil
+	}
+	covers := func(name string) *nsec3Record {
+		for _, r := range nsec3s {
+			if r.cover(name) {
+				return r
+			}
+		}
+		return nil
+	}
+	labels := splitLabels(name)
+	for i := 1; i <= len(labels); i++ {
+		encloser := joinLabels(labels[i:])
+		if matches(encloser) == nil {
+			continue
+		}
+		nextCloser := covers(joinLabels(labels[i-1:]))
+		if nextCloser == nil {
+			return false
+		}
+		wildcard := wildcardOf(encloser)
+		if nxdomain {
+			return covers(wildcard) != nil
+		}
+		if w := matches(wildcard); w != nil && deniesType(w.types, qtype) {
+			return true
+		}
+		// the next closer name in an opt-out span may be an insecure
+		// delegation, which has no DS records
+		return qtype == typeDS && nextCloser.flags&flagOptOut == flagOptOut
+	}
+	return false
+}
+
+// provesInsecureDelegation checks whether the NSEC or NSEC3 records prove
+// name is a zone cut without DS records, or is covered by an opt-out NSEC3.
+func provesInsecureDelegation(nsecs []*nsecRecord, nsec3s []*nsec3Record, name string) bool {
+	unsigned := func(types []uint16) bool {
+		return hasType(types, typeNS) && !hasType(types, typeDS) && !hasType(types, typeSOA)
+	}
+	for _, r := range nsecs {
+		if r.owner == name && unsigned(r.types) {
+			return true
+		}
+	}
+	for _, r := range nsec3s {
+		if r.match(name) && unsigned(r.types) {
+			return true
+		}
+		if r.cover(name) && r.flags&flagOptOut == flagOptOut {
+			return true
+		}
+	}
+	return false
+}
+
+// provesNoDelegation checks whether the NSEC or NSEC3 records prove name is
+// not a zone cut, as it has neither NS nor DS records, or it does not exist.
+func provesNoDelegation(nsecs []*nsecRecord, nsec3s []*nsec3Record, name string) bool {
+	for _, r := range nsecs {
+		if r.owner == name && !hasType(r.types, typeNS) && !hasType(r.types, typeDS) {
+			return true
+		}
+		if r.covers(name) {
+			return true
+		}
+	}
+	for _, r := range nsec3s {
+		if r.match(name) && !hasType(r.types, typeNS) && !hasType(r.types, typeDS) {
+			return true
+		}
+		// an opt-out span may hide an insecure delegation
+		if r.cover(name) && r.flags&flagOptOut == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,328 @@
+package dns
+
+import (
+	"context"
+	"crypto"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/xtls/xray-core/common"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+type signedZone struct {
+	name string
+	key  *dns.DNSKEY
+	priv crypto.Signer
+}
+
+func newSignedZone(name string) *signedZone {
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: name, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     dns.ZONE | dns.SEP,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	priv, err := key.Generate(256)
+	common.Must(err)
+	return &signedZone{name: name, key: key, priv: priv.(crypto.Signer)}
+}
+
+func (z *signedZone) sign(rrset ...dns.RR) []dns.RR {
+	sig := &dns.RRSIG{
+		Hdr:        dns.RR_Header{Name: rrset[0].Header().Name, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: 3600},
+		KeyTag:     z.key.KeyTag(),
+		SignerName: z.name,
+		Algorithm:  z.key.Algorithm,
+		Inception:  uint32(time.Now().Add(-time.Hour).Unix()),
+		Expiration: uint32(time.Now().Add(time.Hour).Unix()),
+	}
+	common.Must(sig.Sign(z.priv, rrset))
+	return append(rrset, sig)
+}
+
+// testAuthority answers queries of the validator from its records.
+type testAuthority struct {
+	answers map[rrsetKey][]dns.RR
+	denials map[string][]dns.RR
+}
+
+func (a *testAuthority) exchange(ctx context.Context, msg []byte) ([]byte, error) {
+	req := new(dns.Msg)
+	common.Must(req.Unpack(msg))
+	q := req.Question[0]
+	resp := new(dns.Msg)
+	resp.SetReply(req)
+	if rrs, found := a.answers[rrsetKey{name: q.Name, rrtype: q.Qtype}]; found {
+		resp.Answer = rrs
+	} else {
+		resp.Ns = a.denials[q.Name]
+	}
+	return resp.Pack()
+}
+
+func newTestAuthority() (*testAuthority, trustAnchors, *signedZone) {
+	root := newSignedZone(".")
+	example := newSignedZone("example.")
+
+	a := &testAuthority{
+		answers: map[rrsetKey][]dns.RR{
+			{name: ".", rrtype: dns.TypeDNSKEY}:        root.sign(root.key),
+			{name: "example.", rrtype: dns.TypeDNSKEY}: example.sign(example.key),
+			{name: "example.", rrtype: dns.TypeDS}:     root.sign(example.key.ToDS(dns.SHA256)),
+		},
+		denials: map[string][]dns.RR{
+			"insecure.": root.sign(&dns.NSEC{
+				Hdr:        dns.RR_Header{Name: "insecure.", Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 3600},
+				NextDomain: "zzz.",
+				TypeBitMap: []uint16{dns.TypeNS, dns.TypeRRSIG, dns.TypeNSEC},
+			}),
+			"nx.example.": example.sign(&dns.NSEC{
+				Hdr:        dns.RR_Header{Name: "example.", Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 3600},
+				NextDomain: "www.example.",
+				TypeBitMap: []uint16{dns.TypeNS, dns.TypeSOA, dns.TypeRRSIG, dns.TypeNSEC, dns.TypeDNSKEY},
+			}),
+			"www.example.": example.sign(&dns.NSEC{
+				Hdr:        dns.RR_Header{Name: "www.example.", Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 3600},
+				NextDomain: "zzz.example.",
+				TypeBitMap: []uint16{dns.TypeA, dns.TypeRRSIG, dns.TypeNSEC},
+			}),
+		},
+	}
+	anchors, err := parseTrustAnchors([]string{root.key.ToDS(dns.SHA256).String()})
+	common.Must(err)
+	return a, anchors, example
+}
+
+// parseTestRecord converts rr to the record validated.
+func parseTestRecord(rr dns.RR) *resource {
+	msg := new(dns.Msg)
+	msg.Answer = []dns.RR{rr}
+	b, err := msg.Pack()
+	common.Must(err)
+	m, err := parseMessage(b)
+	common.Must(err)
+	return m.answer[0]
+}
+
+func newAResponse(name string, ip [4]byte, sign func(...dns.RR) []dns.RR) []byte {
+	a := &dns.A{
+		Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300},
+		A:   ip[:],
+	}
+	resp := new(dns.Msg)
+	resp.SetQuestion(name, dns.TypeA)
+	resp.Response = true
+	resp.Answer = []dns.RR{a}
+	if sign != nil {
+		resp.Answer = sign(a)
+	}
+	b, err := resp.Pack()
+	common.Must(err)
+	return b
+}
+
+func newDenialResponse(name string, qtype uint16, rcode int, ns []dns.RR) []byte {
+	resp := new(dns.Msg)
+	resp.SetQuestion(name, qtype)
+	resp.Response = true
+	resp.Rcode = rcode
+	resp.Ns = ns
+	b, err := resp.Pack()
+	common.Must(err)
+	return b
+}
+
+func TestDNSSECValidate(t *testing.T) {
+	authority, anchors, example := newTestAuthority()
+	v := newDNSSECValidator("test", anchors, authority.exchange)
+	ctx := context.Background()
+
+	if err := v.validate(ctx, newAResponse("www.example.", [4]byte{1, 2, 3, 4}, example.sign)); err != nil {
+		t.Error("expected secure answer, got ", err)
+	}
+
+	if err := v.validate(ctx, newAResponse("host.insecure.", [4]byte{1, 2, 3, 4}, nil)); err != nil {
+		t.Error("expected insecure answer, got ", err)
+	}
+
+	if err := v.validate(ctx, newAResponse("mail.example.", [4]byte{1, 2, 3, 4}, nil)); err == nil {
+		t.Error("expected unsigned answer in signed zone to be bogus")
+	}
+
+	forged := newSignedZone("example.")
+	if err := v.validate(ctx, newAResponse("www.example.", [4]byte{1, 2, 3, 4}, forged.sign)); err == nil {
+		t.Error("expected answer signed by unknown key to be bogus")
+	}
+
+	rec := v.check(ctx, newAResponse("mail.example.", [4]byte{1, 2, 3, 4}, nil), &IPRecord{ReqID: 1})
+	if rec.RCode != dnsmessage.RCodeServerFailure || rec.ReqID != 1 {
+		t.Error("expected SERVFAIL record, got ", rec)
+	}
+}
+
+func TestDNSSECTrustAnchors(t *testing.T) {
+	anchors, err := parseTrustAnchors(nil)
+	common.Must(err)
+	if len(anchors) != len(rootTrustAnchors) {
+		t.Error("expected root trust anchors, got ", anchors)
+	}
+
+	if _, err := parseTrustAnchors([]string{". IN A 1.2.3.4"}); err == nil {
+		t.Error("expected error of non-DS trust anchor")
+	}
+}
+
+func TestDNSSECValidateDenial(t *testing.T) {
+	authority, anchors, example := newTestAuthority()
+	v := newDNSSECValidator("test", anchors, authority.exchange)
+	ctx := context.Background()
+
+	soa := example.sign(&dns.SOA{
+		Hdr:     dns.RR_Header{Name: "example.", Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 3600},
+		Ns:      "ns.example.",
+		Mbox:    "admin.example.",
+		Serial:  1,
+		Minttl:  300,
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  86400,
+	})
+	apexNSEC := authority.denials["nx.example."]
+	wwwNSEC := authority.denials["www.example."]
+
+	if err := v.validate(ctx, newDenialResponse("nx.example.", dns.TypeA, dns.RcodeNameError, append(soa, apexNSEC...))); err != nil {
+		t.Error("expected secure NXDOMAIN, got ", err)
+	}
+	if err := v.validate(ctx, newDenialResponse("www.example.", dns.TypeAAAA, dns.RcodeSuccess, append(soa, wwwNSEC...))); err != nil {
+		t.Error("expected secure NODATA, got ", err)
+	}
+	if err := v.validate(ctx, newDenialResponse("host.insecure.", dns.TypeA, dns.RcodeNameError, nil)); err != nil {
+		t.Error("expected insecure NXDOMAIN, got ", err)
+	}
+
+	if err := v.validate(ctx, newDenialResponse("www.example.", dns.TypeA, dns.RcodeSuccess, nil)); err == nil {
+		t.Error("expected stripped NOERROR to be bogus")
+	}
+	if err := v.validate(ctx, newDenialResponse("nx.example.", dns.TypeA, dns.RcodeNameError, nil)); err == nil {
+		t.Error("expected stripped NXDOMAIN to be bogus")
+	}
+	if err := v.validate(ctx, newDenialResponse("nx.example.", dns.TypeA, dns.RcodeNameError, soa)); err == nil {
+		t.Error("expected NXDOMAIN of replayed SOA to be bogus")
+	}
+	if err := v.validate(ctx, newDenialResponse("nx.example.", dns.TypeA, dns.RcodeNameError, append(soa, wwwNSEC...))); err == nil {
+		t.Error("expected NXDOMAIN of NSEC not covering the name to be bogus")
+	}
+	if err := v.validate(ctx, newDenialResponse("www.example.", dns.TypeA, dns.RcodeSuccess, append(soa, wwwNSEC...))); err == nil {
+		t.Error("expected NODATA of NSEC having the type to be bogus")
+	}
+
+	truncated := new(dns.Msg)
+	common.Must(truncated.Unpack(newAResponse("www.example.", [4]byte{1, 2, 3, 4}, example.sign)))
+	truncated.Truncated = true
+	b, err := truncated.Pack()
+	common.Must(err)
+	if err := v.validate(ctx, b); err == nil {
+		t.Error("expected truncated response to be bogus")
+	}
+}
+
+func TestNSEC3ProvesDenial(t *testing.T) {
+	nsec3 := func(owner string, next string, types ...uint16) *nsec3Record {
+		r, err := parseNSEC3(parseTestRecord(&dns.NSEC3{
+			Hdr:        dns.RR_Header{Name: dns.HashName(owner, dns.SHA1, 0, "") + ".example.", Rrtype: dns.TypeNSEC3, Class: dns.ClassINET},
+			Hash:       dns.SHA1,
+			NextDomain: dns.HashName(next, dns.SHA1, 0, ""),
+			HashLength: 20,
+			TypeBitMap: types,
+		}))
+		common.Must(err)
+		return r
+	}
+	// in the order of hashes: the apex, *.example., www.example., nx.example.
+	apex := "example."
+	records := []*nsec3Record{
+		nsec3(apex, "www.example.", dns.TypeNS, dns.TypeSOA),
+		nsec3("www.example.", apex, dns.TypeA),
+	}
+	if !nsec3ProvesDenial(records, "www.example.", dns.TypeAAAA, false) {
+		t.Error("expected NODATA proof")
+	}
+	if nsec3ProvesDenial(records, "www.example.", dns.TypeA, false) {
+		t.Error("unexpected NODATA proof of existing type")
+	}
+	if !nsec3ProvesDenial(records, "nx.example.", dns.TypeA, true) {
+		t.Error("expected NXDOMAIN proof")
+	}
+	if nsec3ProvesDenial(records[:1], "www.example.", dns.TypeAAAA, false) {
+		t.Error("unexpected NODATA proof without matching NSEC3")
+	}
+}
+
+func TestDNSSECWildcard(t *testing.T) {
+	authority, anchors, example := newTestAuthority()
+	v := newDNSSECValidator("test", anchors, authority.exchange)
+	ctx := context.Background()
+
+	// the answer of host.wild.example. expanded from *.wild.example.
+	answer := example.sign(&dns.A{
+		Hdr: dns.RR_Header{Name: "*.wild.example.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300},
+		A:   []byte{1, 2, 3, 4},
+	})
+	for _, rr := range answer {
+		rr.Header().Name = "host.wild.example."
+	}
+	nsec := example.sign(&dns.NSEC{
+		Hdr:        dns.RR_Header{Name: "*.wild.example.", Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 3600},
+		NextDomain: "zzz.example.",
+		TypeBitMap: []uint16{dns.TypeA, dns.TypeRRSIG, dns.TypeNSEC},
+	})
+	newResponse := func(ns []dns.RR) []byte {
+		resp := new(dns.Msg)
+		resp.SetQuestion("host.wild.example.", dns.TypeA)
+		resp.Response = true
+		resp.Answer = answer
+		resp.Ns = ns
+		b, err := resp.Pack()
+		common.Must(err)
+		return b
+	}
+
+	if err := v.validate(ctx, newResponse(nsec)); err != nil {
+		t.Error("expected secure wildcard answer, got ", err)
+	}
+	if err := v.validate(ctx, newResponse(nil)); err == nil {
+		t.Error("expected wildcard answer without proof of no closer match to be bogus")
+	}
+	if err := v.validate(ctx, newResponse(authority.denials["www.example."])); err == nil {
+		t.Error("expected wildcard answer with NSEC not covering the name to be bogus")
+	}
+}
+
+func TestDNSSECDelegation(t *testing.T) {
+	authority, anchors, example := newTestAuthority()
+	authority.denials["soa.example."] = example.sign(&dns.SOA{
+		Hdr:    dns.RR_Header{Name: "example.", Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 3600},
+		Ns:     "ns.example.",
+		Mbox:   "admin.example.",
+		Serial: 1,
+		Minttl: 300,
+	})
+	v := newDNSSECValidator("test", anchors, authority.exchange)
+	ctx := context.Background()
+
+	trust, err := v.trustOf(ctx, "www.example.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trust.zone != "example." || trust.insecure {
+		t.Error("expected trust of example., got ", trust.zone)
+	}
+	if trust, err := v.trustOf(ctx, "host.insecure."); err != nil || !trust.insecure {
+		t.Error("expected insecure delegation, got ", trust, err)
+	}
+	if _, err := v.trustOf(ctx, "host.soa.example."); err == nil {
+		t.Error("expected denial of DS records without NSEC to be bogus")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/xtls/xray-core/common/cache"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/task"
 	"github.com/xtls/xray-core/features/dns"
 )
 
@@ -19,6 +20,8 @@ type Holder struct {
 	domainToIP cache.Lru
 	ipRange    *gonet.IPNet
 	mu         *sync.Mutex
+	persist    *task.Periodic
+	dirty      bool
 
 	config *FakeDnsPool
 }
@@ -44,12 +47,21 @@ func (*Holder) Type() interface{} {
 
 func (fkdns *Holder) Start() error {
 	if fkdns.config != nil && fkdns.config.IpPool != "" && fkdns.config.LruSize != 0 {
-		return fkdns.initializeFromConfig()
+		if err := fkdns.initializeFromConfig(); err != nil {
+			return err
+		}
+		if fkdns.config.PersistPath != "" {
+			return fkdns.startPersist()
+		}
+		return nil
 	}
 	return errors.New("invalid fakeDNS setting")
 }
 
 func (fkdns *Holder) Close() error {
+	if err := fkdns.stopPersist(); err != nil {
+		errors.LogWarningInner(context.Background(), err, "failed to save fake DNS mapping to ", fkdns.config.PersistPath)
+	}
 	fkdns.domainToIP = nil
 	fkdns.ipRange = nil
 	fkdns.mu = nil
@@ -71,7 +83,7 @@ func NewFakeDNSHolder() (*Holder, error) {
 }
 
 func NewFakeDNSHolderConfigOnly(conf *FakeDnsPool) (*Holder, error) {
-	return &Holder{nil, nil, nil, conf}, nil
+	return &Holder{config: conf}, nil
 }
 
 func (fkdns *Holder) initializeFromConfig() error {
@@ -127,6 +139,7 @@ func (fkdns *Holder) GetFakeIPForDomain(domain string) []net.Address {
 		}
 	}
 	fkdns.domainToIP.Put(domain, ip)
+	fkdns.dirty = true
 	return []net.Address{ip}
 }
 
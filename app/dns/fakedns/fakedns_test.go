@@ -2,6 +2,7 @@ package fakedns
 
 import (
 	gonet "net"
+	"path/filepath"
 	"strconv"
 	"testing"
 
@@ -129,6 +130,37 @@ func TestFakeDnsHolderCreateMappingAndRollOver(t *testing.T) {
 	}
 }
 
+func TestFakeDnsHolderPersist(t *testing.T) {
+	config := &FakeDnsPool{
+		IpPool:      dns.FakeIPv4Pool,
+		LruSize:     256,
+		PersistPath: filepath.Join(t.TempDir(), "fakedns.json"),
+	}
+	fkdns, err := NewFakeDNSHolderConfigOnly(config)
+	common.Must(err)
+	common.Must(fkdns.Start())
+	addr := fkdns.GetFakeIPForDomain("fakednstest.example.com")
+	common.Must(fkdns.Close())
+
+	restarted, err := NewFakeDNSHolderConfigOnly(config)
+	common.Must(err)
+	common.Must(restarted.Start())
+	defer restarted.Close()
+	assert.Equal(t, "fakednstest.example.com", restarted.GetDomainFromFakeDNS(addr[0]))
+	assert.Equal(t, addr, restarted.GetFakeIPForDomain("fakednstest.example.com"))
+
+	// mapping of another pool is discarded
+	changed, err := NewFakeDNSHolderConfigOnly(&FakeDnsPool{
+		IpPool:      "240.0.0.0/12",
+		LruSize:     256,
+		PersistPath: config.PersistPath,
+	})
+	common.Must(err)
+	common.Must(changed.Start())
+	defer changed.Close()
+	assert.Equal(t, "", changed.GetDomainFromFakeDNS(addr[0]))
+}
+
 func TestFakeDNSMulti(t *testing.T) {
 	fakeMulti, err := NewFakeDNSHolderMulti(&FakeDnsPoolMulti{
 		Pools: []*FakeDnsPool{{
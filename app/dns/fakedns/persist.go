@@ -0,0 +1,118 @@
+package fakedns
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/platform/filesystem"
+	"github.com/xtls/xray-core/common/task"
+)
+
+const defaultPersistInterval = time.Minute
+
+// persistedPool is the on-disk form of the mapping of a fake IP pool.
+type persistedPool struct {
+	IPPool string `json:"ipPool"`
+	// Mappings are ordered from the least recently used one
+	Mappings []persistedMapping `json:"mappings"`
+}
+
+type persistedMapping struct {
+	Domain string `json:"domain"`
+	IP     string `json:"ip"`
+}
+
+// startPersist restores the mapping saved before, and saves it periodically.
+func (fkdns *Holder) startPersist() error {
+	if err := fkdns.load(); err != nil {
+		errors.LogWarningInner(context.Background(), err, "failed to restore fake DNS mapping from ", fkdns.config.PersistPath)
+	}
+	interval := time.Duration(fkdns.config.PersistInterval)
+	if interval <= 0 {
+		interval = defaultPersistInterval
+	}
+	fkdns.persist = &task.Periodic{
+		Interval: interval,
+		Execute: func() error {
+			if err := fkdns.save(); err != nil {
+				errors.LogWarningInner(context.Background(), err, "failed to save fake DNS mapping to ", fkdns.config.PersistPath)
+			}
+			return nil
+		},
+	}
+	return fkdns.persist.Start()
+}
+
+// stopPersist stops saving periodically, and saves the mapping for the last time.
+func (fkdns *Holder) stopPersist() error {
+	if fkdns.persist == nil {
+		return nil
+	}
+	fkdns.persist.Close()
+	fkdns.persist = nil
+	return fkdns.save()
+}
+
+func (fkdns *Holder) load() error {
+	data, err := filesystem.ReadFile(fkdns.config.PersistPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var pool persistedPool
+	if err := json.Unmarshal(data, &pool); err != nil {
+		return err
+	}
+	if pool.IPPool != fkdns.ipRange.String() {
+		errors.LogInfo(context.Background(), "fake DNS pool changed from ", pool.IPPool, " to ", fkdns.ipRange, ", discarding saved mapping")
+		return nil
+	}
+
+	fkdns.mu.Lock()
+	defer fkdns.mu.Unlock()
+	for _, m := range pool.Mappings {
+		ip := net.ParseAddress(m.IP)
+		if !ip.Family().IsIP() || !fkdns.ipRange.Contains(ip.IP()) {
+			continue
+		}
+		fkdns.domainToIP.Put(m.Domain, ip)
+	}
+	errors.LogInfo(context.Background(), "restored ", len(pool.Mappings), " fake DNS mappings in ", pool.IPPool)
+	return nil
+}
+
+func (fkdns *Holder) save() error {
+	fkdns.mu.Lock()
+	if !fkdns.dirty {
+		fkdns.mu.Unlock()
+		return nil
+	}
+	pool := persistedPool{IPPool: fkdns.ipRange.String()}
+	fkdns.domainToIP.Range(func(key, value interface{}) bool {
+		pool.Mappings = append(pool.Mappings, persistedMapping{
+			Domain: key.(string),
+			IP:     value.(net.Address).String(),
+		})
+		return true
+	})
+	fkdns.dirty = false
+	fkdns.mu.Unlock()
+
+	data, err := json.Marshal(pool)
+	if err != nil {
+		return err
+	}
+	if err := filesystem.WriteFileAtomic(fkdns.config.PersistPath, data); err != nil {
+		fkdns.mu.Lock()
+		fkdns.dirty = true
+		fkdns.mu.Unlock()
+		return err
+	}
+	return nil
+}
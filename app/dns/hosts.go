@@ -2,6 +2,8 @@ package dns
 
 import (
 	"context"
+	"strings"
+	"sync"
 
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
@@ -13,6 +15,17 @@ import (
 type StaticHosts struct {
 	ips      [][]net.Address
 	matchers *strmatcher.MatcherGroup
+
+	// runtime holds the mappings of full domains added at runtime, which
+	// take precedence over the configured ones
+	access  sync.RWMutex
+	runtime map[string][]net.Address
+
+	// files holds the mappings of full domains loaded from hosts files,
+	// which are looked up after the configured ones
+	files      map[string][]net.Address
+	filePaths  []string
+	fileStates []hostsFileState
 }
 
 // NewStaticHosts creates a new StaticHosts instance.
@@ -21,6 +34,7 @@ func NewStaticHosts(hosts []*Config_HostMapping) (*StaticHosts, error) {
 	sh := &StaticHosts{
 		ips:      make([][]net.Address, len(hosts)+16),
 		matchers: g,
+		runtime:  make(map[string][]net.Address),
 	}
 
 	for _, mapping := range hosts {
@@ -62,11 +76,24 @@ func filterIP(ips []net.Address, option dns.IPOption) []net.Address {
 }
 
 func (h *StaticHosts) lookupInternal(domain string) []net.Address {
+	h.access.RLock()
+	addrs, found := h.runtime[strings.ToLower(domain)]
+	h.access.RUnlock()
+	if found {
+		return addrs
+	}
+
 	var ips []net.Address
 	for _, id := range h.matchers.Match(domain) {
 		ips = append(ips, h.ips[id]...)
 	}
-	return ips
+	if len(ips) > 0 {
+		return ips
+	}
+
+	h.access.RLock()
+	defer h.access.RUnlock()
+	return h.files[strings.ToLower(domain)]
 }
 
 func (h *StaticHosts) lookup(domain string, option dns.IPOption, maxDepth int) []net.Address {
@@ -91,3 +118,32 @@ func (h *StaticHosts) lookup(domain string, option dns.IPOption, maxDepth int) [
 func (h *StaticHosts) Lookup(domain string, option dns.IPOption) []net.Address {
 	return h.lookup(domain, option, 5)
 }
+
+// AddHosts maps the domain to addrs at runtime, replacing the mapping added before.
+func (h *StaticHosts) AddHosts(domain string, addrs []net.Address) error {
+	if domain == "" || len(addrs) == 0 {
+		return errors.New("neither domain nor addresses can be empty")
+	}
+	for _, addr := range addrs {
+		if addr.Family().IsDomain() && len(addrs) > 1 {
+			return errors.New("a domain can only be mapped to a single proxied domain: ", domain)
+		}
+	}
+	h.access.Lock()
+	h.runtime[strings.ToLower(domain)] = addrs
+	h.access.Unlock()
+	errors.LogInfo(context.Background(), "static hosts added: ", domain, " -> ", addrs)
+	return nil
+}
+
+// RemoveHosts removes the mapping of the domain added at runtime.
+func (h *StaticHosts) RemoveHosts(domain string) error {
+	domain = strings.ToLower(domain)
+	h.access.Lock()
+	defer h.access.Unlock()
+	if _, found := h.runtime[domain]; !found {
+		return errors.New("no runtime hosts for domain: ", domain)
+	}
+	delete(h.runtime, domain)
+	return nil
+}
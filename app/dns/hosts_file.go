@@ -0,0 +1,147 @@
+package dns
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/platform/filesystem"
+	"github.com/xtls/xray-core/common/task"
+)
+
+// defaultHostsReloadInterval is the interval hosts files are checked for changes.
+const defaultHostsReloadInterval = 10 * time.Second
+
+// hostsFileState is the modification state of a hosts file when loaded,
+// with the mappings loaded from it.
+type hostsFileState struct {
+	modTime time.Time
+	size    int64
+	hosts   map[string][]net.Address
+}
+
+// parseHostsFile parses a file in the format of /etc/hosts, in which each
+// line is an IP followed by host names, into the addresses of full domains.
+func parseHostsFile(data []byte, hosts map[string][]net.Address) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		// zone of IPv6 link-local addresses is not supported
+		ip := net.ParseIP(strings.SplitN(fields[0], "%", 2)[0])
+		if ip == nil {
+			errors.LogDebug(context.Background(), "invalid IP in hosts file: ", fields[0])
+			continue
+		}
+		addr := net.IPAddress(ip)
+		for _, name := range fields[1:] {
+			name = strings.ToLower(strings.TrimSuffix(name, "."))
+			hosts[name] = append(hosts[name], addr)
+		}
+	}
+}
+
+// loadHostsFile reads the hosts file at path.
+func loadHostsFile(path string) (hostsFileState, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return hostsFileState{}, err
+	}
+	data, err := filesystem.ReadFile(path)
+	if err != nil {
+		return hostsFileState{}, err
+	}
+	hosts := make(map[string][]net.Address)
+	parseHostsFile(data, hosts)
+	return hostsFileState{modTime: info.ModTime(), size: info.Size(), hosts: hosts}, nil
+}
+
+// LoadFiles replaces the mappings from hosts files with the ones in paths.
+// Mappings from files take effect after the configured ones. A file failed to
+// read keeps the mappings it was last loaded with, and the failures are
+// returned after the others are loaded.
+func (h *StaticHosts) LoadFiles(paths []string) error {
+	h.access.RLock()
+	previous := make(map[string]hostsFileState, len(h.filePaths))
+	for i, path := range h.filePaths {
+		previous[path] = h.fileStates[i]
+	}
+	h.access.RUnlock()
+
+	var errs []error
+	states := make([]hostsFileState, len(paths))
+	for i, path := range paths {
+		state, err := loadHostsFile(path)
+		if err != nil {
+			errs = append(errs, errors.New("failed to read hosts file ", path).Base(err))
+			state = previous[path]
+		}
+		states[i] = state
+	}
+	hosts := make(map[string][]net.Address)
+	for _, state := range states {
+		for name, addrs := range state.hosts {
+			hosts[name] = append(hosts[name], addrs...)
+		}
+	}
+
+	h.access.Lock()
+	h.files = hosts
+	h.filePaths = paths
+	h.fileStates = states
+	h.access.Unlock()
+	errors.LogInfo(context.Background(), "loaded ", len(hosts), " hosts from files ", paths)
+	if len(errs) > 0 {
+		return errors.Combine(errs...)
+	}
+	return nil
+}
+
+// filesModified checks whether any hosts file is changed since loaded.
+func (h *StaticHosts) filesModified() bool {
+	h.access.RLock()
+	defer h.access.RUnlock()
+	for i, path := range h.filePaths {
+		info, err := os.Stat(path)
+		if err != nil {
+			// keep the mappings loaded if the file is removed
+			continue
+		}
+		if !info.ModTime().Equal(h.fileStates[i].modTime) || info.Size() != h.fileStates[i].size {
+			return true
+		}
+	}
+	return false
+}
+
+// startWatchFiles reloads the hosts files when they are changed.
+func (s *DNS) startWatchFiles() error {
+	interval := s.hostsReloadInterval
+	if interval <= 0 {
+		interval = defaultHostsReloadInterval
+	}
+	s.hostsReload = &task.Periodic{
+		Interval: interval,
+		Execute: func() error {
+			if !s.hosts.filesModified() {
+				return nil
+			}
+			if err := s.hosts.LoadFiles(s.hostsFiles); err != nil {
+				errors.LogWarningInner(s.ctx, err, "failed to reload hosts files")
+			}
+			return nil
+		},
+	}
+	return s.hostsReload.Start()
+}
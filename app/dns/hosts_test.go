@@ -1,6 +1,8 @@
 package dns_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -118,3 +120,87 @@ func TestStaticHosts(t *testing.T) {
 		}
 	}
 }
+
+func TestStaticHostsFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts")
+	common.Must(os.WriteFile(path, []byte(`# comment
+127.0.0.1	localhost
+::1	localhost ip6-localhost
+10.0.0.1	internal.example.com	Alias.Example.com. # trailing comment
+10.0.0.2	internal.example.com
+not-an-ip	invalid.example.com
+`), 0o644))
+
+	pb := []*Config_HostMapping{
+		{
+			Type:   DomainMatchingType_Full,
+			Domain: "internal.example.com",
+			Ip:     [][]byte{{1, 1, 1, 1}},
+		},
+	}
+	hosts, err := NewStaticHosts(pb)
+	common.Must(err)
+	common.Must(hosts.LoadFiles([]string{path}))
+
+	option := dns.IPOption{IPv4Enable: true, IPv6Enable: true}
+	{
+		ips := hosts.Lookup("localhost", option)
+		if r := cmp.Diff(ips, []net.Address{net.LocalHostIP, net.LocalHostIPv6}); r != "" {
+			t.Error(r)
+		}
+	}
+	{
+		ips := hosts.Lookup("alias.example.com", option)
+		if r := cmp.Diff(ips, []net.Address{net.ParseAddress("10.0.0.1")}); r != "" {
+			t.Error(r)
+		}
+	}
+	{
+		// configured hosts take precedence
+		ips := hosts.Lookup("internal.example.com", option)
+		if r := cmp.Diff(ips, []net.Address{net.ParseAddress("1.1.1.1")}); r != "" {
+			t.Error(r)
+		}
+	}
+	if ips := hosts.Lookup("invalid.example.com", option); ips != nil {
+		t.Error("expected no hosts, actual ", ips)
+	}
+
+	common.Must(os.WriteFile(path, []byte("10.0.0.3 alias.example.com\n"), 0o644))
+	common.Must(hosts.LoadFiles([]string{path}))
+	{
+		ips := hosts.Lookup("alias.example.com", option)
+		if r := cmp.Diff(ips, []net.Address{net.ParseAddress("10.0.0.3")}); r != "" {
+			t.Error(r)
+		}
+	}
+	if ips := hosts.Lookup("localhost", option); ips != nil {
+		t.Error("expected hosts removed, actual ", ips)
+	}
+
+	if err := hosts.LoadFiles([]string{filepath.Join(t.TempDir(), "missing")}); err == nil {
+		t.Error("expected error of missing hosts file")
+	}
+
+	// a file failed to reload keeps its mappings, while the others reload
+	other := filepath.Join(t.TempDir(), "hosts")
+	common.Must(os.WriteFile(other, []byte("10.0.0.4 other.example.com\n"), 0o644))
+	common.Must(hosts.LoadFiles([]string{path, other}))
+	common.Must(os.Remove(path))
+	common.Must(os.WriteFile(other, []byte("10.0.0.5 other.example.com\n"), 0o644))
+	if err := hosts.LoadFiles([]string{path, other}); err == nil {
+		t.Error("expected error of removed hosts file")
+	}
+	{
+		ips := hosts.Lookup("alias.example.com", option)
+		if r := cmp.Diff(ips, []net.Address{net.ParseAddress("10.0.0.3")}); r != "" {
+			t.Error("mappings of removed file not kept: ", r)
+		}
+	}
+	{
+		ips := hosts.Lookup("other.example.com", option)
+		if r := cmp.Diff(ips, []net.Address{net.ParseAddress("10.0.0.5")}); r != "" {
+			t.Error("other file not reloaded: ", r)
+		}
+	}
+}
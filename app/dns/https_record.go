@@ -0,0 +1,121 @@
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/session"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// exchanger is a name server sending raw DNS messages.
+type exchanger interface {
+	exchange(ctx context.Context, msg []byte) ([]byte, error)
+}
+
+// LookupECHConfig implements dns.ECHConfigResolver.
+func (s *DNS) LookupECHConfig(domain string) ([]byte, time.Duration, error) {
+	domain = strings.TrimSuffix(domain, ".")
+	if domain == "" {
+		return nil, 0, errors.New("empty domain name")
+	}
+
+	msg, err := packQuery(domain, typeHTTPS, false)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	errs := []error{}
+	ctx := session.ContextWithInbound(s.ctx, &session.Inbound{Tag: s.tag})
+	for _, client := range s.sortClients(domain) {
+		server, ok := client.server.(exchanger)
+		if !ok {
+			continue
+		}
+		config, ttl, err := lookupECHConfig(ctx, server, msg)
+		if err == nil {
+			errors.LogDebug(s.ctx, "ECH config of domain ", domain, " answered by server ", client.Name())
+			return config, ttl, nil
+		}
+		errors.LogInfoInner(s.ctx, err, "failed to lookup ECH config for domain ", domain, " at server ", client.Name())
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return nil, 0, errors.New("no name server for HTTPS records of ", domain)
+	}
+	return nil, 0, errors.New("returning nil for domain ", domain).Base(errors.Combine(errs...))
+}
+
+// lookupECHConfig queries the HTTPS record with the ech parameter.
+func lookupECHConfig(ctx context.Context, server exchanger, msg []byte) ([]byte, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	payload, err := server.exchange(ctx, msg)
+	if err != nil {
+		return nil, 0, err
+	}
+	var p dnsmessage.Parser
+	header, err := p.Start(payload)
+	if err != nil {
+		return nil, 0, err
+	}
+	if header.RCode != dnsmessage.RCodeSuccess {
+		return nil, 0, dns_feature.RCodeError(header.RCode)
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return nil, 0, err
+	}
+	for {
+		h, err := p.AnswerHeader()
+		if err == dnsmessage.ErrSectionDone {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if h.Type != dnsmessage.Type(typeHTTPS) {
+			if err := p.SkipAnswer(); err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+		body, err := p.UnknownResource()
+		if err != nil {
+			return nil, 0, err
+		}
+		if ech := echConfigOf(body.Data); ech != nil {
+			return ech, time.Duration(h.TTL) * time.Second, nil
+		}
+	}
+	return nil, 0, dns_feature.ErrEmptyResponse
+}
+
+// echConfigOf returns the value of the ech parameter in the RDATA of an
+// HTTPS record, or nil if there is none.
+func echConfigOf(rdata []byte) []byte {
+	if len(rdata) < 2 {
+		return nil
+	}
+	// SvcPriority, TargetName and SvcParams of RFC 9460
+	_, off, err := readName(rdata, 2)
+	if err != nil {
+		return nil
+	}
+	for off+4 <= len(rdata) {
+		key := binary.BigEndian.Uint16(rdata[off:])
+		n := int(binary.BigEndian.Uint16(rdata[off+2:]))
+		off += 4
+		if off+n > len(rdata) {
+			return nil
+		}
+		if key == svcParamECH {
+			return rdata[off : off+n]
+		}
+		off += n
+	}
+	return nil
+}
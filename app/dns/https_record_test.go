@@ -0,0 +1,54 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/xtls/xray-core/common"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+)
+
+type httpsRecordServer struct {
+	records []dns.RR
+}
+
+func (s *httpsRecordServer) exchange(ctx context.Context, msg []byte) ([]byte, error) {
+	req := new(dns.Msg)
+	if err := req.Unpack(msg); err != nil {
+		return nil, err
+	}
+	resp := new(dns.Msg)
+	resp.SetReply(req)
+	resp.Answer = s.records
+	return resp.Pack()
+}
+
+func TestLookupECHConfig(t *testing.T) {
+	req := new(dns.Msg)
+	req.SetQuestion("example.com.", dns.TypeHTTPS)
+	msg, err := req.Pack()
+	common.Must(err)
+
+	echConfig := []byte{0, 4, 1, 2, 3, 4}
+	server := &httpsRecordServer{records: []dns.RR{&dns.HTTPS{SVCB: dns.SVCB{
+		Hdr:      dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeHTTPS, Class: dns.ClassINET, Ttl: 300},
+		Priority: 1,
+		Target:   ".",
+		Value:    []dns.SVCBKeyValue{&dns.SVCBAlpn{Alpn: []string{"h2"}}, &dns.SVCBECHConfig{ECH: echConfig}},
+	}}}}
+	config, ttl, err := lookupECHConfig(context.Background(), server, msg)
+	common.Must(err)
+	if !bytes.Equal(config, echConfig) {
+		t.Error("unexpected ECH config ", config)
+	}
+	if ttl != 300*time.Second {
+		t.Error("unexpected TTL ", ttl)
+	}
+
+	if _, _, err := lookupECHConfig(context.Background(), &httpsRecordServer{}, msg); err != dns_feature.ErrEmptyResponse {
+		t.Error("expected empty response, actual ", err)
+	}
+}
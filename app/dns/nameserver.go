@@ -30,6 +30,8 @@ type Client struct {
 	skipFallback bool
 	domains      []string
 	expectIPs    []*router.GeoIPMatcher
+	// latency is the smoothed latency of queries in nanoseconds
+	latency int64
 }
 
 var errExpectedIPNonMatch = errors.New("expectIPs not match")
@@ -48,6 +50,10 @@ func NewServer(ctx context.Context, dest net.Destination, dispatcher routing.Dis
 			return NewDoHNameServer(u, dispatcher, queryStrategy)
 		case strings.EqualFold(u.Scheme, "https+local"): // DOH Local mode
 			return NewDoHLocalNameServer(u, queryStrategy), nil
+		case strings.EqualFold(u.Scheme, "h3"): // DOH3 Remote mode
+			return NewDoH3NameServer(u, dispatcher, queryStrategy)
+		case strings.EqualFold(u.Scheme, "h3+local"): // DOH3 Local mode
+			return NewDoH3LocalNameServer(u, queryStrategy), nil
 		case strings.EqualFold(u.Scheme, "quic+local"): // DNS-over-QUIC Local mode
 			return NewQUICNameServer(u, queryStrategy)
 		case strings.EqualFold(u.Scheme, "tcp"): // DNS-over-TCP Remote mode
@@ -72,6 +78,7 @@ func NewServer(ctx context.Context, dest net.Destination, dispatcher routing.Dis
 }
 
 // NewClient creates a DNS client managing a name server with client IP, domain rules and expected IPs.
+// Responses are validated with DNSSEC if anchors is not nil.
 func NewClient(
 	ctx context.Context,
 	ns *NameServer,
@@ -79,6 +86,8 @@ func NewClient(
 	container router.GeoIPMatcherContainer,
 	matcherInfos *[]*DomainMatcherInfo,
 	updateDomainRule func(strmatcher.Matcher, int, []*DomainMatcherInfo) error,
+	cacheOptions CacheOptions,
+	anchors trustAnchors,
 ) (*Client, error) {
 	client := &Client{}
 
@@ -88,6 +97,12 @@ func NewClient(
 		if err != nil {
 			return errors.New("failed to create nameserver").Base(err).AtWarning()
 		}
+		if cached, ok := server.(interface{ SetCacheOptions(CacheOptions) }); ok {
+			cached.SetCacheOptions(cacheOptions)
+		}
+		if validated, ok := server.(interface{ SetDNSSEC(trustAnchors) }); ok && anchors != nil {
+			validated.SetDNSSEC(anchors)
+		}
 
 		// Prioritize local domains with specific TLDs or those without any dot for the local DNS
 		if _, isLocalDNS := server.(*LocalNameServer); isLocalDNS {
@@ -174,9 +189,14 @@ func (c *Client) Name() string {
 
 // QueryIP sends DNS query to the name server with the client's IP.
 func (c *Client) QueryIP(ctx context.Context, domain string, option dns.IPOption, disableCache bool) ([]net.IP, error) {
-	ctx, cancel := context.WithTimeout(ctx, 4*time.Second)
+	cached := !disableCache && c.isCached(domain)
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
 	ips, err := c.server.QueryIP(ctx, domain, c.clientIP, option, disableCache)
 	cancel()
+	if !cached {
+		c.updateLatency(time.Since(start), err)
+	}
 
 	if err != nil {
 		return ips, err
@@ -3,13 +3,15 @@ package dns
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"sync"
 	"time"
 
+	"github.com/xtls/quic-go"
+	"github.com/xtls/quic-go/http3"
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/log"
@@ -18,26 +20,22 @@ import (
 	"github.com/xtls/xray-core/common/protocol/dns"
 	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/common/signal/pubsub"
-	"github.com/xtls/xray-core/common/task"
 	dns_feature "github.com/xtls/xray-core/features/dns"
 	"github.com/xtls/xray-core/features/routing"
 	"github.com/xtls/xray-core/transport/internet"
-	"golang.org/x/net/dns/dnsmessage"
 )
 
 // DoHNameServer implemented DNS over HTTPS (RFC8484) Wire Format,
 // which is compatible with traditional dns over udp(RFC1035),
 // thus most of the DOH implementation is copied from udpns.go
 type DoHNameServer struct {
-	dispatcher routing.Dispatcher
-	sync.RWMutex
-	ips           map[string]*record
-	pub           *pubsub.Service
-	cleanup       *task.Periodic
+	*CacheController
+	dispatcher    routing.Dispatcher
 	httpClient    *http.Client
 	dohURL        string
 	name          string
 	queryStrategy QueryStrategy
+	dnssec        *dnssecValidator
 }
 
 // NewDoHNameServer creates DOH server object for remote resolving.
@@ -122,18 +120,115 @@ func NewDoHLocalNameServer(url *url.URL, queryStrategy QueryStrategy) *DoHNameSe
 	return s
 }
 
+// NewDoH3NameServer creates DNS-over-HTTP/3 server object for remote resolving.
+func NewDoH3NameServer(url *url.URL, dispatcher routing.Dispatcher, queryStrategy QueryStrategy) (*DoHNameServer, error) {
+	url.Scheme = "https"
+	s := baseDOHNameServer(url, "DOH3", queryStrategy)
+	s.dispatcher = dispatcher
+	s.httpClient = &http.Client{
+		Timeout: time.Second * 180,
+		Transport: newH3Transport(func(ctx context.Context, dest net.Destination) (net.Conn, error) {
+			link, err := s.dispatcher.Dispatch(toDnsContext(ctx, s.dohURL), dest)
+			if err != nil {
+				return nil, err
+			}
+
+			cc := common.ChainedClosable{}
+			if cw, ok := link.Writer.(common.Closable); ok {
+				cc = append(cc, cw)
+			}
+			if cr, ok := link.Reader.(common.Closable); ok {
+				cc = append(cc, cr)
+			}
+			return cnc.NewConnection(
+				cnc.ConnectionInputMulti(link.Writer),
+				cnc.ConnectionOutputMultiUDP(link.Reader),
+				cnc.ConnectionOnClose(cc),
+			), nil
+		}),
+	}
+	errors.LogInfo(context.Background(), "DNS: created Remote DOH3 client for ", url.String())
+	return s, nil
+}
+
+// NewDoH3LocalNameServer creates DNS-over-HTTP/3 client object for local resolving.
+func NewDoH3LocalNameServer(url *url.URL, queryStrategy QueryStrategy) *DoHNameServer {
+	url.Scheme = "https"
+	s := baseDOHNameServer(url, "DOH3L", queryStrategy)
+	s.httpClient = &http.Client{
+		Timeout: time.Second * 180,
+		Transport: newH3Transport(func(ctx context.Context, dest net.Destination) (net.Conn, error) {
+			conn, err := internet.DialSystem(ctx, dest, nil)
+			log.Record(&log.AccessMessage{
+				From:   "DNS",
+				To:     s.dohURL,
+				Status: log.AccessAccepted,
+				Detour: "local",
+			})
+			return conn, err
+		}),
+	}
+	errors.LogInfo(context.Background(), "DNS: created Local DOH3 client for ", url.String())
+	return s
+}
+
+// newH3Transport creates an HTTP/3 transport running QUIC over the packet
+// connections from dial.
+func newH3Transport(dial func(context.Context, net.Destination) (net.Conn, error)) *http3.Transport {
+	return &http3.Transport{
+		QUICConfig: &quic.Config{
+			HandshakeIdleTimeout: handshakeTimeout,
+			MaxIdleTimeout:       90 * time.Second,
+			KeepAlivePeriod:      30 * time.Second,
+		},
+		Dial: func(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config) (quic.EarlyConnection, error) {
+			dest, err := net.ParseDestination("udp:" + addr)
+			if err != nil {
+				return nil, err
+			}
+			conn, err := dial(ctx, dest)
+			if err != nil {
+				return nil, err
+			}
+
+			var packetConn net.PacketConn
+			var remote string
+			switch c := conn.(type) {
+			case *internet.PacketConnWrapper:
+				packetConn, remote = c.Conn, c.Dest.String()
+			case *net.UDPConn:
+				packetConn, remote = c, c.RemoteAddr().String()
+			default:
+				packetConn, remote = &internet.FakePacketConn{Conn: c}, c.RemoteAddr().String()
+			}
+			udpAddr, err := net.ResolveUDPAddr("udp", remote)
+			if err != nil {
+				conn.Close()
+				return nil, err
+			}
+
+			qconn, err := quic.DialEarly(ctx, packetConn, udpAddr, tlsCfg, cfg)
+			if err != nil {
+				conn.Close()
+				return nil, err
+			}
+			// the packet connection is not closed by QUIC as it is not created by it
+			go func() {
+				<-qconn.Context().Done()
+				conn.Close()
+			}()
+			return qconn, nil
+		},
+	}
+}
+
 func baseDOHNameServer(url *url.URL, prefix string, queryStrategy QueryStrategy) *DoHNameServer {
 	s := &DoHNameServer{
-		ips:           make(map[string]*record),
-		pub:           pubsub.NewService(),
 		name:          prefix + "//" + url.Host,
 		dohURL:        url.String(),
 		queryStrategy: queryStrategy,
 	}
-	s.cleanup = &task.Periodic{
-		Interval: time.Minute,
-		Execute:  s.Cleanup,
-	}
+	s.CacheController = NewCacheController(s.name)
 	return s
 }
 
@@ -142,81 +237,9 @@ func (s *DoHNameServer) Name() string {
 	return s.name
 }
 
-// Cleanup clears expired items from cache
-func (s *DoHNameServer) Cleanup() error {
-	now := time.Now()
-	s.Lock()
-	defer s.Unlock()
-
-	if len(s.ips) == 0 {
-		return errors.New("nothing to do. stopping...")
-	}
-
-	for domain, record := range s.ips {
-		if record.A != nil && record.A.Expire.Before(now) {
-			record.A = nil
-		}
-		if record.AAAA != nil && record.AAAA.Expire.Before(now) {
-			record.AAAA = nil
-		}
-
-		if record.A == nil && record.AAAA == nil {
-			errors.LogDebug(context.Background(), s.name, " cleanup ", domain)
-			delete(s.ips, domain)
-		} else {
-			s.ips[domain] = record
-		}
-	}
-
-	if len(s.ips) == 0 {
-		s.ips = make(map[string]*record)
-	}
-
-	return nil
-}
-
-func (s *DoHNameServer) updateIP(req *dnsRequest, ipRec *IPRecord) {
-	elapsed := time.Since(req.start)
-
-	s.Lock()
-	rec, found := s.ips[req.domain]
-	if !found {
-		rec = &record{}
-	}
-	updated := false
-
-	switch req.reqType {
-	case dnsmessage.TypeA:
-		if isNewer(rec.A, ipRec) {
-			rec.A = ipRec
-			updated = true
-		}
-	case dnsmessage.TypeAAAA:
-		addr := make([]net.Address, 0, len(ipRec.IP))
-		for _, ip := range ipRec.IP {
-			if len(ip.IP()) == net.IPv6len {
-				addr = append(addr, ip)
-			}
-		}
-		ipRec.IP = addr
-		if isNewer(rec.AAAA, ipRec) {
-			rec.AAAA = ipRec
-			updated = true
-		}
-	}
-	errors.LogInfo(context.Background(), s.name, " got answer: ", req.domain, " ", req.reqType, " -> ", ipRec.IP, " ", elapsed)
-
-	if updated {
-		s.ips[req.domain] = rec
-	}
-	switch req.reqType {
-	case dnsmessage.TypeA:
-		s.pub.Publish(req.domain+"4", nil)
-	case dnsmessage.TypeAAAA:
-		s.pub.Publish(req.domain+"6", nil)
-	}
-	s.Unlock()
-	common.Must(s.cleanup.Start())
+// SetDNSSEC enables validating responses with the chain of trust from anchors.
+func (s *DoHNameServer) SetDNSSEC(anchors trustAnchors) {
+	s.dnssec = newDNSSECValidator(s.name, anchors, s.exchange)
 }
 
 func (s *DoHNameServer) newReqID() uint16 {
@@ -231,7 +254,7 @@ func (s *DoHNameServer) sendQuery(ctx context.Context, domain string, clientIP n
 		return
 	}
 
-	reqs := buildReqMsgs(domain, option, s.newReqID, genEDNS0Options(clientIP))
+	reqs := buildReqMsgs(domain, option, s.newReqID, s.dnssec.reqOptions(clientIP))
 
 	var deadline time.Time
 	if d, ok := ctx.Deadline(); ok {
@@ -278,11 +301,16 @@ func (s *DoHNameServer) sendQuery(ctx context.Context, domain string, clientIP n
 				errors.LogErrorInner(ctx, err, "failed to handle DOH response for ", domain)
 				return
 			}
-			s.updateIP(r, rec)
+			s.updateIP(r, s.dnssec.check(dnsCtx, resp, rec))
 		}(req)
 	}
 }
 
+// exchange sends a DNS message and returns its response.
+func (s *DoHNameServer) exchange(ctx context.Context, msg []byte) ([]byte, error) {
+	return s.dohHTTPSContext(ctx, msg)
+}
+
 func (s *DoHNameServer) dohHTTPSContext(ctx context.Context, b []byte) ([]byte, error) {
 	body := bytes.NewBuffer(b)
 	req, err := http.NewRequest("POST", s.dohURL, body)
@@ -309,48 +337,6 @@ func (s *DoHNameServer) dohHTTPSContext(ctx context.Context, b []byte) ([]byte,
 	return io.ReadAll(resp.Body)
 }
 
-func (s *DoHNameServer) findIPsForDomain(domain string, option dns_feature.IPOption) ([]net.IP, error) {
-	s.RLock()
-	record, found := s.ips[domain]
-	s.RUnlock()
-
-	if !found {
-		return nil, errRecordNotFound
-	}
-
-	var err4 error
-	var err6 error
-	var ips []net.Address
-	var ip6 []net.Address
-
-	if option.IPv4Enable {
-		ips, err4 = record.A.getIPs()
-	}
-
-	if option.IPv6Enable {
-		ip6, err6 = record.AAAA.getIPs()
-		ips = append(ips, ip6...)
-	}
-
-	if len(ips) > 0 {
-		return toNetIP(ips)
-	}
-
-	if err4 != nil {
-		return nil, err4
-	}
-
-	if err6 != nil {
-		return nil, err6
-	}
-
-	if (option.IPv4Enable && record.A != nil) || (option.IPv6Enable && record.AAAA != nil) {
-		return nil, dns_feature.ErrEmptyResponse
-	}
-
-	return nil, errRecordNotFound
-}
-
 // QueryIP implements Server.
 func (s *DoHNameServer) QueryIP(ctx context.Context, domain string, clientIP net.IP, option dns_feature.IPOption, disableCache bool) ([]net.IP, error) { // nolint: dupl
 	fqdn := Fqdn(domain)
@@ -362,10 +348,15 @@ func (s *DoHNameServer) QueryIP(ctx context.Context, domain string, clientIP net
 	if disableCache {
 		errors.LogDebug(ctx, "DNS cache is disabled. Querying IP for ", domain, " at ", s.name)
 	} else {
-		ips, err := s.findIPsForDomain(fqdn, option)
+		ips, refresh, err := s.findIPsForDomain(fqdn, option)
 		if err == nil || err == dns_feature.ErrEmptyResponse {
 			errors.LogDebugInner(ctx, err, s.name, " cache HIT ", domain, " -> ", ips)
 			log.Record(&log.DNSLog{Server: s.name, Domain: domain, Result: ips, Status: log.DNSCacheHit, Elapsed: 0, Error: err})
+			if refresh {
+				s.refresh(ctx, fqdn, option, func(ctx context.Context) {
+					s.sendQuery(ctx, fqdn, clientIP, option)
+				})
+			}
 			return ips, err
 		}
 	}
@@ -400,7 +391,7 @@ func (s *DoHNameServer) QueryIP(ctx context.Context, domain string, clientIP net
 	start := time.Now()
 
 	for {
-		ips, err := s.findIPsForDomain(fqdn, option)
+		ips, err := s.findFreshIPsForDomain(fqdn, option)
 		if err != errRecordNotFound {
 			log.Record(&log.DNSLog{Server: s.name, Domain: domain, Result: ips, Status: log.DNSQueried, Elapsed: time.Since(start), Error: err})
 			return ips, err
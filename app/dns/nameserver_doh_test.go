@@ -103,3 +103,20 @@ func TestDOHNameServerWithIPv6Override(t *testing.T) {
 		}
 	}
 }
+
+func TestDOH3NameServer(t *testing.T) {
+	url, err := url.Parse("h3+local://1.1.1.1/dns-query")
+	common.Must(err)
+
+	s := NewDoH3LocalNameServer(url, QueryStrategy_USE_IP)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	ips, err := s.QueryIP(ctx, "google.com", net.IP(nil), dns_feature.IPOption{
+		IPv4Enable: true,
+		IPv6Enable: true,
+	}, false)
+	cancel()
+	common.Must(err)
+	if len(ips) == 0 {
+		t.Error("expect some ips, but got 0")
+	}
+}
@@ -2,12 +2,14 @@ package dns
 
 import (
 	"context"
+	"encoding/binary"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/log"
 	"github.com/xtls/xray-core/common/net"
@@ -23,16 +25,16 @@ import (
 
 // ClassicNameServer implemented traditional UDP DNS.
 type ClassicNameServer struct {
+	*CacheController
 	sync.RWMutex
 	name          string
 	address       *net.Destination
-	ips           map[string]*record
 	requests      map[uint16]*dnsRequest
-	pub           *pubsub.Service
 	udpServer     *udp.Dispatcher
 	cleanup       *task.Periodic
 	reqID         uint32
 	queryStrategy QueryStrategy
+	dnssec        *dnssecValidator
 }
 
 // NewClassicNameServer creates udp server object for remote resolving.
@@ -44,12 +46,11 @@ func NewClassicNameServer(address net.Destination, dispatcher routing.Dispatcher
 
 	s := &ClassicNameServer{
 		address:       &address,
-		ips:           make(map[string]*record),
 		requests:      make(map[uint16]*dnsRequest),
-		pub:           pubsub.NewService(),
 		name:          strings.ToUpper(address.String()),
 		queryStrategy: queryStrategy,
 	}
+	s.CacheController = NewCacheController(s.name)
 	s.cleanup = &task.Periodic{
 		Interval: time.Minute,
 		Execute:  s.Cleanup,
@@ -64,36 +65,21 @@ func (s *ClassicNameServer) Name() string {
 	return s.name
 }
 
-// Cleanup clears expired items from cache
+// SetDNSSEC enables validating responses with the chain of trust from anchors.
+func (s *ClassicNameServer) SetDNSSEC(anchors trustAnchors) {
+	s.dnssec = newDNSSECValidator(s.name, anchors, s.exchange)
+}
+
+// Cleanup clears expired pending requests
 func (s *ClassicNameServer) Cleanup() error {
 	now := time.Now()
 	s.Lock()
 	defer s.Unlock()
 
-	if len(s.ips) == 0 && len(s.requests) == 0 {
+	if len(s.requests) == 0 {
 		return errors.New(s.name, " nothing to do. stopping...")
 	}
 
-	for domain, record := range s.ips {
-		if record.A != nil && record.A.Expire.Before(now) {
-			record.A = nil
-		}
-		if record.AAAA != nil && record.AAAA.Expire.Before(now) {
-			record.AAAA = nil
-		}
-
-		if record.A == nil && record.AAAA == nil {
-			errors.LogDebug(context.Background(), s.name, " cleanup ", domain)
-			delete(s.ips, domain)
-		} else {
-			s.ips[domain] = record
-		}
-	}
-
-	if len(s.ips) == 0 {
-		s.ips = make(map[string]*record)
-	}
-
 	for id, req := range s.requests {
 		if req.expire.Before(now) {
 			delete(s.requests, id)
@@ -109,7 +95,8 @@ func (s *ClassicNameServer) Cleanup() error {
 
 // HandleResponse handles udp response packet from remote DNS server.
 func (s *ClassicNameServer) HandleResponse(ctx context.Context, packet *udp_proto.Packet) {
-	ipRec, err := parseResponse(packet.Payload.Bytes())
+	payload := packet.Payload.Bytes()
+	ipRec, err := parseResponse(payload)
 	if err != nil {
 		errors.LogError(ctx, s.name, " fail to parse responded DNS udp")
 		return
@@ -128,51 +115,50 @@ func (s *ClassicNameServer) HandleResponse(ctx context.Context, packet *udp_prot
 		return
 	}
 
-	var rec record
-	switch req.reqType {
-	case dnsmessage.TypeA:
-		rec.A = ipRec
-	case dnsmessage.TypeAAAA:
-		rec.AAAA = ipRec
+	if req.response != nil {
+		req.response <- append([]byte(nil), payload...)
+		return
 	}
-
-	elapsed := time.Since(req.start)
-	errors.LogInfo(ctx, s.name, " got answer: ", req.domain, " ", req.reqType, " -> ", ipRec.IP, " ", elapsed)
-	if len(req.domain) > 0 && (rec.A != nil || rec.AAAA != nil) {
-		s.updateIP(req.domain, &rec)
+	if len(req.domain) > 0 {
+		if s.dnssec == nil {
+			s.updateIP(req, ipRec)
+			return
+		}
+		// validating sends more queries answered in this goroutine
+		payload := append([]byte(nil), payload...)
+		go func() {
+			ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+			defer cancel()
+			s.updateIP(req, s.dnssec.check(ctx, payload, ipRec))
+		}()
 	}
 }
 
-func (s *ClassicNameServer) updateIP(domain string, newRec *record) {
-	s.Lock()
-
-	rec, found := s.ips[domain]
-	if !found {
-		rec = &record{}
+// exchange sends a DNS message and waits for its response.
+func (s *ClassicNameServer) exchange(ctx context.Context, msg []byte) ([]byte, error) {
+	id := s.newReqID()
+	req := &dnsRequest{
+		msg:      &dnsmessage.Message{Header: dnsmessage.Header{ID: id}},
+		response: make(chan []byte, 1),
 	}
+	s.addPendingRequest(req)
+	defer func() {
+		s.Lock()
+		delete(s.requests, id)
+		s.Unlock()
+	}()
 
-	updated := false
-	if isNewer(rec.A, newRec.A) {
-		rec.A = newRec.A
-		updated = true
-	}
-	if isNewer(rec.AAAA, newRec.AAAA) {
-		rec.AAAA = newRec.AAAA
-		updated = true
-	}
+	b := buf.New()
+	b.Write(msg)
+	binary.BigEndian.PutUint16(b.BytesTo(2), id)
+	s.udpServer.Dispatch(toDnsContext(ctx, s.address.String()), *s.address, b)
 
-	if updated {
-		errors.LogDebug(context.Background(), s.name, " updating IP records for domain:", domain)
-		s.ips[domain] = rec
-	}
-	if newRec.A != nil {
-		s.pub.Publish(domain+"4", nil)
+	select {
+	case resp := <-req.response:
+		return resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
-	if newRec.AAAA != nil {
-		s.pub.Publish(domain+"6", nil)
-	}
-	s.Unlock()
-	common.Must(s.cleanup.Start())
 }
 
 func (s *ClassicNameServer) newReqID() uint16 {
@@ -181,17 +167,17 @@ func (s *ClassicNameServer) newReqID() uint16 {
 
 func (s *ClassicNameServer) addPendingRequest(req *dnsRequest) {
 	s.Lock()
-	defer s.Unlock()
-
 	id := req.msg.ID
 	req.expire = time.Now().Add(time.Second * 8)
 	s.requests[id] = req
+	s.Unlock()
+	common.Must(s.cleanup.Start())
 }
 
 func (s *ClassicNameServer) sendQuery(ctx context.Context, domain string, clientIP net.IP, option dns_feature.IPOption) {
 	errors.LogDebug(ctx, s.name, " querying DNS for: ", domain)
 
-	reqs := buildReqMsgs(domain, option, s.newReqID, genEDNS0Options(clientIP))
+	reqs := buildReqMsgs(domain, option, s.newReqID, s.dnssec.reqOptions(clientIP))
 
 	for _, req := range reqs {
 		s.addPendingRequest(req)
@@ -200,44 +186,6 @@ func (s *ClassicNameServer) sendQuery(ctx context.Context, domain string, client
 	}
 }
 
-func (s *ClassicNameServer) findIPsForDomain(domain string, option dns_feature.IPOption) ([]net.IP, error) {
-	s.RLock()
-	record, found := s.ips[domain]
-	s.RUnlock()
-
-	if !found {
-		return nil, errRecordNotFound
-	}
-
-	var err4 error
-	var err6 error
-	var ips []net.Address
-	var ip6 []net.Address
-
-	if option.IPv4Enable {
-		ips, err4 = record.A.getIPs()
-	}
-
-	if option.IPv6Enable {
-		ip6, err6 = record.AAAA.getIPs()
-		ips = append(ips, ip6...)
-	}
-
-	if len(ips) > 0 {
-		return toNetIP(ips)
-	}
-
-	if err4 != nil {
-		return nil, err4
-	}
-
-	if err6 != nil {
-		return nil, err6
-	}
-
-	return nil, dns_feature.ErrEmptyResponse
-}
-
 // QueryIP implements Server.
 func (s *ClassicNameServer) QueryIP(ctx context.Context, domain string, clientIP net.IP, option dns_feature.IPOption, disableCache bool) ([]net.IP, error) {
 	fqdn := Fqdn(domain)
@@ -249,10 +197,15 @@ func (s *ClassicNameServer) QueryIP(ctx context.Context, domain string, clientIP
 	if disableCache {
 		errors.LogDebug(ctx, "DNS cache is disabled. Querying IP for ", domain, " at ", s.name)
 	} else {
-		ips, err := s.findIPsForDomain(fqdn, option)
+		ips, refresh, err := s.findIPsForDomain(fqdn, option)
 		if err == nil || err == dns_feature.ErrEmptyResponse {
 			errors.LogDebugInner(ctx, err, s.name, " cache HIT ", domain, " -> ", ips)
 			log.Record(&log.DNSLog{Server: s.name, Domain: domain, Result: ips, Status: log.DNSCacheHit, Elapsed: 0, Error: err})
+			if refresh {
+				s.refresh(ctx, fqdn, option, func(ctx context.Context) {
+					s.sendQuery(ctx, fqdn, clientIP, option)
+				})
+			}
 			return ips, err
 		}
 	}
@@ -287,7 +240,7 @@ func (s *ClassicNameServer) QueryIP(ctx context.Context, domain string, clientIP
 	start := time.Now()
 
 	for {
-		ips, err := s.findIPsForDomain(fqdn, option)
+		ips, err := s.findFreshIPsForDomain(fqdn, option)
 		if err != errRecordNotFound {
 			log.Record(&log.DNSLog{Server: s.name, Domain: domain, Result: ips, Status: log.DNSQueried, Elapsed: time.Since(start), Error: err})
 			return ips, err
@@ -0,0 +1,142 @@
+package dns
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/features/dns"
+)
+
+// queryTimeout is the timeout of querying a name server.
+const queryTimeout = 4 * time.Second
+
+// isCached returns whether the domain is answered from the cache of the
+// name server, which says nothing about the latency of the server.
+func (c *Client) isCached(domain string) bool {
+	if cache, ok := c.server.(interface{ cached(string) bool }); ok {
+		return cache.cached(Fqdn(domain))
+	}
+	return false
+}
+
+// updateLatency updates the smoothed latency of the name server with a query
+// taking elapsed. Queries failed without an answer count as timed out.
+func (c *Client) updateLatency(elapsed time.Duration, err error) {
+	switch {
+	case err == context.Canceled:
+		// canceled as another name server answered first
+		return
+	case err != nil && err != dns.ErrEmptyResponse && dns.RCodeFromError(err) == 0:
+		elapsed = queryTimeout
+	}
+	for {
+		latency := atomic.LoadInt64(&c.latency)
+		updated := int64(elapsed)
+		if latency != 0 {
+			updated = (latency*3 + int64(elapsed)) / 4
+		}
+		if atomic.CompareAndSwapInt64(&c.latency, latency, updated) {
+			return
+		}
+	}
+}
+
+// Latency returns the smoothed latency of the name server, 0 if not queried yet.
+func (c *Client) Latency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.latency))
+}
+
+// sortByLatency orders clients from the fastest one. Clients not queried yet
+// come first, so that their latency gets known.
+func sortByLatency(clients []*Client) {
+	sort.SliceStable(clients, func(i, j int) bool {
+		return clients[i].Latency() < clients[j].Latency()
+	})
+}
+
+type queryResult struct {
+	ips []net.IP
+	err error
+}
+
+// lookupParallel queries clients concurrently, at most parallelLimit of them
+// at a time. The rest are queried only if all of them fail, and none of them
+// answers with an RCode other than REFUSED, which is returned as is.
+func (s *DNS) lookupParallel(ctx context.Context, domain string, clients []*Client, option dns.IPOption) (*dns.Resolution, error) {
+	limit := int(s.parallelLimit)
+	if limit <= 0 {
+		limit = len(clients)
+	}
+
+	errs := []error{}
+	for len(clients) > 0 {
+		n := min(limit, len(clients))
+		ips, servers, batchErrs := s.queryConcurrently(ctx, domain, clients[:n], option)
+		if len(ips) > 0 {
+			return &dns.Resolution{Domain: domain, IPs: ips, Server: strings.Join(servers, ", ")}, nil
+		}
+		for _, err := range batchErrs {
+			// 5 for RcodeRefused in miekg/dns, hardcode to reduce binary size
+			if rcode := dns.RCodeFromError(err); rcode != 0 && rcode != 5 {
+				return nil, err
+			}
+		}
+		errs = append(errs, batchErrs...)
+		clients = clients[n:]
+	}
+
+	return nil, errors.New("returning nil for domain ", domain).Base(errors.Combine(errs...))
+}
+
+// queryConcurrently queries clients at the same time. It returns the first
+// valid answer when racing, or all valid answers merged in the order of
+// clients, with the names of the servers answered.
+func (s *DNS) queryConcurrently(ctx context.Context, domain string, clients []*Client, option dns.IPOption) ([]net.IP, []string, []error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]queryResult, len(clients))
+	done := make(chan int, len(clients))
+	for i, client := range clients {
+		go func(i int, client *Client) {
+			ips, err := client.QueryIP(ctx, domain, option, s.disableCache)
+			results[i] = queryResult{ips: ips, err: err}
+			done <- i
+		}(i, client)
+	}
+
+	var errs []error
+	for range clients {
+		i := <-done
+		result := results[i]
+		if result.err != nil {
+			errors.LogInfoInner(s.ctx, result.err, "failed to lookup ip for domain ", domain, " at server ", clients[i].Name())
+			errs = append(errs, result.err)
+		}
+		if len(result.ips) > 0 && s.parallelQuery == ParallelQuery_RACE {
+			errors.LogDebug(s.ctx, "domain ", domain, " answered first by server ", clients[i].Name())
+			return result.ips, []string{clients[i].Name()}, nil
+		}
+	}
+
+	var ips []net.IP
+	var servers []string
+	seen := make(map[string]bool)
+	for i, result := range results {
+		if len(result.ips) > 0 {
+			servers = append(servers, clients[i].Name())
+		}
+		for _, ip := range result.ips {
+			if !seen[string(ip)] {
+				seen[string(ip)] = true
+				ips = append(ips, ip)
+			}
+		}
+	}
+	return ips, servers, errs
+}
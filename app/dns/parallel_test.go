@@ -0,0 +1,140 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/strmatcher"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+)
+
+type stubServer struct {
+	name  string
+	delay time.Duration
+	ips   []net.IP
+	err   error
+}
+
+func (s *stubServer) Name() string {
+	return s.name
+}
+
+func (s *stubServer) QueryIP(ctx context.Context, domain string, clientIP net.IP, option dns_feature.IPOption, disableCache bool) ([]net.IP, error) {
+	select {
+	case <-time.After(s.delay):
+		return s.ips, s.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func newStubDNS(parallelQuery ParallelQuery, parallelLimit uint32, servers ...*stubServer) *DNS {
+	hosts, err := NewStaticHosts(nil)
+	common.Must(err)
+	clients := make([]*Client, 0, len(servers))
+	for _, server := range servers {
+		clients = append(clients, &Client{server: server})
+	}
+	return &DNS{
+		ctx:           context.Background(),
+		ipOption:      &dns_feature.IPOption{IPv4Enable: true, IPv6Enable: true},
+		hosts:         hosts,
+		clients:       clients,
+		domainMatcher: &strmatcher.MatcherGroup{},
+		parallelQuery: parallelQuery,
+		parallelLimit: parallelLimit,
+	}
+}
+
+func TestParallelQueryRace(t *testing.T) {
+	option := dns_feature.IPOption{IPv4Enable: true}
+	s := newStubDNS(ParallelQuery_RACE, 0,
+		&stubServer{name: "dead", delay: time.Hour},
+		&stubServer{name: "failed", err: errors.New("connection refused")},
+		&stubServer{name: "fast", delay: 10 * time.Millisecond, ips: []net.IP{{1, 1, 1, 1}}},
+		&stubServer{name: "slow", delay: 500 * time.Millisecond, ips: []net.IP{{2, 2, 2, 2}}},
+	)
+
+	start := time.Now()
+	ips, err := s.LookupIP("example.com", option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := cmp.Diff(ips, []net.IP{{1, 1, 1, 1}}); r != "" {
+		t.Error(r)
+	}
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Error("waited for slow servers: ", elapsed)
+	}
+
+	// failed and answering servers are ordered by latency, dead one is not measured yet
+	if latency := s.clients[1].Latency(); latency != queryTimeout {
+		t.Error("expected failed server penalized, actual ", latency)
+	}
+	if latency := s.clients[2].Latency(); latency <= 0 || latency >= queryTimeout {
+		t.Error("expected latency of fast server, actual ", latency)
+	}
+	var names []string
+	for _, client := range s.sortClients("example.com") {
+		names = append(names, client.Name())
+	}
+	if r := cmp.Diff(names, []string{"dead", "slow", "fast", "failed"}); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestParallelQueryMerge(t *testing.T) {
+	option := dns_feature.IPOption{IPv4Enable: true}
+	s := newStubDNS(ParallelQuery_MERGE, 2,
+		&stubServer{name: "a", delay: 20 * time.Millisecond, ips: []net.IP{{1, 1, 1, 1}, {2, 2, 2, 2}}},
+		&stubServer{name: "b", ips: []net.IP{{2, 2, 2, 2}, {3, 3, 3, 3}}},
+		&stubServer{name: "c", ips: []net.IP{{4, 4, 4, 4}}},
+	)
+
+	ips, err := s.LookupIP("example.com", option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := cmp.Diff(ips, []net.IP{{1, 1, 1, 1}, {2, 2, 2, 2}, {3, 3, 3, 3}}); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestParallelQueryLimit(t *testing.T) {
+	option := dns_feature.IPOption{IPv4Enable: true}
+	s := newStubDNS(ParallelQuery_RACE, 2,
+		&stubServer{name: "a", err: dns_feature.ErrEmptyResponse},
+		&stubServer{name: "b", err: errors.New("connection refused")},
+		&stubServer{name: "c", ips: []net.IP{{4, 4, 4, 4}}},
+	)
+
+	ips, err := s.LookupIP("example.com", option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := cmp.Diff(ips, []net.IP{{4, 4, 4, 4}}); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestParallelQueryRCode(t *testing.T) {
+	option := dns_feature.IPOption{IPv4Enable: true}
+	s := newStubDNS(ParallelQuery_RACE, 2,
+		&stubServer{name: "a", err: errors.New("connection refused")},
+		&stubServer{name: "b", delay: 10 * time.Millisecond, err: dns_feature.RCodeError(3)},
+		&stubServer{name: "c", ips: []net.IP{{4, 4, 4, 4}}},
+	)
+
+	ips, err := s.LookupIP("example.com", option)
+	if len(ips) > 0 {
+		t.Error("expected no answer, actual ", ips)
+	}
+	if rcode := dns_feature.RCodeFromError(err); rcode != 3 {
+		t.Error("expected NXDOMAIN, actual rcode ", rcode, ": ", err)
+	}
+}
@@ -0,0 +1,669 @@
+package dns
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Types and flags not defined in dnsmessage, hardcode to reduce binary size.
+const (
+	typeNS     = uint16(dnsmessage.TypeNS)
+	typeCNAME  = uint16(dnsmessage.TypeCNAME)
+	typeSOA    = uint16(dnsmessage.TypeSOA)
+	typeOPT    = uint16(dnsmessage.TypeOPT)
+	typeDNAME  = 39
+	typeDS     = 43
+	typeRRSIG  = 46
+	typeNSEC   = 47
+	typeDNSKEY = 48
+	typeNSEC3  = 50
+	typeHTTPS  = 65
+
+	// flagZone is the Zone Key flag of DNSKEY.
+	flagZone = 0x100
+	// flagOptOut is the Opt-Out flag of NSEC3.
+	flagOptOut = 1
+
+	// svcParamECH is the key of the ech parameter of SVCB and HTTPS records.
+	svcParamECH = 5
+)
+
+var errInvalidRDATA = errors.New("invalid RDATA")
+
+// typeString returns the mnemonic of a record type for logging.
+func typeString(t uint16) string {
+	switch t {
+	case typeDNAME:
+		return "DNAME"
+	case typeDS:
+		return "DS"
+	case typeRRSIG:
+		return "RRSIG"
+	case typeNSEC:
+		return "NSEC"
+	case typeDNSKEY:
+		return "DNSKEY"
+	case typeNSEC3:
+		return "NSEC3"
+	case typeHTTPS:
+		return "HTTPS"
+	}
+	return strings.TrimPrefix(dnsmessage.Type(t).String(), "Type")
+}
+
+// resource is a record with its owner name and RDATA in the canonical form of
+// RFC 4034, section 6.2.
+type resource struct {
+	name   string
+	rrtype uint16
+	class  uint16
+	ttl    uint32
+	rdata  []byte
+}
+
+// message is a response with the sections needed for validation.
+type message struct {
+	header    dnsmessage.Header
+	questions []dnsmessage.Question
+	answer    []*resource
+	ns        []*resource
+}
+
+// parseMessage parses the answer and authority sections of payload.
+func parseMessage(payload []byte) (*message, error) {
+	var p dnsmessage.Parser
+	header, err := p.Start(payload)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := p.AllQuestions()
+	if err != nil {
+		return nil, err
+	}
+	msg := &message{header: header, questions: questions}
+	for {
+		h, err := p.AnswerHeader()
+		if err == dnsmessage.ErrSectionDone {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		r, err := parseResource(&p, h)
+		if err != nil {
+			return nil, err
+		}
+		msg.answer = append(msg.answer, r)
+	}
+	for {
+		h, err := p.AuthorityHeader()
+		if err == dnsmessage.ErrSectionDone {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		r, err := parseResource(&p, h)
+		if err != nil {
+			return nil, err
+		}
+		msg.ns = append(msg.ns, r)
+	}
+	return msg, nil
+}
+
+// parseResource parses the body of the record of h. Names in RDATA, which may
+// be compressed, are decompressed and lowercased.
+func parseResource(p *dnsmessage.Parser, h dnsmessage.ResourceHeader) (*resource, error) {
+	r := &resource{
+		name:   canonicalName(h.Name.String()),
+		rrtype: uint16(h.Type),
+		class:  uint16(h.Class),
+		ttl:    h.TTL,
+	}
+	switch h.Type {
+	case dnsmessage.TypeCNAME:
+		body, err := p.CNAMEResource()
+		if err != nil {
+			return nil, err
+		}
+		r.rdata = appendName(nil, body.CNAME.String())
+	case dnsmessage.TypeNS:
+		body, err := p.NSResource()
+		if err != nil {
+			return nil, err
+		}
+		r.rdata = appendName(nil, body.NS.String())
+	case dnsmessage.TypePTR:
+		body, err := p.PTRResource()
+		if err != nil {
+			return nil, err
+		}
+		r.rdata = appendName(nil, body.PTR.String())
+	case dnsmessage.TypeMX:
+		body, err := p.MXResource()
+		if err != nil {
+			return nil, err
+		}
+		r.rdata = binary.BigEndian.AppendUint16(nil, body.Pref)
+		r.rdata = appendName(r.rdata, body.MX.String())
+	case dnsmessage.TypeSRV:
+		body, err := p.SRVResource()
+		if err != nil {
+			return nil, err
+		}
+		r.rdata = binary.BigEndian.AppendUint16(nil, body.Priority)
+		r.rdata = binary.BigEndian.AppendUint16(r.rdata, body.Weight)
+		r.rdata = binary.BigEndian.AppendUint16(r.rdata, body.Port)
+		r.rdata = appendName(r.rdata, body.Target.String())
+	case dnsmessage.TypeSOA:
+		body, err := p.SOAResource()
+		if err != nil {
+			return nil, err
+		}
+		r.rdata = appendName(nil, body.NS.String())
+		r.rdata = appendName(r.rdata, body.MBox.String())
+		for _, n := range []uint32{body.Serial, body.Refresh, body.Retry, body.Expire, body.MinTTL} {
+			r.rdata = binary.BigEndian.AppendUint32(r.rdata, n)
+		}
+	default:
+		body, err := p.UnknownResource()
+		if err != nil {
+			return nil, err
+		}
+		r.rdata = body.Data
+		if r.rrtype == typeDNAME {
+			// the target is never compressed, but still lowercased
+			r.rdata = lowerASCII(r.rdata)
+		}
+	}
+	return r, nil
+}
+
+// packQuery builds a query of name with EDNS0, and the DO bit set if dnssecOK.
+func packQuery(name string, qtype uint16, dnssecOK bool) ([]byte, error) {
+	qname, err := dnsmessage.NewName(Fqdn(name))
+	if err != nil {
+		return nil, err
+	}
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{RecursionDesired: true})
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(dnsmessage.Question{Name: qname, Type: dnsmessage.Type(qtype), Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	if err := b.StartAdditionals(); err != nil {
+		return nil, err
+	}
+	var opt dnsmessage.ResourceHeader
+	if err := opt.SetEDNS0(4096, dnsmessage.RCodeSuccess, dnssecOK); err != nil {
+		return nil, err
+	}
+	if err := b.OPTResource(opt, dnsmessage.OPTResource{}); err != nil {
+		return nil, err
+	}
+	return b.Finish()
+}
+
+func lowerASCII(b []byte) []byte {
+	lower := make([]byte, len(b))
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		lower[i] = c
+	}
+	return lower
+}
+
+// canonicalName returns name fully qualified and lowercased.
+func canonicalName(name string) string {
+	return string(lowerASCII([]byte(Fqdn(name))))
+}
+
+// splitLabels returns the labels of name, none for the root.
+func splitLabels(name string) []string {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" {
+		return nil
+	}
+	return strings.Split(name, ".")
+}
+
+// joinLabels returns the fully qualified name of labels.
+func joinLabels(labels []string) string {
+	return strings.Join(labels, ".") + "."
+}
+
+// isSubDomain checks whether child is parent or below it, both in canonical form.
+func isSubDomain(parent, child string) bool {
+	return parent == "." || child == parent || strings.HasSuffix(child, "."+parent)
+}
+
+// appendName appends name in the canonical wire format.
+func appendName(b []byte, name string) []byte {
+	for _, label := range splitLabels(canonicalName(name)) {
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	return append(b, 0)
+}
+
+// readName reads an uncompressed name in RDATA at off, and returns it in
+// canonical form with the offset after it.
+func readName(data []byte, off int) (string, int, error) {
+	var name []byte
+	for {
+		if off >= len(data) {
+			return "", 0, errInvalidRDATA
+		}
+		n := int(data[off])
+		off++
+		if n == 0 {
+			break
+		}
+		if n > 63 || off+n > len(data) || bytes.IndexByte(data[off:off+n], '.') >= 0 {
+			return "", 0, errInvalidRDATA
+		}
+		name = append(name, data[off:off+n]...)
+		name = append(name, '.')
+		off += n
+	}
+	return canonicalName(string(name)), off, nil
+}
+
+// parseTypeBitmap parses the type bitmap of NSEC and NSEC3 records.
+func parseTypeBitmap(data []byte) ([]uint16, error) {
+	var types []uint16
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return nil, errInvalidRDATA
+		}
+		window, n := uint16(data[0]), int(data[1])
+		if n == 0 || n > 32 || len(data) < 2+n {
+			return nil, errInvalidRDATA
+		}
+		for i, b := range data[2 : 2+n] {
+			for bit := 0; bit < 8; bit++ {
+				if b&(0x80>>bit) != 0 {
+					types = append(types, window<<8|uint16(i*8+bit))
+				}
+			}
+		}
+		data = data[2+n:]
+	}
+	return types, nil
+}
+
+type dsRecord struct {
+	keyTag     uint16
+	algorithm  uint8
+	digestType uint8
+	digest     []byte
+}
+
+func parseDS(rdata []byte) (*dsRecord, error) {
+	if len(rdata) < 5 {
+		return nil, errInvalidRDATA
+	}
+	return &dsRecord{
+		keyTag:     binary.BigEndian.Uint16(rdata),
+		algorithm:  rdata[2],
+		digestType: rdata[3],
+		digest:     rdata[4:],
+	}, nil
+}
+
+// parseDSText parses a DS record in presentation format, such as
+// ". IN DS 20326 8 2 E06D...".
+func parseDSText(s string) (*dsRecord, error) {
+	fields := strings.Fields(s)
+	i := slices.IndexFunc(fields, func(f string) bool { return strings.EqualFold(f, "DS") })
+	if i < 1 || len(fields) < i+5 {
+		return nil, errors.New("not a DS record")
+	}
+	keyTag, err := strconv.ParseUint(fields[i+1], 10, 16)
+	if err != nil {
+		return nil, errors.New("invalid key tag").Base(err)
+	}
+	algorithm, err := strconv.ParseUint(fields[i+2], 10, 8)
+	if err != nil {
+		return nil, errors.New("invalid algorithm").Base(err)
+	}
+	digestType, err := strconv.ParseUint(fields[i+3], 10, 8)
+	if err != nil {
+		return nil, errors.New("invalid digest type").Base(err)
+	}
+	digest, err := hex.DecodeString(strings.Join(fields[i+4:], ""))
+	if err != nil {
+		return nil, errors.New("invalid digest").Base(err)
+	}
+	return &dsRecord{
+		keyTag:     uint16(keyTag),
+		algorithm:  uint8(algorithm),
+		digestType: uint8(digestType),
+		digest:     digest,
+	}, nil
+}
+
+type dnskeyRecord struct {
+	owner     string
+	flags     uint16
+	algorithm uint8
+	publicKey []byte
+	rdata     []byte
+}
+
+func parseDNSKEY(r *resource) (*dnskeyRecord, error) {
+	if len(r.rdata) < 4 || r.rdata[2] != 3 {
+		return nil, errInvalidRDATA
+	}
+	return &dnskeyRecord{
+		owner:     r.name,
+		flags:     binary.BigEndian.Uint16(r.rdata),
+		algorithm: r.rdata[3],
+		publicKey: r.rdata[4:],
+		rdata:     r.rdata,
+	}, nil
+}
+
+// keyTag computes the key tag of RFC 4034, appendix B.
+func (k *dnskeyRecord) keyTag() uint16 {
+	var ac uint32
+	for i, b := range k.rdata {
+		if i&1 == 0 {
+			ac += uint32(b) << 8
+		} else {
+			ac += uint32(b)
+		}
+	}
+	ac += ac >> 16 & 0xffff
+	return uint16(ac)
+}
+
+// matches checks whether ds is the digest of k.
+func (k *dnskeyRecord) matches(ds *dsRecord) bool {
+	if k.keyTag() != ds.keyTag || k.algorithm != ds.algorithm {
+		return false
+	}
+	var h crypto.Hash
+	switch ds.digestType {
+	case 1:
+		h = crypto.SHA1
+	case 2:
+		h = crypto.SHA256
+	case 4:
+		h = crypto.SHA384
+	default:
+		return false
+	}
+	return bytes.Equal(digest(h, appendName(nil, k.owner), k.rdata), ds.digest)
+}
+
+type rrsigRecord struct {
+	typeCovered uint16
+	algorithm   uint8
+	labels      uint8
+	originalTTL uint32
+	expiration  uint32
+	inception   uint32
+	keyTag      uint16
+	signer      string
+	signature   []byte
+	// signed is the RDATA without the signature, with the signer in
+	// canonical form.
+	signed []byte
+}
+
+func parseRRSIG(rdata []byte) (*rrsigRecord, error) {
+	if len(rdata) < 18 {
+		return nil, errInvalidRDATA
+	}
+	signer, off, err := readName(rdata, 18)
+	if err != nil {
+		return nil, err
+	}
+	return &rrsigRecord{
+		typeCovered: binary.BigEndian.Uint16(rdata),
+		algorithm:   rdata[2],
+		labels:      rdata[3],
+		originalTTL: binary.BigEndian.Uint32(rdata[4:]),
+		expiration:  binary.BigEndian.Uint32(rdata[8:]),
+		inception:   binary.BigEndian.Uint32(rdata[12:]),
+		keyTag:      binary.BigEndian.Uint16(rdata[16:]),
+		signer:      signer,
+		signature:   rdata[off:],
+		signed:      appendName(slices.Clip(rdata[:18]), signer),
+	}, nil
+}
+
+// validAt checks whether t is in the validity period of s, in the serial
+// number arithmetic of RFC 1982.
+func (s *rrsigRecord) validAt(t time.Time) bool {
+	now := uint32(t.Unix())
+	return int32(now-s.inception) >= 0 && int32(s.expiration-now) >= 0
+}
+
+type nsecRecord struct {
+	owner string
+	next  string
+	types []uint16
+}
+
+func parseNSEC(r *resource) (*nsecRecord, error) {
+	next, off, err := readName(r.rdata, 0)
+	if err != nil {
+		return nil, err
+	}
+	types, err := parseTypeBitmap(r.rdata[off:])
+	if err != nil {
+		return nil, err
+	}
+	return &nsecRecord{owner: r.name, next: next, types: types}, nil
+}
+
+type nsec3Record struct {
+	owner      string
+	flags      uint8
+	iterations uint16
+	salt       []byte
+	next       []byte
+	types      []uint16
+}
+
+// parseNSEC3 parses an NSEC3 record, of which only the SHA-1 hash is
+// supported.
+func parseNSEC3(r *resource) (*nsec3Record, error) {
+	data := r.rdata
+	if len(data) < 5 || data[0] != 1 {
+		return nil, errInvalidRDATA
+	}
+	n := int(data[4])
+	if len(data) < 6+n {
+		return nil, errInvalidRDATA
+	}
+	record := &nsec3Record{
+		owner:      r.name,
+		flags:      data[1],
+		iterations: binary.BigEndian.Uint16(data[2:]),
+		salt:       data[5 : 5+n],
+	}
+	data = data[5+n:]
+	n = int(data[0])
+	if n == 0 || len(data) < 1+n {
+		return nil, errInvalidRDATA
+	}
+	record.next = data[1 : 1+n]
+	types, err := parseTypeBitmap(data[1+n:])
+	if err != nil {
+		return nil, err
+	}
+	record.types = types
+	return record, nil
+}
+
+var base32Hex = base32.HexEncoding.WithPadding(base32.NoPadding)
+
+// hashName returns the hash of name with the parameters of r, in the lowercase
+// base32hex encoding of owner names.
+func (r *nsec3Record) hashName(name string) string {
+	sum := digest(crypto.SHA1, appendName(nil, name), r.salt)
+	for i := 0; i < int(r.iterations); i++ {
+		sum = digest(crypto.SHA1, sum, r.salt)
+	}
+	return strings.ToLower(base32Hex.EncodeToString(sum))
+}
+
+// zone returns the hash in the owner name of r, and the zone it belongs to.
+func (r *nsec3Record) zone() (string, string) {
+	hash, zone, _ := strings.Cut(r.owner, ".")
+	return hash, canonicalName(zone)
+}
+
+// match checks whether r is the NSEC3 record of name.
+func (r *nsec3Record) match(name string) bool {
+	hash, zone := r.zone()
+	return isSubDomain(zone, name) && r.hashName(name) == hash
+}
+
+// cover checks whether the hash of name is between the owner and the next
+// hash of r, which may wrap around.
+func (r *nsec3Record) cover(name string) bool {
+	owner, zone := r.zone()
+	if !isSubDomain(zone, name) {
+		return false
+	}
+	hash := r.hashName(name)
+	next := strings.ToLower(base32Hex.EncodeToString(r.next))
+	if owner < next {
+		return owner < hash && hash < next
+	}
+	return owner < hash || hash < next
+}
+
+func digest(h crypto.Hash, data ...[]byte) []byte {
+	d := h.New()
+	for _, b := range data {
+		d.Write(b)
+	}
+	return d.Sum(nil)
+}
+
+// signedData returns the data signed by sig of RFC 4034, section 3.1.8.1,
+// with the owner name of the wildcard if rrset is expanded from it.
+func signedData(sig *rrsigRecord, rrset []*resource) []byte {
+	owner := rrset[0].name
+	if labels := splitLabels(owner); int(sig.labels) < len(labels) {
+		owner = wildcardOf(joinLabels(labels[len(labels)-int(sig.labels):]))
+	}
+	rdatas := make([][]byte, 0, len(rrset))
+	for _, rr := range rrset {
+		rdatas = append(rdatas, rr.rdata)
+	}
+	slices.SortFunc(rdatas, bytes.Compare)
+	rdatas = slices.CompactFunc(rdatas, bytes.Equal)
+
+	data := slices.Clone(sig.signed)
+	for _, rdata := range rdatas {
+		data = appendName(data, owner)
+		data = binary.BigEndian.AppendUint16(data, rrset[0].rrtype)
+		data = binary.BigEndian.AppendUint16(data, rrset[0].class)
+		data = binary.BigEndian.AppendUint32(data, sig.originalTTL)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(rdata)))
+		data = append(data, rdata...)
+	}
+	return data
+}
+
+// verifySignature verifies sig of rrset with key, supporting the algorithms
+// recommended by RFC 8624.
+func verifySignature(sig *rrsigRecord, key *dnskeyRecord, rrset []*resource) error {
+	data := signedData(sig, rrset)
+	switch sig.algorithm {
+	case 5, 7, 8, 10:
+		pub, err := rsaPublicKey(key.publicKey)
+		if err != nil {
+			return err
+		}
+		h := crypto.SHA1
+		switch sig.algorithm {
+		case 8:
+			h = crypto.SHA256
+		case 10:
+			h = crypto.SHA512
+		}
+		return rsa.VerifyPKCS1v15(pub, h, digest(h, data), sig.signature)
+	case 13, 14:
+		curve, h := elliptic.P256(), crypto.SHA256
+		if sig.algorithm == 14 {
+			curve, h = elliptic.P384(), crypto.SHA384
+		}
+		pub, err := ecdsa.ParseUncompressedPublicKey(curve, append([]byte{4}, key.publicKey...))
+		if err != nil {
+			return err
+		}
+		size := len(key.publicKey) / 2
+		if len(sig.signature) != 2*size {
+			return errors.New("invalid signature length")
+		}
+		r := new(big.Int).SetBytes(sig.signature[:size])
+		s := new(big.Int).SetBytes(sig.signature[size:])
+		if !ecdsa.Verify(pub, digest(h, data), r, s) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	case 15:
+		if len(key.publicKey) != ed25519.PublicKeySize {
+			return errors.New("invalid public key length")
+		}
+		if !ed25519.Verify(key.publicKey, data, sig.signature) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+	return errors.New("unsupported algorithm ", sig.algorithm)
+}
+
+// rsaPublicKey parses the public key of RFC 3110.
+func rsaPublicKey(key []byte) (*rsa.PublicKey, error) {
+	if len(key) < 1 {
+		return nil, errInvalidRDATA
+	}
+	n := int(key[0])
+	key = key[1:]
+	if n == 0 {
+		if len(key) < 2 {
+			return nil, errInvalidRDATA
+		}
+		n = int(binary.BigEndian.Uint16(key))
+		key = key[2:]
+	}
+	if n == 0 || n > 4 || len(key) <= n {
+		return nil, errInvalidRDATA
+	}
+	e := 0
+	for _, b := range key[:n] {
+		e = e<<8 | int(b)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(key[n:]), E: e}, nil
+}
@@ -0,0 +1,134 @@
+package dns
+
+import (
+	"github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/strmatcher"
+	"github.com/xtls/xray-core/features/dns"
+)
+
+// rulesServerName is the server name of answers made by response rules.
+const rulesServerName = "rules"
+
+// responseRule is a ResponseRule with its matchers.
+type responseRule struct {
+	action  ResponseRule_Action
+	domains *strmatcher.MatcherGroup
+	target  string
+	ips     []*router.GeoIPMatcher
+}
+
+func newResponseRules(configs []*ResponseRule, container *router.GeoIPMatcherContainer) ([]*responseRule, error) {
+	rules := make([]*responseRule, 0, len(configs))
+	for _, config := range configs {
+		rule := &responseRule{
+			action: config.Action,
+			target: config.Target,
+		}
+		if len(config.Domain) > 0 {
+			rule.domains = new(strmatcher.MatcherGroup)
+			for _, domain := range config.Domain {
+				matcher, err := toStrMatcher(domain.Type, domain.Domain)
+				if err != nil {
+					return nil, errors.New("failed to create domain matcher").Base(err)
+				}
+				rule.domains.Add(matcher)
+			}
+		}
+		for _, geoip := range config.Ip {
+			matcher, err := container.Add(geoip)
+			if err != nil {
+				return nil, errors.New("failed to create ip matcher").Base(err)
+			}
+			rule.ips = append(rule.ips, matcher)
+		}
+
+		switch {
+		case rule.action == ResponseRule_REWRITE && rule.target == "":
+			return nil, errors.New("no target of rewriting rule")
+		case rule.action == ResponseRule_FILTER_IP && len(rule.ips) == 0:
+			return nil, errors.New("no IP of filtering rule")
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (r *responseRule) matchDomain(domain string) bool {
+	return r.domains == nil || len(r.domains.Match(domain)) > 0
+}
+
+func (r *responseRule) matchIP(ip net.IP) bool {
+	for _, matcher := range r.ips {
+		if matcher.Match(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyQueryRules applies the rules matching domain before it is queried.
+// It returns the domain to query with the option, or the answer made by a
+// rule blocking it. The rules after a REWRITE one match its target instead.
+func (s *DNS) applyQueryRules(domain string, option dns.IPOption) (string, dns.IPOption, *dns.Resolution, error) {
+	for _, rule := range s.rules {
+		if !rule.matchDomain(domain) {
+			continue
+		}
+		switch rule.action {
+		case ResponseRule_BLOCK:
+			errors.LogInfo(s.ctx, "domain ", domain, " blocked by rules")
+			return domain, option, nil, dns.RCodeError(3) // NXDOMAIN
+		case ResponseRule_BLOCK_ZERO:
+			errors.LogInfo(s.ctx, "domain ", domain, " blocked by rules")
+			var ips []net.IP
+			if option.IPv4Enable {
+				ips = append(ips, net.AnyIP.IP())
+			}
+			if option.IPv6Enable {
+				ips = append(ips, net.AnyIPv6.IP())
+			}
+			return domain, option, &dns.Resolution{Domain: domain, IPs: ips, Server: rulesServerName}, nil
+		case ResponseRule_STRIP_AAAA:
+			option.IPv6Enable = false
+			if !option.IPv4Enable {
+				return domain, option, nil, dns.ErrEmptyResponse
+			}
+		case ResponseRule_REWRITE:
+			errors.LogInfo(s.ctx, "domain rewritten: ", domain, " -> ", rule.target)
+			domain = rule.target
+		}
+	}
+	return domain, option, nil, nil
+}
+
+// applyAnswerRules removes the IPs filtered by the rules matching domain,
+// which is the one before applyQueryRules. As in applyQueryRules, the rules
+// after a REWRITE one match its target instead.
+func (s *DNS) applyAnswerRules(domain string, ips []net.IP) ([]net.IP, error) {
+	for _, rule := range s.rules {
+		if !rule.matchDomain(domain) {
+			continue
+		}
+		if rule.action == ResponseRule_REWRITE {
+			domain = rule.target
+		}
+		if rule.action != ResponseRule_FILTER_IP {
+			continue
+		}
+		filtered := ips[:0:0]
+		for _, ip := range ips {
+			if rule.matchIP(ip) {
+				errors.LogDebug(s.ctx, "IP ", ip, " of domain ", domain, " filtered by rules")
+				continue
+			}
+			filtered = append(filtered, ip)
+		}
+		ips = filtered
+	}
+	if len(ips) == 0 {
+		return nil, dns.ErrEmptyResponse
+	}
+	return ips, nil
+}
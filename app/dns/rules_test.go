@@ -0,0 +1,77 @@
+package dns
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+)
+
+func TestResponseRules(t *testing.T) {
+	s := newStubDNS(ParallelQuery_SEQUENTIAL, 0, &stubServer{
+		name: "stub",
+		ips:  []net.IP{{10, 0, 0, 1}, {1, 1, 1, 1}},
+	})
+	subdomain := func(domain string) []*NameServer_PriorityDomain {
+		return []*NameServer_PriorityDomain{{Type: DomainMatchingType_Subdomain, Domain: domain}}
+	}
+	rules, err := newResponseRules([]*ResponseRule{
+		{Domain: subdomain("ads.example.com"), Action: ResponseRule_BLOCK},
+		{Domain: subdomain("tracker.example.com"), Action: ResponseRule_BLOCK_ZERO},
+		{Domain: subdomain("v4.example.com"), Action: ResponseRule_STRIP_AAAA},
+		{Domain: subdomain("old.example.com"), Action: ResponseRule_REWRITE, Target: "new.example.com"},
+		{Domain: subdomain("alias.example.com"), Action: ResponseRule_REWRITE, Target: "filtered.example.com"},
+		{Domain: subdomain("filtered.example.com"), Action: ResponseRule_FILTER_IP, Ip: []*router.GeoIP{{
+			Cidr: []*router.CIDR{{Ip: []byte{1, 1, 1, 1}, Prefix: 32}},
+		}}},
+		{Action: ResponseRule_FILTER_IP, Ip: []*router.GeoIP{{
+			Cidr: []*router.CIDR{{Ip: []byte{10, 0, 0, 0}, Prefix: 8}},
+		}}},
+	}, &router.GeoIPMatcherContainer{})
+	common.Must(err)
+	s.rules = rules
+
+	option := dns_feature.IPOption{IPv4Enable: true, IPv6Enable: true}
+
+	_, err = s.Resolve("www.ads.example.com", option)
+	if rcode := dns_feature.RCodeFromError(err); rcode != 3 {
+		t.Error("expected NXDOMAIN, actual ", err)
+	}
+
+	resolution, err := s.Resolve("tracker.example.com", option)
+	common.Must(err)
+	if r := cmp.Diff(resolution.IPs, []net.IP{net.AnyIP.IP(), net.AnyIPv6.IP()}); r != "" {
+		t.Error(r)
+	}
+
+	if _, err := s.Resolve("v4.example.com", dns_feature.IPOption{IPv6Enable: true}); err != dns_feature.ErrEmptyResponse {
+		t.Error("expected empty response, actual ", err)
+	}
+
+	resolution, err = s.Resolve("old.example.com", option)
+	common.Must(err)
+	if resolution.Domain != "new.example.com" || !resolution.Rewritten {
+		t.Error("expected rewritten domain, actual ", resolution.Domain)
+	}
+	if r := cmp.Diff(resolution.IPs, []net.IP{{1, 1, 1, 1}}); r != "" {
+		t.Error(r)
+	}
+
+	// rules after rewriting match the target, for answers as well
+	if _, err := s.Resolve("alias.example.com", option); err != dns_feature.ErrEmptyResponse {
+		t.Error("expected answers of target filtered, actual ", err)
+	}
+
+	resolution, err = s.Resolve("www.example.com", option)
+	common.Must(err)
+	if resolution.Rewritten {
+		t.Error("unexpected rewritten domain ", resolution.Domain)
+	}
+
+	if _, err := newResponseRules([]*ResponseRule{{Action: ResponseRule_REWRITE}}, &router.GeoIPMatcherContainer{}); err == nil {
+		t.Error("expected error of rewriting rule without target")
+	}
+}
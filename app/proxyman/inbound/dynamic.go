@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/xtls/xray-core/app/proxyman"
+	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/dice"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/mux"
@@ -14,6 +15,7 @@ import (
 	"github.com/xtls/xray-core/core"
 	"github.com/xtls/xray-core/proxy"
 	"github.com/xtls/xray-core/transport/internet"
+	"github.com/xtls/xray-core/transport/internet/tls"
 )
 
 type DynamicInboundHandler struct {
@@ -29,6 +31,7 @@ type DynamicInboundHandler struct {
 	lastRefresh    time.Time
 	mux            *mux.Server
 	task           *task.Periodic
+	acme           common.Closable
 
 	ctx context.Context
 }
@@ -183,10 +186,14 @@ func (h *DynamicInboundHandler) refresh() error {
 }
 
 func (h *DynamicInboundHandler) Start() error {
+	h.acme = tls.ConfigFromStreamSettings(h.streamSettings).StartACME()
 	return h.task.Start()
 }
 
 func (h *DynamicInboundHandler) Close() error {
+	if h.acme != nil {
+		h.acme.Close()
+	}
 	return h.task.Close()
 }
 
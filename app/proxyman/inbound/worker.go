@@ -12,6 +12,7 @@ import (
 	c "github.com/xtls/xray-core/common/ctx"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol"
 	"github.com/xtls/xray-core/common/serial"
 	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/common/signal/done"
@@ -22,10 +23,15 @@ import (
 	"github.com/xtls/xray-core/transport/internet"
 	"github.com/xtls/xray-core/transport/internet/stat"
 	"github.com/xtls/xray-core/transport/internet/tcp"
+	"github.com/xtls/xray-core/transport/internet/tls"
 	"github.com/xtls/xray-core/transport/internet/udp"
 	"github.com/xtls/xray-core/transport/pipe"
 )
 
+// tlsHandshakeTimeout is the timeout of TLS handshakes to map client
+// certificates to users.
+const tlsHandshakeTimeout = 8 * time.Second
+
 type worker interface {
 	Start() error
 	Close() error
@@ -89,12 +95,23 @@ func (w *tcpWorker) callback(conn stat.Connection) {
 			WriteCounter: w.downlinkCounter,
 		}
 	}
-	ctx = session.ContextWithInbound(ctx, &session.Inbound{
+	inbound := &session.Inbound{
 		Source:  net.DestinationFromAddr(conn.RemoteAddr()),
 		Gateway: net.TCPDestination(w.address, w.port),
 		Tag:     w.tag,
 		Conn:    conn,
-	})
+	}
+	if tlsConfig := tls.ConfigFromStreamSettings(w.stream); tlsConfig != nil && len(tlsConfig.ClientUser) > 0 {
+		user, err := tlsClientUser(ctx, conn, tlsConfig)
+		if err != nil {
+			errors.LogInfoInner(ctx, err, "connection ends")
+			cancel()
+			conn.Close()
+			return
+		}
+		inbound.User = user
+	}
+	ctx = session.ContextWithInbound(ctx, inbound)
 
 	content := new(session.Content)
 	if w.sniffingConfig != nil {
@@ -113,6 +130,25 @@ func (w *tcpWorker) callback(conn stat.Connection) {
 	conn.Close()
 }
 
+// tlsClientUser completes the TLS handshake of conn to get the user its
+// client certificate is mapped to. Only conn of RAW transport is a TLS
+// connection, as the config rejects client users on other transports.
+func tlsClientUser(ctx context.Context, conn stat.Connection, config *tls.Config) (*protocol.MemoryUser, error) {
+	if statConn, ok := conn.(*stat.CounterConnection); ok {
+		conn = statConn.Connection
+	}
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, tlsHandshakeTimeout)
+	defer cancel()
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return nil, errors.New("failed to complete TLS handshake").Base(err)
+	}
+	return config.ClientUserOf(tlsConn.ConnectionState()), nil
+}
+
 func (w *tcpWorker) Proxy() proxy.Inbound {
 	return w.proxy
 }
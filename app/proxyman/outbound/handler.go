@@ -91,6 +91,10 @@ func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (outbou
 			if err != nil {
 				return nil, errors.New("failed to parse stream settings").Base(err).AtWarning()
 			}
+			if mss.Prewarm.GetSize() > 0 {
+				// prewarmed connections outlive the contexts they are dialed for
+				mss.PrewarmPools = internet.NewPrewarmPools(core.ToBackgroundDetachedContext(ctx))
+			}
 			h.streamSettings = mss
 		default:
 			return nil, errors.New("settings is not SenderConfig")
@@ -199,11 +203,17 @@ func (h *Handler) Dispatch(ctx context.Context, link *transport.Link) {
 			if !h.xudp.Enabled {
 				goto out
 			}
-			test(h.xudp.Dispatch(ctx, link))
+			err := h.xudp.Dispatch(ctx, link)
+			session.SubmitDialResult(ctx, err)
+			test(err)
 			return
 		}
 		if h.mux.Enabled {
-			test(h.mux.Dispatch(ctx, link))
+			// mux workers dial on their own, so a session dispatched to one
+			// counts as connected
+			err := h.mux.Dispatch(ctx, link)
+			session.SubmitDialResult(ctx, err)
+			test(err)
 			return
 		}
 	}
@@ -238,6 +248,15 @@ func (h *Handler) DestIpAddress() net.IP {
 	return internet.DestIpAddress()
 }
 
+// Proxied returns whether connections dialed by the handler are forwarded
+// through another outbound handler.
+func (h *Handler) Proxied() bool {
+	if h.senderSettings != nil && h.senderSettings.ProxySettings.HasTag() {
+		return true
+	}
+	return h.streamSettings != nil && h.streamSettings.SocketSettings != nil && len(h.streamSettings.SocketSettings.DialerProxy) > 0
+}
+
 // Dial implements internet.Dialer.
 func (h *Handler) Dial(ctx context.Context, dest net.Destination) (stat.Connection, error) {
 	if h.senderSettings != nil {
@@ -251,6 +270,8 @@ func (h *Handler) Dial(ctx context.Context, dest net.Destination) (stat.Connecti
 					Target: dest,
 					Tag:    tag,
 				})) // add another outbound in session ctx
+				// the dial result of the chained outbound is submitted as ours,
+				// as the connection goes through it
 				opts := pipe.OptionsFromContext(ctx)
 				uplinkReader, uplinkWriter := pipe.New(opts...)
 				downlinkReader, downlinkWriter := pipe.New(opts...)
@@ -259,7 +280,7 @@ func (h *Handler) Dial(ctx context.Context, dest net.Destination) (stat.Connecti
 				conn := cnc.NewConnection(cnc.ConnectionInputMulti(uplinkWriter), cnc.ConnectionOutputMulti(downlinkReader))
 
 				if config := tls.ConfigFromStreamSettings(h.streamSettings); config != nil {
-					tlsConfig := config.GetTLSConfig(tls.WithDestination(dest))
+					tlsConfig := config.GetTLSConfig(tls.WithContext(ctx), tls.WithDestination(dest))
 					conn = tls.Client(conn, tlsConfig)
 				}
 
@@ -281,10 +302,20 @@ func (h *Handler) Dial(ctx context.Context, dest net.Destination) (stat.Connecti
 	}
 
 	if conn, err := h.getUoTConnection(ctx, dest); err != os.ErrInvalid {
+		session.SubmitDialResult(ctx, err)
 		return conn, err
 	}
 
 	conn, err := internet.Dial(ctx, dest, h.streamSettings)
+	if tlsConn, ok := conn.(tls.Interface); ok && err == nil {
+		// a TLS handshake is lazily done on the first read or write, whose
+		// failure must be part of the dial result
+		if err = tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			conn, err = nil, errors.New("failed to handshake with ", dest).Base(err)
+		}
+	}
+	session.SubmitDialResult(ctx, err)
 	conn = h.getStatCouterConnection(conn)
 	outbounds := session.OutboundsFromContext(ctx)
 	ob := outbounds[len(outbounds)-1]
@@ -316,6 +347,9 @@ func (h *Handler) Start() error {
 // Close implements common.Closable.
 func (h *Handler) Close() error {
 	common.Close(h.mux)
+	if h.streamSettings != nil && h.streamSettings.PrewarmPools != nil {
+		h.streamSettings.PrewarmPools.Close()
+	}
 	return nil
 }
 
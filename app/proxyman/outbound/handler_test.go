@@ -18,7 +18,10 @@ import (
 	core "github.com/xtls/xray-core/core"
 	"github.com/xtls/xray-core/features/outbound"
 	"github.com/xtls/xray-core/proxy/freedom"
+	"github.com/xtls/xray-core/transport/internet"
 	"github.com/xtls/xray-core/transport/internet/stat"
+	_ "github.com/xtls/xray-core/transport/internet/tcp"
+	"github.com/xtls/xray-core/transport/internet/tls"
 )
 
 func TestInterfaces(t *testing.T) {
@@ -87,6 +90,94 @@ func TestOutboundWithStatCounter(t *testing.T) {
 	}
 }
 
+func TestOutboundProxied(t *testing.T) {
+	v, _ := core.New(&core.Config{})
+	v.AddFeature((outbound.Manager)(new(Manager)))
+	ctx := context.WithValue(context.Background(), xrayKey, v)
+
+	newHandler := func(senderSettings *proxyman.SenderConfig) *Handler {
+		h, err := NewHandler(ctx, &core.OutboundHandlerConfig{
+			Tag:            "tag",
+			SenderSettings: serial.ToTypedMessage(senderSettings),
+			ProxySettings:  serial.ToTypedMessage(&freedom.Config{}),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h.(*Handler)
+	}
+
+	if newHandler(&proxyman.SenderConfig{}).Proxied() {
+		t.Error("expected handler not proxied")
+	}
+	if !newHandler(&proxyman.SenderConfig{
+		ProxySettings: &internet.ProxyConfig{Tag: "proxy"},
+	}).Proxied() {
+		t.Error("expected handler proxied by proxySettings")
+	}
+	if !newHandler(&proxyman.SenderConfig{
+		StreamSettings: &internet.StreamConfig{
+			SocketSettings: &internet.SocketConfig{DialerProxy: "proxy"},
+		},
+	}).Proxied() {
+		t.Error("expected handler proxied by dialerProxy")
+	}
+}
+
+type dialResults []error
+
+func (r *dialResults) SubmitDialResult(err error) {
+	*r = append(*r, err)
+}
+
+func TestOutboundDialTLSHandshake(t *testing.T) {
+	// the server accepts TCP connections but never completes TLS handshakes
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	v, _ := core.New(&core.Config{})
+	v.AddFeature((outbound.Manager)(new(Manager)))
+	ctx := context.WithValue(context.Background(), xrayKey, v)
+	h, err := NewHandler(ctx, &core.OutboundHandlerConfig{
+		Tag: "tag",
+		SenderSettings: serial.ToTypedMessage(&proxyman.SenderConfig{
+			StreamSettings: &internet.StreamConfig{
+				ProtocolName:     "tcp",
+				SecurityType:     serial.GetMessageType(&tls.Config{}),
+				SecuritySettings: []*serial.TypedMessage{serial.ToTypedMessage(&tls.Config{AllowInsecure: true})},
+			},
+		}),
+		ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results dialResults
+	ctx = session.ContextWithOutbounds(ctx, []*session.Outbound{{}})
+	ctx = session.ContextWithDialFeedback(ctx, &results)
+	dest := net.DestinationFromAddr(listener.Addr())
+	if conn, err := h.(*Handler).Dial(ctx, dest); err == nil {
+		conn.Close()
+		t.Error("expected dial failed with TLS handshake")
+	}
+	if len(results) != 1 || results[0] == nil {
+		t.Error("expected failed dial result, actual ", results)
+	}
+}
+
 func TestTagsCache(t *testing.T) {
 
 	test_duration := 10 * time.Second
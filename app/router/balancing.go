@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"slices"
 	sync "sync"
 
 	"github.com/xtls/xray-core/app/observatory"
@@ -10,12 +11,30 @@ import (
 	"github.com/xtls/xray-core/core"
 	"github.com/xtls/xray-core/features/extension"
 	"github.com/xtls/xray-core/features/outbound"
+	"github.com/xtls/xray-core/features/routing"
 )
 
 type BalancingStrategy interface {
 	PickOutbound([]string) string
 }
 
+// ContextualBalancingStrategy is a BalancingStrategy that picks outbounds
+// according to the routing context of the connection.
+type ContextualBalancingStrategy interface {
+	PickOutboundWithContext(routing.Context, []string) string
+}
+
+// PeekingBalancingStrategy is a BalancingStrategy with state, which can tell
+// the outbound it would pick without advancing the state.
+type PeekingBalancingStrategy interface {
+	PeekOutbound([]string) string
+}
+
+// BalancingWeightSetter is a BalancingStrategy whose weights can be adjusted at runtime.
+type BalancingWeightSetter interface {
+	SetWeight(tag string, weight float64)
+}
+
 type BalancingPrincipleTarget interface {
 	GetPrincipleTarget([]string) []string
 }
@@ -82,17 +101,42 @@ func (s *RoundRobinStrategy) PickOutbound(tags []string) string {
 	return tag
 }
 
+// PeekOutbound implements PeekingBalancingStrategy.
+func (s *RoundRobinStrategy) PeekOutbound(tags []string) string {
+	tags = filterAliveOutbounds(s.ctx, s.observatory, tags)
+	n := len(tags)
+	if n == 0 {
+		return ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return tags[s.index%n]
+}
+
 type Balancer struct {
-	selectors   []string
-	strategy    BalancingStrategy
-	ohm         outbound.Manager
-	fallbackTag string
+	selectors     []string
+	strategy      BalancingStrategy
+	ohm           outbound.Manager
+	fallbackTag   string
+	fallbackChain *FallbackChain
+	health        *HealthTracker
 
 	override override
 }
 
 // PickOutbound picks the tag of a outbound
-func (b *Balancer) PickOutbound() (string, error) {
+func (b *Balancer) PickOutbound(ctx routing.Context) (string, error) {
+	return b.pickOutbound(ctx, false)
+}
+
+// PeekOutbound returns the tag of the outbound PickOutbound would pick,
+// without advancing the state of the strategy or starting a health trial.
+func (b *Balancer) PeekOutbound(ctx routing.Context) (string, error) {
+	return b.pickOutbound(ctx, true)
+}
+
+func (b *Balancer) pickOutbound(ctx routing.Context, peek bool) (string, error) {
 	candidates, err := b.SelectOutbounds()
 	if err != nil {
 		if b.fallbackTag != "" {
@@ -101,11 +145,13 @@ func (b *Balancer) PickOutbound() (string, error) {
 		}
 		return "", err
 	}
+	// skip outbounds ejected by passive health detection
+	candidates = b.health.Filter(candidates)
 	var tag string
 	if o := b.override.Get(); o != "" {
 		tag = o
 	} else {
-		tag = b.strategy.PickOutbound(candidates)
+		tag = b.pickCandidate(ctx, candidates, peek)
 	}
 	if tag == "" {
 		if b.fallbackTag != "" {
@@ -118,12 +164,60 @@ func (b *Balancer) PickOutbound() (string, error) {
 	return tag, nil
 }
 
+// pickCandidate picks one of candidates with the strategy. An ejected outbound
+// picked for a trial which is already taken by another pick is skipped.
+func (b *Balancer) pickCandidate(ctx routing.Context, candidates []string, peek bool) string {
+	for len(candidates) > 0 {
+		var tag string
+		if s, ok := b.strategy.(ContextualBalancingStrategy); ok && ctx != nil {
+			tag = s.PickOutboundWithContext(ctx, candidates)
+		} else if s, ok := b.strategy.(PeekingBalancingStrategy); ok && peek {
+			tag = s.PeekOutbound(candidates)
+		} else {
+			tag = b.strategy.PickOutbound(candidates)
+		}
+		if tag == "" || peek || b.health == nil || b.health.Picked(tag) {
+			return tag
+		}
+		candidates = slices.DeleteFunc(slices.Clone(candidates), func(c string) bool { return c == tag })
+	}
+	// all outbounds are ejected, goes to fallbackTag
+	return ""
+}
+
 func (b *Balancer) InjectContext(ctx context.Context) {
 	if contextReceiver, ok := b.strategy.(extension.ContextReceiver); ok {
 		contextReceiver.InjectContext(ctx)
 	}
 }
 
+// filterAliveOutbounds removes the tags reported dead by the observatory.
+// Tags unknown to the observatory are considered alive.
+func filterAliveOutbounds(ctx context.Context, ob extension.Observatory, tags []string) []string {
+	if ob == nil {
+		return tags
+	}
+	observeReport, err := ob.GetObservation(ctx)
+	if err != nil {
+		return tags
+	}
+	result, ok := observeReport.(*observatory.ObservationResult)
+	if !ok {
+		return tags
+	}
+	statusMap := make(map[string]*observatory.OutboundStatus)
+	for _, outboundStatus := range result.Status {
+		statusMap[outboundStatus.OutboundTag] = outboundStatus
+	}
+	aliveTags := make([]string, 0, len(tags))
+	for _, candidate := range tags {
+		if outboundStatus, found := statusMap[candidate]; !found || outboundStatus.Alive {
+			aliveTags = append(aliveTags, candidate)
+		}
+	}
+	return aliveTags
+}
+
 // SelectOutbounds select outbounds with selectors of the Balancer
 func (b *Balancer) SelectOutbounds() ([]string, error) {
 	hs, ok := b.ohm.(outbound.HandlerSelector)
@@ -158,6 +252,18 @@ func (r *Router) SetOverrideTarget(tag, target string) error {
 	return errors.New("cannot find tag")
 }
 
+// SetBalancerWeight implements routing.BalancerWeightSetter
+func (r *Router) SetBalancerWeight(tag, target string, weight float64) error {
+	if b, ok := r.balancers[tag]; ok {
+		if s, ok := b.strategy.(BalancingWeightSetter); ok {
+			s.SetWeight(target, weight)
+			return nil
+		}
+		return errors.New("balancer ", tag, " does not support weights")
+	}
+	return errors.New("cannot find tag")
+}
+
 // GetOverrideTarget implements routing.BalancerOverrider
 func (r *Router) GetOverrideTarget(tag string) (string, error) {
 	if b, ok := r.balancers[tag]; ok {
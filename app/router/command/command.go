@@ -53,6 +53,13 @@ func (s *routingServer) OverrideBalancerTarget(ctx context.Context, request *Ove
 	return nil, errors.New("unsupported router implementation")
 }
 
+func (s *routingServer) SetBalancerWeight(ctx context.Context, request *SetBalancerWeightRequest) (*SetBalancerWeightResponse, error) {
+	if bw, ok := s.router.(routing.BalancerWeightSetter); ok {
+		return &SetBalancerWeightResponse{}, bw.SetBalancerWeight(request.BalancerTag, request.OutboundTag, float64(request.Weight))
+	}
+	return nil, errors.New("unsupported router implementation")
+}
+
 func (s *routingServer) AddRule(ctx context.Context, request *AddRuleRequest) (*AddRuleResponse, error) {
 	if bo, ok := s.router.(routing.Router); ok {
 		return &AddRuleResponse{}, bo.AddRule(request.Config, request.ShouldAppend)
@@ -90,6 +97,54 @@ func (s *routingServer) TestRoute(ctx context.Context, request *TestRouteRequest
 	return AsProtobufMessage(request.FieldSelectors)(route), nil
 }
 
+func (s *routingServer) ExplainRoute(ctx context.Context, request *ExplainRouteRequest) (*ExplainRouteResponse, error) {
+	if request.RoutingContext == nil {
+		return nil, errors.New("Invalid routing request.")
+	}
+	re, ok := s.router.(routing.RouteExplainer)
+	if !ok {
+		return nil, errors.New("unsupported router implementation")
+	}
+	explanation, err := re.ExplainRoute(AsRoutingContext(request.RoutingContext))
+	if err != nil {
+		return nil, err
+	}
+	resp := &ExplainRouteResponse{}
+	if explanation.Route != nil {
+		resp.Route = AsProtobufMessage(request.FieldSelectors)(explanation.Route)
+		if fr, ok := explanation.Route.(routing.FallbackRoute); ok {
+			resp.FallbackTags = fr.GetFallbackTags()
+		}
+	}
+	for _, rule := range explanation.Rules {
+		r := &RuleExplanation{
+			RuleTag:      rule.RuleTag,
+			OutboundTag:  rule.OutboundTag,
+			BalancerTag:  rule.BalancerTag,
+			Matched:      rule.Matched,
+			AfterResolve: rule.AfterResolve,
+		}
+		for _, cond := range rule.Conditions {
+			r.Conditions = append(r.Conditions, &ConditionExplanation{
+				Name:    cond.Name,
+				Matched: cond.Matched,
+			})
+		}
+		resp.Rules = append(resp.Rules, r)
+	}
+	for _, resolution := range explanation.Resolutions {
+		r := &DNSResolution{
+			Domain: resolution.Domain,
+			Ips:    mapIPsToBytes(resolution.IPs),
+		}
+		if resolution.Err != nil {
+			r.Error = resolution.Err.Error()
+		}
+		resp.Resolutions = append(resp.Resolutions, r)
+	}
+	return resp, nil
+}
+
 func (s *routingServer) SubscribeRoutingStats(request *SubscribeRoutingStatsRequest, stream RoutingService_SubscribeRoutingStatsServer) error {
 	if s.routingStats == nil {
 		return errors.New("Routing statistics not enabled.")
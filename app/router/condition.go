@@ -152,6 +152,30 @@ func (m *MultiGeoIPMatcher) Apply(ctx routing.Context) bool {
 	return false
 }
 
+// ASNMatcher matches source or destination IPs against the prefixes announced
+// by a set of autonomous systems.
+type ASNMatcher struct {
+	MultiGeoIPMatcher
+}
+
+func NewASNMatcher(asns []*GeoIP, onSource bool) (*ASNMatcher, error) {
+	var matchers []*GeoIPMatcher
+	for _, asn := range asns {
+		matcher, err := globalASNContainer.Add(asn)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+	}
+
+	return &ASNMatcher{
+		MultiGeoIPMatcher: MultiGeoIPMatcher{
+			matchers: matchers,
+			onSource: onSource,
+		},
+	}, nil
+}
+
 type PortMatcher struct {
 	port     net.MemoryPortList
 	onSource bool
@@ -115,4 +115,7 @@ func (c *GeoIPMatcherContainer) Add(geoip *GeoIP) (*GeoIPMatcher, error) {
 	return m, nil
 }
 
-var globalGeoIPContainer GeoIPMatcherContainer
+var (
+	globalGeoIPContainer GeoIPMatcherContainer
+	globalASNContainer   GeoIPMatcherContainer
+)
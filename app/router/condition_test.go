@@ -155,6 +155,61 @@ func TestRoutingRule(t *testing.T) {
 				},
 			},
 		},
+		{
+			rule: &RoutingRule{
+				Asn: []*GeoIP{
+					{
+						CountryCode: "AS13335",
+						Cidr: []*CIDR{
+							{
+								Ip:     []byte{1, 1, 1, 0},
+								Prefix: 24,
+							},
+						},
+					},
+				},
+			},
+			test: []ruleTest{
+				{
+					input:  withOutbound(&session.Outbound{Target: net.TCPDestination(net.ParseAddress("1.1.1.1"), 80)}),
+					output: true,
+				},
+				{
+					input:  withOutbound(&session.Outbound{Target: net.TCPDestination(net.ParseAddress("8.8.8.8"), 80)}),
+					output: false,
+				},
+				{
+					input:  withBackground(),
+					output: false,
+				},
+			},
+		},
+		{
+			rule: &RoutingRule{
+				SourceAsn: []*GeoIP{
+					{
+						CountryCode:  "AS64512",
+						ReverseMatch: true,
+						Cidr: []*CIDR{
+							{
+								Ip:     []byte{10, 0, 0, 0},
+								Prefix: 8,
+							},
+						},
+					},
+				},
+			},
+			test: []ruleTest{
+				{
+					input:  withInbound(&session.Inbound{Source: net.TCPDestination(net.ParseAddress("192.168.0.1"), 80)}),
+					output: true,
+				},
+				{
+					input:  withInbound(&session.Inbound{Source: net.TCPDestination(net.ParseAddress("10.0.0.1"), 80)}),
+					output: false,
+				},
+			},
+		},
 		{
 			rule: &RoutingRule{
 				UserEmail: []string{
@@ -11,19 +11,38 @@ import (
 )
 
 type Rule struct {
-	Tag       string
-	RuleTag   string
-	Balancer  *Balancer
-	Condition Condition
+	Tag           string
+	RuleTag       string
+	Balancer      *Balancer
+	Condition     Condition
+	FallbackChain *FallbackChain
 }
 
-func (r *Rule) GetTag() (string, error) {
+func (r *Rule) GetTag(ctx routing.Context) (string, error) {
 	if r.Balancer != nil {
-		return r.Balancer.PickOutbound()
+		return r.Balancer.PickOutbound(ctx)
 	}
 	return r.Tag, nil
 }
 
+// PeekTag returns the tag GetTag would return, without side effects on the
+// balancer of the rule.
+func (r *Rule) PeekTag(ctx routing.Context) (string, error) {
+	if r.Balancer != nil {
+		return r.Balancer.PeekOutbound(ctx)
+	}
+	return r.Tag, nil
+}
+
+// GetFallbackChain returns the fallback chain of the balancer of the rule if
+// set, or else the fallback chain of the rule.
+func (r *Rule) GetFallbackChain() *FallbackChain {
+	if r.Balancer != nil && r.Balancer.fallbackChain != nil {
+		return r.Balancer.fallbackChain
+	}
+	return r.FallbackChain
+}
+
 // Apply checks rule matching of current routing context.
 func (r *Rule) Apply(ctx routing.Context) bool {
 	return r.Condition.Apply(ctx)
@@ -88,6 +107,22 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 		conds.Add(cond)
 	}
 
+	if len(rr.Asn) > 0 {
+		cond, err := NewASNMatcher(rr.Asn, false)
+		if err != nil {
+			return nil, err
+		}
+		conds.Add(cond)
+	}
+
+	if len(rr.SourceAsn) > 0 {
+		cond, err := NewASNMatcher(rr.SourceAsn, true)
+		if err != nil {
+			return nil, err
+		}
+		conds.Add(cond)
+	}
+
 	if len(rr.Protocol) > 0 {
 		conds.Add(NewProtocolMatcher(rr.Protocol))
 	}
@@ -140,6 +175,42 @@ func (br *BalancingRule) Build(ohm outbound.Manager, dispatcher routing.Dispatch
 			fallbackTag: br.FallbackTag,
 			strategy:    leastLoadStrategy,
 		}, nil
+	case "weightedroundrobin":
+		var s *StrategyWeightedRoundRobinConfig
+		if br.StrategySettings != nil {
+			i, err := br.StrategySettings.GetInstance()
+			if err != nil {
+				return nil, err
+			}
+			var ok bool
+			if s, ok = i.(*StrategyWeightedRoundRobinConfig); !ok {
+				return nil, errors.New("not a StrategyWeightedRoundRobinConfig").AtError()
+			}
+		}
+		return &Balancer{
+			selectors:   br.OutboundSelector,
+			ohm:         ohm,
+			fallbackTag: br.FallbackTag,
+			strategy:    NewWeightedRoundRobinStrategy(s),
+		}, nil
+	case "consistenthash":
+		var s *StrategyConsistentHashConfig
+		if br.StrategySettings != nil {
+			i, err := br.StrategySettings.GetInstance()
+			if err != nil {
+				return nil, err
+			}
+			var ok bool
+			if s, ok = i.(*StrategyConsistentHashConfig); !ok {
+				return nil, errors.New("not a StrategyConsistentHashConfig").AtError()
+			}
+		}
+		return &Balancer{
+			selectors:   br.OutboundSelector,
+			ohm:         ohm,
+			fallbackTag: br.FallbackTag,
+			strategy:    NewConsistentHashStrategy(s),
+		}, nil
 	case "random":
 		fallthrough
 	case "":
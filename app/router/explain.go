@@ -0,0 +1,129 @@
+package router
+
+import (
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/features/routing"
+	routing_dns "github.com/xtls/xray-core/features/routing/dns"
+)
+
+// conditionName returns the config field name of a condition.
+func conditionName(cond Condition) string {
+	switch c := cond.(type) {
+	case *DomainMatcher:
+		return "domain"
+	case *ASNMatcher:
+		if c.onSource {
+			return "sourceAsn"
+		}
+		return "asn"
+	case *MultiGeoIPMatcher:
+		if c.onSource {
+			return "source"
+		}
+		return "ip"
+	case *PortMatcher:
+		if c.onSource {
+			return "sourcePort"
+		}
+		return "port"
+	case NetworkMatcher:
+		return "network"
+	case *UserMatcher:
+		return "user"
+	case *InboundTagMatcher:
+		return "inboundTag"
+	case *ProtocolMatcher:
+		return "protocol"
+	case *AttributeMatcher:
+		return "attrs"
+	default:
+		return "unknown"
+	}
+}
+
+// dnsRecorder records lookups made through the wrapped DNS client.
+type dnsRecorder struct {
+	dns.Client
+	resolutions []*routing.DNSResolution
+}
+
+// LookupIP implements dns.Client.
+func (d *dnsRecorder) LookupIP(domain string, option dns.IPOption) ([]net.IP, error) {
+	ips, err := d.Client.LookupIP(domain, option)
+	d.resolutions = append(d.resolutions, &routing.DNSResolution{
+		Domain: domain,
+		IPs:    ips,
+		Err:    err,
+	})
+	return ips, err
+}
+
+func (r *Router) explainRule(ctx routing.Context, rule *Rule, afterResolve bool) *routing.RuleExplanation {
+	exp := &routing.RuleExplanation{
+		RuleTag:      rule.RuleTag,
+		OutboundTag:  rule.Tag,
+		AfterResolve: afterResolve,
+		Matched:      true,
+	}
+	for tag, balancer := range r.balancers {
+		if balancer == rule.Balancer {
+			exp.BalancerTag = tag
+		}
+	}
+	conds := []Condition{rule.Condition}
+	if chain, ok := rule.Condition.(*ConditionChan); ok {
+		conds = *chain
+	}
+	for _, cond := range conds {
+		matched := cond.Apply(ctx)
+		exp.Conditions = append(exp.Conditions, &routing.ConditionExplanation{
+			Name:    conditionName(cond),
+			Matched: matched,
+		})
+		exp.Matched = exp.Matched && matched
+	}
+	return exp
+}
+
+// ExplainRoute implements routing.RouteExplainer.
+func (r *Router) ExplainRoute(ctx routing.Context) (*routing.RouteExplanation, error) {
+	explanation := new(routing.RouteExplanation)
+	recorder := &dnsRecorder{Client: r.dns}
+	defer func() {
+		explanation.Resolutions = recorder.resolutions
+	}()
+
+	skipDNSResolve := ctx.GetSkipDNSResolve()
+	if r.domainStrategy == Config_IpOnDemand && !skipDNSResolve {
+		ctx = routing_dns.ContextWithDNSClient(ctx, recorder)
+	}
+
+	pick := func(afterResolve bool) *Rule {
+		for _, rule := range r.rules {
+			exp := r.explainRule(ctx, rule, afterResolve)
+			explanation.Rules = append(explanation.Rules, exp)
+			if exp.Matched {
+				return rule
+			}
+		}
+		return nil
+	}
+
+	rule := pick(false)
+	if rule == nil && r.domainStrategy == Config_IpIfNonMatch && len(ctx.GetTargetDomain()) > 0 && !skipDNSResolve {
+		ctx = routing_dns.ContextWithDNSClient(ctx, recorder)
+		rule = pick(true)
+	}
+	if rule == nil {
+		return explanation, nil
+	}
+
+	// explaining must not advance balancers as picking does
+	tag, err := rule.PeekTag(ctx)
+	if err != nil {
+		return nil, err
+	}
+	explanation.Route = &Route{Context: ctx, outboundTag: tag, ruleTag: rule.RuleTag, fallbackChain: rule.GetFallbackChain()}
+	return explanation, nil
+}
@@ -0,0 +1,149 @@
+package router
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+)
+
+const defaultHealthCooldown = 30 * time.Second
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+type outboundHealth struct {
+	failures int
+	state    breakerState
+	// openedAt is the time the breaker opened, or the time the latest
+	// half-open trial started
+	openedAt time.Time
+}
+
+// HealthTracker is a circuit breaker of outbounds fed by passive health
+// reports. An outbound is ejected after consecutive failures, and a single
+// trial connection is let through once the cooldown is over. The outbound is
+// restored if the trial succeeds, and ejected again otherwise.
+type HealthTracker struct {
+	maxFailures int
+	cooldown    time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	statuses map[string]*outboundHealth
+}
+
+// NewHealthTracker creates a new HealthTracker with config. It returns nil if
+// passive health detection is disabled.
+func NewHealthTracker(config *PassiveHealthConfig) *HealthTracker {
+	if config == nil || config.MaxFailures == 0 {
+		return nil
+	}
+	cooldown := time.Duration(config.Cooldown)
+	if cooldown <= 0 {
+		cooldown = defaultHealthCooldown
+	}
+	return &HealthTracker{
+		maxFailures: int(config.MaxFailures),
+		cooldown:    cooldown,
+		now:         time.Now,
+		statuses:    make(map[string]*outboundHealth),
+	}
+}
+
+// ReportSuccess closes the breaker of the outbound.
+func (t *HealthTracker) ReportSuccess(tag string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if h, found := t.statuses[tag]; found {
+		if h.state != breakerClosed {
+			errors.LogInfo(context.Background(), "passive health: outbound [", tag, "] recovered")
+		}
+		delete(t.statuses, tag)
+	}
+}
+
+// ReportFailure counts a failure of the outbound, and opens its breaker on
+// too many consecutive failures or a failed trial.
+func (t *HealthTracker) ReportFailure(tag string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h, found := t.statuses[tag]
+	if !found {
+		h = new(outboundHealth)
+		t.statuses[tag] = h
+	}
+	h.failures++
+	if h.state == breakerHalfOpen || (h.state == breakerClosed && h.failures >= t.maxFailures) {
+		h.state = breakerOpen
+		h.openedAt = t.now()
+		errors.LogWarningInner(context.Background(), err, "passive health: outbound [", tag, "] ejected after ", h.failures, " consecutive failures")
+	}
+}
+
+// Available reports whether the outbound can be picked. An ejected outbound
+// becomes available for a trial once the cooldown is over.
+func (t *HealthTracker) Available(tag string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h, found := t.statuses[tag]
+	if !found || h.state == breakerClosed {
+		return true
+	}
+	// for a half-open breaker, the previous trial is considered lost if it
+	// has not reported within the cooldown
+	return t.now().Sub(h.openedAt) >= t.cooldown
+}
+
+// Picked records that the outbound is picked, and returns whether it can be
+// used. The pick of an ejected outbound starts a trial, and concurrent picks
+// are refused until the trial reports, or is considered lost after the
+// cooldown.
+func (t *HealthTracker) Picked(tag string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h, found := t.statuses[tag]
+	if !found || h.state == breakerClosed {
+		return true
+	}
+	if t.now().Sub(h.openedAt) < t.cooldown {
+		return false
+	}
+	h.state = breakerHalfOpen
+	h.openedAt = t.now()
+	return true
+}
+
+// Filter removes ejected outbounds from tags.
+func (t *HealthTracker) Filter(tags []string) []string {
+	if t == nil {
+		return tags
+	}
+	available := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if t.Available(tag) {
+			available = append(available, tag)
+		}
+	}
+	return available
+}
+
+// ReportSuccess implements routing.HealthReporter.
+func (r *Router) ReportSuccess(tag string) {
+	if r.health != nil {
+		r.health.ReportSuccess(tag)
+	}
+}
+
+// ReportFailure implements routing.HealthReporter.
+func (r *Router) ReportFailure(tag string, err error) {
+	if r.health != nil {
+		r.health.ReportFailure(tag, err)
+	}
+}
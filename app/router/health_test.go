@@ -0,0 +1,77 @@
+package router
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHealthTrackerDisabled(t *testing.T) {
+	if tracker := NewHealthTracker(&PassiveHealthConfig{}); tracker != nil {
+		t.Error("expected disabled tracker")
+	}
+	var tracker *HealthTracker
+	tags := []string{"a", "b"}
+	if r := cmp.Diff(tracker.Filter(tags), tags); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestHealthTrackerCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	tracker := NewHealthTracker(&PassiveHealthConfig{
+		MaxFailures: 2,
+		Cooldown:    int64(time.Minute),
+	})
+	tracker.now = func() time.Time { return now }
+	errDial := errors.New("dial failed")
+	tags := []string{"a", "b"}
+
+	tracker.ReportFailure("a", errDial)
+	if r := cmp.Diff(tracker.Filter(tags), tags); r != "" {
+		t.Error("ejected before max failures: ", r)
+	}
+	// a success resets consecutive failures
+	tracker.ReportSuccess("a")
+	tracker.ReportFailure("a", errDial)
+	if r := cmp.Diff(tracker.Filter(tags), tags); r != "" {
+		t.Error("failures are not reset: ", r)
+	}
+	tracker.ReportFailure("a", errDial)
+	if r := cmp.Diff(tracker.Filter(tags), []string{"b"}); r != "" {
+		t.Error("not ejected: ", r)
+	}
+
+	// half-open after cooldown, only a single trial is let through
+	now = now.Add(time.Minute)
+	if r := cmp.Diff(tracker.Filter(tags), tags); r != "" {
+		t.Error("no trial after cooldown: ", r)
+	}
+	if !tracker.Picked("a") {
+		t.Error("trial refused")
+	}
+	// a concurrent pick passed the filter before the trial started
+	if tracker.Picked("a") {
+		t.Error("more than one trial picked")
+	}
+	if r := cmp.Diff(tracker.Filter(tags), []string{"b"}); r != "" {
+		t.Error("more than one trial: ", r)
+	}
+
+	// failed trial ejects again
+	tracker.ReportFailure("a", errDial)
+	now = now.Add(time.Second)
+	if r := cmp.Diff(tracker.Filter(tags), []string{"b"}); r != "" {
+		t.Error("not ejected after failed trial: ", r)
+	}
+
+	// successful trial restores
+	now = now.Add(time.Minute)
+	tracker.Picked("a")
+	tracker.ReportSuccess("a")
+	if r := cmp.Diff(tracker.Filter(tags), tags); r != "" {
+		t.Error("not restored after successful trial: ", r)
+	}
+}
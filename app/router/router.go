@@ -3,6 +3,7 @@ package router
 import (
 	"context"
 	sync "sync"
+	"time"
 
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/errors"
@@ -20,6 +21,7 @@ type Router struct {
 	rules          []*Rule
 	balancers      map[string]*Balancer
 	dns            dns.Client
+	health         *HealthTracker
 
 	ctx        context.Context
 	ohm        outbound.Manager
@@ -33,6 +35,7 @@ type Route struct {
 	outboundGroupTags []string
 	outboundTag       string
 	ruleTag           string
+	fallbackChain     *FallbackChain
 }
 
 // Init initializes the Router.
@@ -42,6 +45,7 @@ func (r *Router) Init(ctx context.Context, config *Config, d dns.Client, ohm out
 	r.ctx = ctx
 	r.ohm = ohm
 	r.dispatcher = dispatcher
+	r.health = NewHealthTracker(config.PassiveHealth)
 
 	r.balancers = make(map[string]*Balancer, len(config.BalancingRule))
 	for _, rule := range config.BalancingRule {
@@ -49,6 +53,8 @@ func (r *Router) Init(ctx context.Context, config *Config, d dns.Client, ohm out
 		if err != nil {
 			return err
 		}
+		balancer.fallbackChain = rule.FallbackChain
+		balancer.health = r.health
 		balancer.InjectContext(ctx)
 		r.balancers[rule.Tag] = balancer
 	}
@@ -60,9 +66,10 @@ func (r *Router) Init(ctx context.Context, config *Config, d dns.Client, ohm out
 			return err
 		}
 		rr := &Rule{
-			Condition: cond,
-			Tag:       rule.GetTag(),
-			RuleTag:   rule.GetRuleTag(),
+			Condition:     cond,
+			Tag:           rule.GetTag(),
+			RuleTag:       rule.GetRuleTag(),
+			FallbackChain: rule.FallbackChain,
 		}
 		btag := rule.GetBalancingTag()
 		if len(btag) > 0 {
@@ -84,11 +91,11 @@ func (r *Router) PickRoute(ctx routing.Context) (routing.Route, error) {
 	if err != nil {
 		return nil, err
 	}
-	tag, err := rule.GetTag()
+	tag, err := rule.GetTag(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return &Route{Context: ctx, outboundTag: tag, ruleTag: rule.RuleTag}, nil
+	return &Route{Context: ctx, outboundTag: tag, ruleTag: rule.RuleTag, fallbackChain: rule.GetFallbackChain()}, nil
 }
 
 // AddRule implements routing.Router.
@@ -121,6 +128,8 @@ func (r *Router) ReloadRules(config *Config, shouldAppend bool) error {
 		if err != nil {
 			return err
 		}
+		balancer.fallbackChain = rule.FallbackChain
+		balancer.health = r.health
 		balancer.InjectContext(r.ctx)
 		r.balancers[rule.Tag] = balancer
 	}
@@ -134,9 +143,10 @@ func (r *Router) ReloadRules(config *Config, shouldAppend bool) error {
 			return err
 		}
 		rr := &Rule{
-			Condition: cond,
-			Tag:       rule.GetTag(),
-			RuleTag:   rule.GetRuleTag(),
+			Condition:     cond,
+			Tag:           rule.GetTag(),
+			RuleTag:       rule.GetRuleTag(),
+			FallbackChain: rule.FallbackChain,
 		}
 		btag := rule.GetBalancingTag()
 		if len(btag) > 0 {
@@ -242,6 +252,16 @@ func (r *Route) GetRuleTag() string {
 	return r.ruleTag
 }
 
+// GetFallbackTags implements routing.FallbackRoute.
+func (r *Route) GetFallbackTags() []string {
+	return r.fallbackChain.GetOutboundTag()
+}
+
+// GetAttemptTimeout implements routing.FallbackRoute.
+func (r *Route) GetAttemptTimeout() time.Duration {
+	return time.Duration(r.fallbackChain.GetAttemptTimeout())
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		r := new(Router)
@@ -11,6 +11,7 @@ import (
 	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/features/dns"
 	"github.com/xtls/xray-core/features/outbound"
+	"github.com/xtls/xray-core/features/routing"
 	routing_session "github.com/xtls/xray-core/features/routing/session"
 	"github.com/xtls/xray-core/testing/mocks"
 )
@@ -276,3 +277,127 @@ func TestIPIfNonMatchIP(t *testing.T) {
 		t.Error("expect tag 'test', bug actually ", tag)
 	}
 }
+
+func TestExplainRoute(t *testing.T) {
+	config := &Config{
+		DomainStrategy: Config_IpIfNonMatch,
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "direct",
+				},
+				RuleTag:  "lan",
+				Networks: []net.Network{net.Network_TCP},
+				Geoip: []*GeoIP{
+					{
+						Cidr: []*CIDR{
+							{
+								Ip:     []byte{192, 168, 0, 0},
+								Prefix: 16,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockDNS := mocks.NewDNSClient(mockCtl)
+	mockDNS.EXPECT().LookupIP(gomock.Eq("example.com"), dns.IPOption{
+		IPv4Enable: true,
+		IPv6Enable: true,
+		FakeEnable: false,
+	}).Return([]net.IP{{192, 168, 0, 1}}, nil).Times(1)
+
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), config, mockDNS, nil, nil))
+
+	ctx := session.ContextWithOutbounds(context.Background(), []*session.Outbound{{
+		Target: net.TCPDestination(net.DomainAddress("example.com"), 80),
+	}})
+	explanation, err := r.ExplainRoute(routing_session.AsRoutingContext(ctx))
+	common.Must(err)
+
+	if explanation.Route == nil || explanation.Route.GetOutboundTag() != "direct" {
+		t.Fatal("expect route to 'direct', but actually ", explanation.Route)
+	}
+	if len(explanation.Rules) != 2 {
+		t.Fatal("expect the rule to be evaluated twice, but actually ", len(explanation.Rules))
+	}
+	first, second := explanation.Rules[0], explanation.Rules[1]
+	if first.Matched || first.AfterResolve || !second.Matched || !second.AfterResolve {
+		t.Error("unexpected rule results: ", first, second)
+	}
+	if len(first.Conditions) != 2 || first.Conditions[0].Name != "network" || !first.Conditions[0].Matched ||
+		first.Conditions[1].Name != "ip" || first.Conditions[1].Matched {
+		t.Error("unexpected condition results: ", first.Conditions)
+	}
+	if len(explanation.Resolutions) != 1 || explanation.Resolutions[0].Domain != "example.com" {
+		t.Error("unexpected resolutions: ", explanation.Resolutions)
+	}
+}
+
+func TestExplainRouteBalancer(t *testing.T) {
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_BalancingTag{
+					BalancingTag: "balance",
+				},
+				Networks: []net.Network{net.Network_TCP},
+				FallbackChain: &FallbackChain{
+					OutboundTag: []string{"backup"},
+				},
+			},
+		},
+		BalancingRule: []*BalancingRule{
+			{
+				Tag:              "balance",
+				OutboundSelector: []string{"test-"},
+				Strategy:         "roundrobin",
+			},
+		},
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockDNS := mocks.NewDNSClient(mockCtl)
+	mockOhm := mocks.NewOutboundManager(mockCtl)
+	mockHs := mocks.NewOutboundHandlerSelector(mockCtl)
+
+	mockHs.EXPECT().Select(gomock.Eq([]string{"test-"})).Return([]string{"test-1", "test-2"}).AnyTimes()
+
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), config, mockDNS, &mockOutboundManager{
+		Manager:         mockOhm,
+		HandlerSelector: mockHs,
+	}, nil))
+
+	ctx := routing_session.AsRoutingContext(session.ContextWithOutbounds(context.Background(), []*session.Outbound{{
+		Target: net.TCPDestination(net.DomainAddress("example.com"), 80),
+	}}))
+	for i := 0; i < 2; i++ {
+		explanation, err := r.ExplainRoute(ctx)
+		common.Must(err)
+		if tag := explanation.Route.GetOutboundTag(); tag != "test-1" {
+			t.Error("expect tag 'test-1', but actually ", tag)
+		}
+		fallbacks := explanation.Route.(routing.FallbackRoute).GetFallbackTags()
+		if len(fallbacks) != 1 || fallbacks[0] != "backup" {
+			t.Error("unexpected fallback tags: ", fallbacks)
+		}
+	}
+
+	// explaining does not advance the balancer
+	for _, expected := range []string{"test-1", "test-2"} {
+		route, err := r.PickRoute(ctx)
+		common.Must(err)
+		if tag := route.GetOutboundTag(); tag != expected {
+			t.Error("expect tag '", expected, "', but actually ", tag)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package router
+
+import (
+	"context"
+	"hash/fnv"
+
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/extension"
+	"github.com/xtls/xray-core/features/routing"
+)
+
+// ConsistentHashStrategy sends connections with the same hash key to the same
+// outbound. It uses rendezvous hashing, so when an outbound goes down or comes
+// back according to the observatory, only the keys mapped to it are moved.
+type ConsistentHashStrategy struct {
+	settings *StrategyConsistentHashConfig
+
+	ctx         context.Context
+	observatory extension.Observatory
+}
+
+// NewConsistentHashStrategy creates a new ConsistentHashStrategy with settings
+func NewConsistentHashStrategy(settings *StrategyConsistentHashConfig) *ConsistentHashStrategy {
+	if settings == nil {
+		settings = new(StrategyConsistentHashConfig)
+	}
+	return &ConsistentHashStrategy{
+		settings: settings,
+	}
+}
+
+func (s *ConsistentHashStrategy) InjectContext(ctx context.Context) {
+	s.ctx = ctx
+	core.OptionalFeatures(s.ctx, func(observatory extension.Observatory) error {
+		s.observatory = observatory
+		return nil
+	})
+}
+
+func (s *ConsistentHashStrategy) GetPrincipleTarget(strings []string) []string {
+	return filterAliveOutbounds(s.ctx, s.observatory, strings)
+}
+
+// PickOutbound implements BalancingStrategy. Without a routing context, all
+// connections share the same empty key.
+func (s *ConsistentHashStrategy) PickOutbound(candidates []string) string {
+	return s.pick("", candidates)
+}
+
+// PickOutboundWithContext implements ContextualBalancingStrategy.
+func (s *ConsistentHashStrategy) PickOutboundWithContext(ctx routing.Context, candidates []string) string {
+	return s.pick(s.hashKey(ctx), candidates)
+}
+
+func (s *ConsistentHashStrategy) hashKey(ctx routing.Context) string {
+	switch s.settings.HashKey {
+	case StrategyConsistentHashConfig_User:
+		if user := ctx.GetUser(); len(user) > 0 {
+			return user
+		}
+	case StrategyConsistentHashConfig_Domain:
+		if domain := ctx.GetTargetDomain(); len(domain) > 0 {
+			return domain
+		}
+		return ipsKey(ctx.GetTargetIPs())
+	}
+	return ipsKey(ctx.GetSourceIPs())
+}
+
+func ipsKey(ips []net.IP) string {
+	if len(ips) == 0 {
+		return ""
+	}
+	return ips[0].String()
+}
+
+func (s *ConsistentHashStrategy) pick(key string, candidates []string) string {
+	candidates = filterAliveOutbounds(s.ctx, s.observatory, candidates)
+	var selected string
+	var highest uint64
+	for _, candidate := range candidates {
+		if score := rendezvousScore(key, candidate); len(selected) == 0 || score > highest {
+			selected = candidate
+			highest = score
+		}
+	}
+	// goes to fallbackTag if empty
+	return selected
+}
+
+// rendezvousScore returns the weight of a tag for a key in rendezvous hashing.
+func rendezvousScore(key, tag string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(tag))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	// splitmix64 finalizer, as FNV alone is poorly distributed for short inputs
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
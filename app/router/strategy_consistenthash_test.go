@@ -0,0 +1,57 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/session"
+	routing_session "github.com/xtls/xray-core/features/routing/session"
+)
+
+func TestConsistentHashSticky(t *testing.T) {
+	strategy := NewConsistentHashStrategy(&StrategyConsistentHashConfig{
+		HashKey: StrategyConsistentHashConfig_User,
+	})
+	candidates := []string{"a", "b", "c"}
+	ctx := &routing_session.Context{
+		Inbound: &session.Inbound{User: &protocol.MemoryUser{Email: "love@example.com"}},
+	}
+	expected := strategy.PickOutboundWithContext(ctx, candidates)
+	for i := 0; i < 10; i++ {
+		if actual := strategy.PickOutboundWithContext(ctx, candidates); actual != expected {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+	}
+	// order of candidates should not matter
+	if actual := strategy.PickOutboundWithContext(ctx, []string{"c", "b", "a"}); actual != expected {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestConsistentHashMinimalReshuffle(t *testing.T) {
+	strategy := NewConsistentHashStrategy(nil)
+	all := []string{"a", "b", "c", "d"}
+	withoutC := []string{"a", "b", "d"}
+
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		ctx := &routing_session.Context{
+			Inbound: &session.Inbound{Source: net.TCPDestination(net.IPAddress([]byte{10, 0, byte(i >> 8), byte(i)}), 1080)},
+		}
+		before := strategy.PickOutboundWithContext(ctx, all)
+		after := strategy.PickOutboundWithContext(ctx, withoutC)
+		counts[before]++
+		if before != "c" && before != after {
+			t.Fatalf("key moved from %v to %v while its outbound stayed up", before, after)
+		}
+		if after == "c" {
+			t.Fatal("picked an outbound which is down")
+		}
+	}
+	for _, tag := range all {
+		if counts[tag] < 150 {
+			t.Errorf("outbound %v is underused: %v", tag, counts)
+		}
+	}
+}
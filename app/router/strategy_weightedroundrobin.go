@@ -0,0 +1,125 @@
+package router
+
+import (
+	"context"
+	"sync"
+
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/extension"
+)
+
+// WeightedRoundRobinStrategy distributes connections among outbounds in
+// proportion to their weights, skipping outbounds the observatory reports
+// dead. It uses the smooth weighted round-robin algorithm, which interleaves
+// picks instead of sending bursts to the heaviest outbound.
+type WeightedRoundRobinStrategy struct {
+	weights *WeightManager
+
+	ctx         context.Context
+	observatory extension.Observatory
+
+	mu        sync.Mutex
+	overrides map[string]float64
+	current   map[string]float64
+}
+
+// NewWeightedRoundRobinStrategy creates a new WeightedRoundRobinStrategy with settings
+func NewWeightedRoundRobinStrategy(settings *StrategyWeightedRoundRobinConfig) *WeightedRoundRobinStrategy {
+	var weights []*StrategyWeight
+	if settings != nil {
+		weights = settings.Weights
+	}
+	return &WeightedRoundRobinStrategy{
+		weights: NewWeightManager(
+			weights, 1,
+			func(value, weight float64) float64 {
+				return value * weight
+			},
+		),
+		overrides: make(map[string]float64),
+		current:   make(map[string]float64),
+	}
+}
+
+func (s *WeightedRoundRobinStrategy) InjectContext(ctx context.Context) {
+	s.ctx = ctx
+	core.OptionalFeatures(s.ctx, func(observatory extension.Observatory) error {
+		s.observatory = observatory
+		return nil
+	})
+}
+
+// SetWeight implements BalancingWeightSetter.
+func (s *WeightedRoundRobinStrategy) SetWeight(tag string, weight float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if weight < 0 {
+		delete(s.overrides, tag)
+	} else {
+		s.overrides[tag] = weight
+	}
+	// restart the sequence so the new weights apply immediately
+	clear(s.current)
+}
+
+func (s *WeightedRoundRobinStrategy) weight(tag string) float64 {
+	if weight, found := s.overrides[tag]; found {
+		return weight
+	}
+	return s.weights.Get(tag)
+}
+
+func (s *WeightedRoundRobinStrategy) GetPrincipleTarget(strings []string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var tags []string
+	for _, tag := range filterAliveOutbounds(s.ctx, s.observatory, strings) {
+		if s.weight(tag) > 0 {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func (s *WeightedRoundRobinStrategy) PickOutbound(candidates []string) string {
+	return s.pick(candidates, true)
+}
+
+// PeekOutbound implements PeekingBalancingStrategy.
+func (s *WeightedRoundRobinStrategy) PeekOutbound(candidates []string) string {
+	return s.pick(candidates, false)
+}
+
+// pick selects the candidate with the largest current weight, and moves the
+// sequence on if advance.
+func (s *WeightedRoundRobinStrategy) pick(candidates []string, advance bool) string {
+	candidates = filterAliveOutbounds(s.ctx, s.observatory, candidates)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var selected string
+	var total, selectedCurrent float64
+	for _, tag := range candidates {
+		weight := s.weight(tag)
+		if weight <= 0 {
+			continue
+		}
+		total += weight
+		current := s.current[tag] + weight
+		if advance {
+			s.current[tag] = current
+		}
+		if len(selected) == 0 || current > selectedCurrent {
+			selected = tag
+			selectedCurrent = current
+		}
+	}
+	if len(selected) == 0 {
+		// goes to fallbackTag
+		return ""
+	}
+	if advance {
+		s.current[selected] -= total
+	}
+	return selected
+}
@@ -0,0 +1,75 @@
+package router
+
+import (
+	"testing"
+)
+
+func TestWeightedRoundRobin(t *testing.T) {
+	strategy := NewWeightedRoundRobinStrategy(&StrategyWeightedRoundRobinConfig{
+		Weights: []*StrategyWeight{
+			{Match: "a", Value: 7},
+			{Match: "b", Value: 2},
+		},
+	})
+	candidates := []string{"a", "b", "c"}
+
+	counts := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		counts[strategy.PickOutbound(candidates)]++
+	}
+	if counts["a"] != 70 || counts["b"] != 20 || counts["c"] != 10 {
+		t.Errorf("unexpected distribution: %v", counts)
+	}
+
+	// smooth: the heaviest outbound is never picked more than its share in a row
+	strategy.SetWeight("a", 1)
+	strategy.SetWeight("b", 1)
+	last, run := "", 0
+	for i := 0; i < 30; i++ {
+		tag := strategy.PickOutbound(candidates)
+		if tag == last {
+			run++
+		} else {
+			last, run = tag, 1
+		}
+		if run > 1 {
+			t.Fatalf("%v picked twice in a row with equal weights", tag)
+		}
+	}
+
+	strategy.SetWeight("c", 0)
+	for i := 0; i < 10; i++ {
+		if tag := strategy.PickOutbound(candidates); tag == "c" {
+			t.Fatal("picked an outbound with weight 0")
+		}
+	}
+
+	strategy.SetWeight("a", -1)
+	strategy.SetWeight("b", 0)
+	if tag := strategy.PickOutbound(candidates); tag != "a" {
+		t.Errorf("expected: a, actual: %v", tag)
+	}
+	if tag := strategy.PickOutbound([]string{"b", "c"}); tag != "" {
+		t.Errorf("expected empty tag for fallback, actual: %v", tag)
+	}
+}
+
+func TestWeightedRoundRobinPeek(t *testing.T) {
+	strategy := NewWeightedRoundRobinStrategy(&StrategyWeightedRoundRobinConfig{
+		Weights: []*StrategyWeight{
+			{Match: "a", Value: 2},
+			{Match: "b", Value: 1},
+		},
+	})
+	candidates := []string{"a", "b"}
+
+	for _, expected := range []string{"a", "b", "a", "a"} {
+		peeked := strategy.PeekOutbound(candidates)
+		if tag := strategy.PeekOutbound(candidates); tag != peeked {
+			t.Fatalf("peeked %v, then %v", peeked, tag)
+		}
+		if tag := strategy.PickOutbound(candidates); tag != expected || tag != peeked {
+			t.Errorf("expected: %v, peeked: %v, actual: %v", expected, peeked, tag)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package tls
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// JA3 returns the JA3 fingerprint of the client hello, as the hex encoded MD5
+// of "version,ciphers,extensions,groups,pointFormats".
+// https://github.com/salesforce/ja3
+func (h *SniffHeader) JA3() string {
+	var sb strings.Builder
+	sb.WriteString(strconv.Itoa(int(h.legacyVersion)))
+	sb.WriteByte(',')
+	writeDecimalList(&sb, h.cipherSuites)
+	sb.WriteByte(',')
+	writeDecimalList(&sb, h.extensions)
+	sb.WriteByte(',')
+	writeDecimalList(&sb, h.supportedGroups)
+	sb.WriteByte(',')
+	for i, f := range h.pointFormats {
+		if i > 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteString(strconv.Itoa(int(f)))
+	}
+	sum := md5.Sum([]byte(sb.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// JA4 returns the JA4 fingerprint of the client hello sent over TCP.
+// https://github.com/FoxIO-LLC/ja4/blob/main/technical_details/JA4.md
+func (h *SniffHeader) JA4() string {
+	return h.ja4('t')
+}
+
+func (h *SniffHeader) ja4(transport byte) string {
+	var version string
+	switch h.Version() {
+	case 0x0304:
+		version = "13"
+	case 0x0303:
+		version = "12"
+	case 0x0302:
+		version = "11"
+	case 0x0301:
+		version = "10"
+	case 0x0300:
+		version = "s3"
+	default:
+		version = "00"
+	}
+	sni := byte('i')
+	if h.hasServerName {
+		sni = 'd'
+	}
+	alpn := "00"
+	if len(h.alpn) > 0 && len(h.alpn[0]) > 0 {
+		first := h.alpn[0]
+		alpn = string([]byte{first[0], first[len(first)-1]})
+	}
+
+	ciphers := slices.Clone(h.cipherSuites)
+	slices.Sort(ciphers)
+	extensions := make([]uint16, 0, len(h.extensions))
+	for _, e := range h.extensions {
+		// server_name and application_layer_protocol_negotiation are excluded
+		// from the hash since they are already reflected in the prefix.
+		if e != 0x00 && e != 0x10 {
+			extensions = append(extensions, e)
+		}
+	}
+	slices.Sort(extensions)
+
+	extensionsPart := hexList(extensions)
+	if len(h.signatureAlgorithms) > 0 {
+		extensionsPart += "_" + hexList(h.signatureAlgorithms)
+	}
+
+	return fmt.Sprintf("%c%s%c%02d%02d%s_%s_%s", transport, version, sni,
+		min(len(h.cipherSuites), 99), min(len(h.extensions), 99), alpn,
+		truncatedHash(hexList(ciphers), len(ciphers)), truncatedHash(extensionsPart, len(extensions)))
+}
+
+// Attributes returns the routing attributes derived from the client hello.
+// ":sni" is absent if the client hello has no server name.
+func (h *SniffHeader) Attributes() map[string]string {
+	attrs := map[string]string{
+		":alpn":        strings.Join(h.alpn, ","),
+		":tls-version": versionName(h.Version()),
+		":ja3":         h.JA3(),
+		":ja4":         h.JA4(),
+	}
+	if h.hasServerName {
+		attrs[":sni"] = h.domain
+	}
+	return attrs
+}
+
+func versionName(v uint16) string {
+	switch v {
+	case 0x0304:
+		return "1.3"
+	case 0x0303:
+		return "1.2"
+	case 0x0302:
+		return "1.1"
+	case 0x0301:
+		return "1.0"
+	default:
+		return fmt.Sprintf("0x%04x", v)
+	}
+}
+
+func writeDecimalList(sb *strings.Builder, list []uint16) {
+	for i, v := range list {
+		if i > 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteString(strconv.Itoa(int(v)))
+	}
+}
+
+func hexList(list []uint16) string {
+	parts := make([]string, len(list))
+	for i, v := range list {
+		parts[i] = fmt.Sprintf("%04x", v)
+	}
+	return strings.Join(parts, ",")
+}
+
+func truncatedHash(s string, count int) string {
+	if count == 0 {
+		return "000000000000"
+	}
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:12]
+}
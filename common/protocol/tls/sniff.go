@@ -10,6 +10,18 @@ import (
 
 type SniffHeader struct {
 	domain string
+
+	// Fields below are collected from the ClientHello for routing attributes
+	// and fingerprinting. GREASE values are already removed.
+	legacyVersion       uint16
+	supportedVersions   []uint16
+	cipherSuites        []uint16
+	extensions          []uint16
+	supportedGroups     []uint16
+	pointFormats        []uint8
+	signatureAlgorithms []uint16
+	alpn                []string
+	hasServerName       bool
 }
 
 func (h *SniffHeader) Protocol() string {
@@ -20,6 +32,27 @@ func (h *SniffHeader) Domain() string {
 	return h.domain
 }
 
+// ALPN returns the protocols offered in the application_layer_protocol_negotiation extension.
+func (h *SniffHeader) ALPN() []string {
+	return h.alpn
+}
+
+// HasServerName returns true if the ClientHello carries a server_name extension.
+func (h *SniffHeader) HasServerName() bool {
+	return h.hasServerName
+}
+
+// Version returns the highest TLS version offered by the client.
+func (h *SniffHeader) Version() uint16 {
+	version := h.legacyVersion
+	for _, v := range h.supportedVersions {
+		if v > version {
+			version = v
+		}
+	}
+	return version
+}
+
 var (
 	errNotTLS         = errors.New("not TLS header")
 	errNotClientHello = errors.New("not client hello")
@@ -29,12 +62,41 @@ func IsValidTLSVersion(major, minor byte) bool {
 	return major == 3
 }
 
+// isGREASE returns true for the reserved values of RFC 8701.
+func isGREASE(v uint16) bool {
+	return v&0x0f0f == 0x0a0a && v>>8 == v&0xff
+}
+
+func readUint16List(d []byte) []uint16 {
+	list := make([]uint16, 0, len(d)/2)
+	for ; len(d) >= 2; d = d[2:] {
+		v := binary.BigEndian.Uint16(d)
+		if !isGREASE(v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
 // ReadClientHello returns server name (if any) from TLS client hello message.
 // https://github.com/golang/go/blob/master/src/crypto/tls/handshake_messages.go#L300
 func ReadClientHello(data []byte, h *SniffHeader) error {
+	if err := parseClientHello(data, h); err != nil {
+		return err
+	}
+	if !h.hasServerName {
+		return errNotTLS
+	}
+	return nil
+}
+
+// parseClientHello parses the whole client hello message into h. Unlike
+// ReadClientHello, a client hello without server name is not an error.
+func parseClientHello(data []byte, h *SniffHeader) error {
 	if len(data) < 42 {
 		return common.ErrNoClue
 	}
+	h.legacyVersion = uint16(data[4])<<8 | uint16(data[5])
 	sessionIDLen := int(data[38])
 	if sessionIDLen > 32 || len(data) < 39+sessionIDLen {
 		return common.ErrNoClue
@@ -49,6 +111,7 @@ func ReadClientHello(data []byte, h *SniffHeader) error {
 	if cipherSuiteLen%2 == 1 || len(data) < 2+cipherSuiteLen {
 		return errNotClientHello
 	}
+	h.cipherSuites = readUint16List(data[2 : 2+cipherSuiteLen])
 	data = data[2+cipherSuiteLen:]
 	if len(data) < 1 {
 		return common.ErrNoClue
@@ -72,19 +135,36 @@ func ReadClientHello(data []byte, h *SniffHeader) error {
 		return errNotClientHello
 	}
 
+	// Once the server name is known, a malformed extension after it is
+	// tolerated, since only the server name was parsed before.
+	malformed := func() error {
+		if h.hasServerName {
+			return nil
+		}
+		return errNotClientHello
+	}
+
 	for len(data) != 0 {
 		if len(data) < 4 {
-			return errNotClientHello
+			return malformed()
 		}
 		extension := uint16(data[0])<<8 | uint16(data[1])
 		length := int(data[2])<<8 | int(data[3])
 		data = data[4:]
 		if len(data) < length {
-			return errNotClientHello
+			return malformed()
+		}
+		d := data[:length]
+		data = data[length:]
+		if !isGREASE(extension) {
+			h.extensions = append(h.extensions, extension)
 		}
 
-		if extension == 0x00 { /* extensionServerName */
-			d := data[:length]
+		switch extension {
+		case 0x00: /* extensionServerName */
+			if h.hasServerName {
+				continue
+			}
 			if len(d) < 2 {
 				return errNotClientHello
 			}
@@ -112,18 +192,60 @@ func ReadClientHello(data []byte, h *SniffHeader) error {
 						return errNotClientHello
 					}
 					h.domain = serverName
-					return nil
+					h.hasServerName = true
+					break
 				}
 				d = d[nameLen:]
 			}
+		case 0x0a: /* extensionSupportedCurves */
+			if len(d) >= 2 {
+				h.supportedGroups = readUint16List(d[2:])
+			}
+		case 0x0b: /* extensionSupportedPoints */
+			if len(d) >= 1 && len(d) >= 1+int(d[0]) {
+				h.pointFormats = append([]uint8(nil), d[1:1+int(d[0])]...)
+			}
+		case 0x0d: /* extensionSignatureAlgorithms */
+			if len(d) >= 2 {
+				h.signatureAlgorithms = readUint16List(d[2:])
+			}
+		case 0x10: /* extensionALPN */
+			if len(d) < 2 {
+				continue
+			}
+			for d = d[2:]; len(d) > 0; {
+				protoLen := int(d[0])
+				if len(d) < 1+protoLen {
+					break
+				}
+				h.alpn = append(h.alpn, string(d[1:1+protoLen]))
+				d = d[1+protoLen:]
+			}
+		case 0x2b: /* extensionSupportedVersions */
+			if len(d) >= 1 {
+				h.supportedVersions = readUint16List(d[1:])
+			}
 		}
-		data = data[length:]
 	}
 
-	return errNotTLS
+	return nil
 }
 
+// SniffTLS returns the header of a TLS client hello with server name.
 func SniffTLS(b []byte) (*SniffHeader, error) {
+	h, err := SniffClientHello(b)
+	if err != nil {
+		return nil, err
+	}
+	if !h.hasServerName {
+		return nil, errNotTLS
+	}
+	return h, nil
+}
+
+// SniffClientHello returns the header of a TLS client hello, which may have no
+// server name unlike SniffTLS.
+func SniffClientHello(b []byte) (*SniffHeader, error) {
 	if len(b) < 5 {
 		return nil, common.ErrNoClue
 	}
@@ -140,7 +262,7 @@ func SniffTLS(b []byte) (*SniffHeader, error) {
 	}
 
 	h := &SniffHeader{}
-	err := ReadClientHello(b[5:5+headerLen], h)
+	err := parseClientHello(b[5:5+headerLen], h)
 	if err == nil {
 		return h, nil
 	}
@@ -1,6 +1,10 @@
 package tls_test
 
 import (
+	"crypto/tls"
+	"io"
+	"net"
+	"strings"
 	"testing"
 
 	. "github.com/xtls/xray-core/common/protocol/tls"
@@ -159,3 +163,100 @@ func TestTLSHeaders(t *testing.T) {
 		}
 	}
 }
+
+func TestTLSClientHelloFingerprint(t *testing.T) {
+	input := []byte{
+		0x16, 0x03, 0x01, 0x00, 0xc8, 0x01, 0x00, 0x00,
+		0xc4, 0x03, 0x03, 0x1a, 0xac, 0xb2, 0xa8, 0xfe,
+		0xb4, 0x96, 0x04, 0x5b, 0xca, 0xf7, 0xc1, 0xf4,
+		0x2e, 0x53, 0x24, 0x6e, 0x34, 0x0c, 0x58, 0x36,
+		0x71, 0x97, 0x59, 0xe9, 0x41, 0x66, 0xe2, 0x43,
+		0xa0, 0x13, 0xb6, 0x00, 0x00, 0x20, 0x1a, 0x1a,
+		0xc0, 0x2b, 0xc0, 0x2f, 0xc0, 0x2c, 0xc0, 0x30,
+		0xcc, 0xa9, 0xcc, 0xa8, 0xcc, 0x14, 0xcc, 0x13,
+		0xc0, 0x13, 0xc0, 0x14, 0x00, 0x9c, 0x00, 0x9d,
+		0x00, 0x2f, 0x00, 0x35, 0x00, 0x0a, 0x01, 0x00,
+		0x00, 0x7b, 0xba, 0xba, 0x00, 0x00, 0xff, 0x01,
+		0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x16, 0x00,
+		0x14, 0x00, 0x00, 0x11, 0x63, 0x2e, 0x73, 0x2d,
+		0x6d, 0x69, 0x63, 0x72, 0x6f, 0x73, 0x6f, 0x66,
+		0x74, 0x2e, 0x63, 0x6f, 0x6d, 0x00, 0x17, 0x00,
+		0x00, 0x00, 0x23, 0x00, 0x00, 0x00, 0x0d, 0x00,
+		0x14, 0x00, 0x12, 0x04, 0x03, 0x08, 0x04, 0x04,
+		0x01, 0x05, 0x03, 0x08, 0x05, 0x05, 0x01, 0x08,
+		0x06, 0x06, 0x01, 0x02, 0x01, 0x00, 0x05, 0x00,
+		0x05, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x12,
+		0x00, 0x00, 0x00, 0x10, 0x00, 0x0e, 0x00, 0x0c,
+		0x02, 0x68, 0x32, 0x08, 0x68, 0x74, 0x74, 0x70,
+		0x2f, 0x31, 0x2e, 0x31, 0x00, 0x0b, 0x00, 0x02,
+		0x01, 0x00, 0x00, 0x0a, 0x00, 0x0a, 0x00, 0x08,
+		0xaa, 0xaa, 0x00, 0x1d, 0x00, 0x17, 0x00, 0x18,
+		0xaa, 0xaa, 0x00, 0x01, 0x00,
+	}
+
+	header, err := SniffTLS(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs := header.Attributes()
+	expected := map[string]string{
+		":sni":         "c.s-microsoft.com",
+		":alpn":        "h2,http/1.1",
+		":tls-version": "1.2",
+		":ja3":         "b8f81673c0e1d29908346f3bab892b9b",
+		":ja4":         "t12d1510h2_f0daf39aad75_e69ac49eb88f",
+	}
+	for key, value := range expected {
+		if attrs[key] != value {
+			t.Error("expect ", key, " to be ", value, " but got ", attrs[key])
+		}
+	}
+}
+
+func TestTLSClientHelloWithoutServerName(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	defer serverConn.Close()
+	go func() {
+		client := tls.Client(clientConn, &tls.Config{
+			InsecureSkipVerify: true,
+			NextProtos:         []string{"h2", "http/1.1"},
+		})
+		client.Handshake()
+		client.Close()
+	}()
+
+	b := make([]byte, 4096)
+	n, err := io.ReadAtLeast(serverConn, b, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordLen := 5 + (int(b[3])<<8 | int(b[4]))
+	if n < recordLen {
+		if _, err := io.ReadFull(serverConn, b[n:recordLen]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := SniffTLS(b[:recordLen]); err == nil {
+		t.Error("expect SniffTLS to require a server name")
+	}
+	header, err := SniffClientHello(b[:recordLen])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found := header.Attributes()[":sni"]; found {
+		t.Error("expect no sni attribute")
+	}
+	if header.Domain() != "" || header.HasServerName() {
+		t.Error("expect no server name but got ", header.Domain())
+	}
+	if header.Version() != tls.VersionTLS13 {
+		t.Error("expect TLS 1.3 but got ", header.Version())
+	}
+	if !strings.HasPrefix(header.JA4(), "t13i") || !strings.Contains(header.JA4(), "h2_") {
+		t.Error("unexpected JA4 ", header.JA4())
+	}
+	if err := ReadClientHello(b[5:recordLen], &SniffHeader{}); err == nil {
+		t.Error("expect ReadClientHello to require a server name")
+	}
+}
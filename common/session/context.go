@@ -23,6 +23,7 @@ const (
 	timeoutOnlyKey            ctx.SessionKey = 8
 	allowedNetworkKey         ctx.SessionKey = 9
 	handlerSessionKey         ctx.SessionKey = 10
+	dialFeedbackKey           ctx.SessionKey = 11
 )
 
 func ContextWithInbound(ctx context.Context, inbound *Inbound) context.Context {
@@ -130,6 +131,23 @@ func TrackedConnectionError(ctx context.Context, tracker TrackedRequestErrorFeed
 	return context.WithValue(ctx, trackedConnectionErrorKey, tracker)
 }
 
+// DialFeedback receives the results of an outbound dialing its transport connection.
+type DialFeedback interface {
+	SubmitDialResult(err error)
+}
+
+func SubmitDialResult(ctx context.Context, err error) {
+	if feedback, ok := ctx.Value(dialFeedbackKey).(DialFeedback); ok && feedback != nil {
+		feedback.SubmitDialResult(err)
+	}
+}
+
+// ContextWithDialFeedback sets the receiver of dial results. A nil feedback
+// stops results from reaching the receiver set by upper contexts.
+func ContextWithDialFeedback(ctx context.Context, feedback DialFeedback) context.Context {
+	return context.WithValue(ctx, dialFeedbackKey, feedback)
+}
+
 func ContextWithDispatcher(ctx context.Context, dispatcher routing.Dispatcher) context.Context {
 	return context.WithValue(ctx, dispatcherKey, dispatcher)
 }
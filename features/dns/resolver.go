@@ -0,0 +1,68 @@
+package dns
+
+import (
+	"time"
+
+	"github.com/xtls/xray-core/common/net"
+)
+
+// Resolution is the result of resolving a domain with a Client.
+type Resolution struct {
+	// Domain is the domain resolved, which may be replaced by static hosts.
+	Domain string
+	// Rewritten is true if Domain is the target of a rule rewriting the
+	// domain, which is answered as a CNAME.
+	Rewritten bool
+	IPs       []net.IP
+	// Server is the name of the name servers answered.
+	Server string
+}
+
+// Resolver is a Client telling where the results come from.
+//
+// xray:api:alpha
+type Resolver interface {
+	Resolve(domain string, option IPOption) (*Resolution, error)
+}
+
+// CacheEntry is a cached answer of a name server.
+type CacheEntry struct {
+	Server string
+	Domain string
+	// Type is the query type, "A" or "AAAA".
+	Type   string
+	IPs    []net.IP
+	Expire time.Time
+	RCode  uint16
+}
+
+// CacheManager is a Client exposing the caches of its name servers.
+//
+// xray:api:alpha
+type CacheManager interface {
+	// ListCache returns cached answers of the domain, or all if domain is empty.
+	ListCache(domain string) []*CacheEntry
+	// FlushCache removes cached answers of the domain, or all if domain is
+	// empty, and returns the number of domains removed.
+	FlushCache(domain string) int
+}
+
+// HostsManager is a Client of which static hosts can be changed at runtime.
+//
+// xray:api:alpha
+type HostsManager interface {
+	// AddHosts maps the domain to addrs, which are IPs or a single domain.
+	AddHosts(domain string, addrs []net.Address) error
+	// RemoveHosts removes the mapping added by AddHosts.
+	RemoveHosts(domain string) error
+}
+
+// ECHConfigResolver is a Client looking up the ECH configs of domains from
+// their DNS HTTPS records (RFC 9460).
+//
+// xray:api:alpha
+type ECHConfigResolver interface {
+	// LookupECHConfig returns the ECHConfigList of the domain, and how long
+	// it can be cached.
+	LookupECHConfig(domain string) ([]byte, time.Duration, error)
+}
@@ -8,3 +8,13 @@ type BalancerOverrider interface {
 type BalancerPrincipleTarget interface {
 	GetPrincipleTarget(tag string) ([]string, error)
 }
+
+type BalancerWeightSetter interface {
+	SetBalancerWeight(tag, target string, weight float64) error
+}
+
+// HealthReporter receives passive health reports of outbounds from connections.
+type HealthReporter interface {
+	ReportSuccess(tag string)
+	ReportFailure(tag string, err error)
+}
@@ -0,0 +1,45 @@
+package routing
+
+import (
+	"github.com/xtls/xray-core/common/net"
+)
+
+// RouteExplainer is an optional interface of Router, reporting how every rule
+// was evaluated for a routing context.
+type RouteExplainer interface {
+	// ExplainRoute evaluates the rules as PickRoute does, without short-circuiting
+	// conditions of a rule. Route of the result is nil if no rule matched.
+	ExplainRoute(ctx Context) (*RouteExplanation, error)
+}
+
+// RouteExplanation is the result of RouteExplainer.
+type RouteExplanation struct {
+	Route       Route
+	Rules       []*RuleExplanation
+	Resolutions []*DNSResolution
+}
+
+// RuleExplanation describes the evaluation of one routing rule.
+type RuleExplanation struct {
+	RuleTag     string
+	OutboundTag string
+	BalancerTag string
+	Matched     bool
+	Conditions  []*ConditionExplanation
+	// AfterResolve is true if the rule was evaluated again with resolved IPs
+	// of the target domain, as in IPIfNonMatch domain strategy.
+	AfterResolve bool
+}
+
+// ConditionExplanation describes the evaluation of one condition of a rule.
+type ConditionExplanation struct {
+	Name    string
+	Matched bool
+}
+
+// DNSResolution is a DNS lookup performed while routing.
+type DNSResolution struct {
+	Domain string
+	IPs    []net.IP
+	Err    error
+}
@@ -1,6 +1,8 @@
 package routing
 
 import (
+	"time"
+
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/serial"
 	"github.com/xtls/xray-core/features"
@@ -35,6 +37,18 @@ type Route interface {
 	GetRuleTag() string
 }
 
+// FallbackRoute is a Route with outbounds to try in turn when the outbound of
+// the route fails to connect.
+type FallbackRoute interface {
+	Route
+
+	// GetFallbackTags returns the tags of outbounds to try after the outbound of the route.
+	GetFallbackTags() []string
+
+	// GetAttemptTimeout returns the timeout of connecting in each attempt, 0 for no timeout.
+	GetAttemptTimeout() time.Duration
+}
+
 // RouterType return the type of Router interface. Can be used to implement common.HasType.
 //
 // xray:api:stable
@@ -9,6 +9,7 @@ import (
 	"github.com/xtls/xray-core/app/router"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/infra/conf/cfgcommon/duration"
 )
 
 type NameServerConfig struct {
@@ -137,6 +138,67 @@ type DNSConfig struct {
 	DisableCache           bool                `json:"disableCache"`
 	DisableFallback        bool                `json:"disableFallback"`
 	DisableFallbackIfMatch bool                `json:"disableFallbackIfMatch"`
+	Prefetch               bool                `json:"prefetch"`
+	ServeStale             bool                `json:"serveStale"`
+	StaleMaxAge            duration.Duration   `json:"staleMaxAge"`
+	PersistPath            string              `json:"persistPath"`
+	PersistInterval        duration.Duration   `json:"persistInterval"`
+	ParallelQuery          string              `json:"parallelQuery"`
+	ParallelLimit          uint32              `json:"parallelLimit"`
+	DNSSEC                 bool                `json:"dnssec"`
+	DNSSECTrustAnchors     []string            `json:"dnssecTrustAnchors"`
+	Rules                  []*DNSRuleConfig    `json:"rules"`
+	HostsFiles             []string            `json:"hostsFiles"`
+	HostsReloadInterval    duration.Duration   `json:"hostsReloadInterval"`
+}
+
+// DNSRuleConfig is a JSON serializable object for dns.ResponseRule.
+type DNSRuleConfig struct {
+	Domains StringList `json:"domains"`
+	Action  string     `json:"action"`
+	Target  string     `json:"target"`
+	IPs     StringList `json:"ips"`
+}
+
+// Build implements Buildable
+func (c *DNSRuleConfig) Build() (*dns.ResponseRule, error) {
+	rule := &dns.ResponseRule{
+		Target: c.Target,
+	}
+	switch strings.ToLower(c.Action) {
+	case "block":
+		rule.Action = dns.ResponseRule_BLOCK
+	case "zero":
+		rule.Action = dns.ResponseRule_BLOCK_ZERO
+	case "stripaaaa":
+		rule.Action = dns.ResponseRule_STRIP_AAAA
+	case "rewrite":
+		rule.Action = dns.ResponseRule_REWRITE
+	case "filterip":
+		rule.Action = dns.ResponseRule_FILTER_IP
+	default:
+		return nil, errors.New("unknown DNS rule action: ", c.Action)
+	}
+
+	for _, d := range c.Domains {
+		parsedDomain, err := parseDomainRule(d)
+		if err != nil {
+			return nil, errors.New("invalid domain rule: ", d).Base(err)
+		}
+		for _, pd := range parsedDomain {
+			rule.Domain = append(rule.Domain, &dns.NameServer_PriorityDomain{
+				Type:   toDomainMatchingType(pd.Type),
+				Domain: pd.Value,
+			})
+		}
+	}
+
+	geoipList, err := ToCidrList(c.IPs)
+	if err != nil {
+		return nil, errors.New("invalid IP rule: ", c.IPs).Base(err)
+	}
+	rule.Ip = geoipList
+	return rule, nil
 }
 
 type HostAddress struct {
@@ -317,6 +379,31 @@ func (c *DNSConfig) Build() (*dns.Config, error) {
 		DisableFallback:        c.DisableFallback,
 		DisableFallbackIfMatch: c.DisableFallbackIfMatch,
 		QueryStrategy:          resolveQueryStrategy(c.QueryStrategy),
+		Prefetch:               c.Prefetch,
+		ServeStale:             c.ServeStale,
+		StaleMaxAge:            int64(c.StaleMaxAge),
+		PersistPath:            c.PersistPath,
+		PersistInterval:        int64(c.PersistInterval),
+		ParallelLimit:          c.ParallelLimit,
+		Dnssec:                 c.DNSSEC,
+		DnssecTrustAnchor:      c.DNSSECTrustAnchors,
+		HostsFile:              c.HostsFiles,
+		HostsReloadInterval:    int64(c.HostsReloadInterval),
+	}
+
+	switch strings.ToLower(c.ParallelQuery) {
+	case "", "sequential":
+		config.ParallelQuery = dns.ParallelQuery_SEQUENTIAL
+	case "race":
+		config.ParallelQuery = dns.ParallelQuery_RACE
+	case "merge":
+		config.ParallelQuery = dns.ParallelQuery_MERGE
+	default:
+		return nil, errors.New("unknown parallelQuery: ", c.ParallelQuery)
+	}
+
+	if c.StaleMaxAge < 0 {
+		return nil, errors.New("negative staleMaxAge")
 	}
 
 	if c.ClientIP != nil {
@@ -334,6 +421,14 @@ func (c *DNSConfig) Build() (*dns.Config, error) {
 		config.NameServer = append(config.NameServer, ns)
 	}
 
+	for _, r := range c.Rules {
+		rule, err := r.Build()
+		if err != nil {
+			return nil, errors.New("failed to build DNS rule").Base(err)
+		}
+		config.ResponseRule = append(config.ResponseRule, rule)
+	}
+
 	if c.Hosts != nil {
 		staticHosts, err := c.Hosts.Build()
 		if err != nil {
@@ -3,8 +3,10 @@ package conf_test
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/xtls/xray-core/app/dns"
+	"github.com/xtls/xray-core/app/router"
 	"github.com/xtls/xray-core/common/net"
 	. "github.com/xtls/xray-core/infra/conf"
 	"google.golang.org/protobuf/proto"
@@ -103,5 +105,97 @@ func TestDNSConfigParsing(t *testing.T) {
 				DisableFallback: true,
 			},
 		},
+		{
+			Input: `{
+				"prefetch": true,
+				"serveStale": true,
+				"staleMaxAge": "12h"
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				Prefetch:    true,
+				ServeStale:  true,
+				StaleMaxAge: int64(12 * time.Hour),
+			},
+		},
+		{
+			Input: `{
+				"persistPath": "dns_cache.json",
+				"persistInterval": "5m"
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				PersistPath:     "dns_cache.json",
+				PersistInterval: int64(5 * time.Minute),
+			},
+		},
+		{
+			Input: `{
+				"parallelQuery": "race",
+				"parallelLimit": 2
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				ParallelQuery: dns.ParallelQuery_RACE,
+				ParallelLimit: 2,
+			},
+		},
+		{
+			Input: `{
+				"hostsFiles": ["/etc/hosts"],
+				"hostsReloadInterval": "30s"
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				HostsFile:           []string{"/etc/hosts"},
+				HostsReloadInterval: int64(30 * time.Second),
+			},
+		},
+		{
+			Input: `{
+				"dnssec": true,
+				"dnssecTrustAnchors": [". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBB683457104237C7F8EC8D"]
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				Dnssec:            true,
+				DnssecTrustAnchor: []string{". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBB683457104237C7F8EC8D"},
+			},
+		},
+		{
+			Input: `{
+				"rules": [
+					{"domains": ["domain:ads.example.com"], "action": "block"},
+					{"domains": ["full:example.org"], "action": "rewrite", "target": "example.net"},
+					{"ips": ["10.0.0.0/8"], "action": "filterIP"}
+				]
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				ResponseRule: []*dns.ResponseRule{
+					{
+						Domain: []*dns.NameServer_PriorityDomain{{
+							Type:   dns.DomainMatchingType_Subdomain,
+							Domain: "ads.example.com",
+						}},
+						Action: dns.ResponseRule_BLOCK,
+					},
+					{
+						Domain: []*dns.NameServer_PriorityDomain{{
+							Type:   dns.DomainMatchingType_Full,
+							Domain: "example.org",
+						}},
+						Action: dns.ResponseRule_REWRITE,
+						Target: "example.net",
+					},
+					{
+						Action: dns.ResponseRule_FILTER_IP,
+						Ip: []*router.GeoIP{{
+							Cidr: []*router.CIDR{{Ip: []byte{10, 0, 0, 0}, Prefix: 8}},
+						}},
+					},
+				},
+			},
+		},
 	})
 }
@@ -8,11 +8,23 @@ import (
 	"github.com/xtls/xray-core/app/dns/fakedns"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/infra/conf/cfgcommon/duration"
 )
 
 type FakeDNSPoolElementConfig struct {
-	IPPool  string `json:"ipPool"`
-	LRUSize int64  `json:"poolSize"`
+	IPPool          string            `json:"ipPool"`
+	LRUSize         int64             `json:"poolSize"`
+	PersistPath     string            `json:"persistPath"`
+	PersistInterval duration.Duration `json:"persistInterval"`
+}
+
+func (c *FakeDNSPoolElementConfig) Build() *fakedns.FakeDnsPool {
+	return &fakedns.FakeDnsPool{
+		IpPool:          c.IPPool,
+		LruSize:         c.LRUSize,
+		PersistPath:     c.PersistPath,
+		PersistInterval: int64(c.PersistInterval),
+	}
 }
 
 type FakeDNSConfig struct {
@@ -39,16 +51,13 @@ func (f *FakeDNSConfig) Build() (*fakedns.FakeDnsPoolMulti, error) {
 	fakeDNSPool := fakedns.FakeDnsPoolMulti{}
 
 	if f.pool != nil {
-		fakeDNSPool.Pools = append(fakeDNSPool.Pools, &fakedns.FakeDnsPool{
-			IpPool:  f.pool.IPPool,
-			LruSize: f.pool.LRUSize,
-		})
+		fakeDNSPool.Pools = append(fakeDNSPool.Pools, f.pool.Build())
 		return &fakeDNSPool, nil
 	}
 
 	if f.pools != nil {
 		for _, v := range f.pools {
-			fakeDNSPool.Pools = append(fakeDNSPool.Pools, &fakedns.FakeDnsPool{IpPool: v.IPPool, LruSize: v.LRUSize})
+			fakeDNSPool.Pools = append(fakeDNSPool.Pools, v.Build())
 		}
 		return &fakeDNSPool, nil
 	}
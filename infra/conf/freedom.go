@@ -14,13 +14,14 @@ import (
 )
 
 type FreedomConfig struct {
-	DomainStrategy string    `json:"domainStrategy"`
-	Redirect       string    `json:"redirect"`
-	UserLevel      uint32    `json:"userLevel"`
-	Fragment       *Fragment `json:"fragment"`
-	Noise          *Noise    `json:"noise"`
-	Noises         []*Noise  `json:"noises"`
-	ProxyProtocol  uint32    `json:"proxyProtocol"`
+	DomainStrategy string               `json:"domainStrategy"`
+	Redirect       string               `json:"redirect"`
+	UserLevel      uint32               `json:"userLevel"`
+	Fragment       *Fragment            `json:"fragment"`
+	Noise          *Noise               `json:"noise"`
+	Noises         []*Noise             `json:"noises"`
+	ProxyProtocol  uint32               `json:"proxyProtocol"`
+	HappyEyeballs  *HappyEyeballsConfig `json:"happyEyeballs"`
 }
 
 type Fragment struct {
@@ -147,6 +148,9 @@ func (c *FreedomConfig) Build() (proto.Message, error) {
 	if c.ProxyProtocol > 0 && c.ProxyProtocol <= 2 {
 		config.ProxyProtocol = c.ProxyProtocol
 	}
+	if c.HappyEyeballs != nil {
+		config.HappyEyeballs = c.HappyEyeballs.Build()
+	}
 	return config, nil
 }
 
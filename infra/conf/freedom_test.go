@@ -7,6 +7,7 @@ import (
 	"github.com/xtls/xray-core/common/protocol"
 	. "github.com/xtls/xray-core/infra/conf"
 	"github.com/xtls/xray-core/proxy/freedom"
+	"github.com/xtls/xray-core/transport/internet"
 )
 
 func TestFreedomConfig(t *testing.T) {
@@ -37,5 +38,22 @@ func TestFreedomConfig(t *testing.T) {
 				UserLevel: 1,
 			},
 		},
+		{
+			Input: `{
+				"domainStrategy": "UseIP",
+				"happyEyeballs": {
+					"prioritizeIPv6": true,
+					"tryDelayMs": 100
+				}
+			}`,
+			Parser: loadJSON(creator),
+			Output: &freedom.Config{
+				DomainStrategy: freedom.Config_USE_IP,
+				HappyEyeballs: &internet.HappyEyeballsConfig{
+					PrioritizeIpv6: true,
+					TryDelayMs:     100,
+				},
+			},
+		},
 	})
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/common/platform/filesystem"
 	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/infra/conf/cfgcommon/duration"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -21,10 +22,32 @@ type StrategyConfig struct {
 }
 
 type BalancingRule struct {
-	Tag         string         `json:"tag"`
-	Selectors   StringList     `json:"selector"`
-	Strategy    StrategyConfig `json:"strategy"`
-	FallbackTag string         `json:"fallbackTag"`
+	Tag           string               `json:"tag"`
+	Selectors     StringList           `json:"selector"`
+	Strategy      StrategyConfig       `json:"strategy"`
+	FallbackTag   string               `json:"fallbackTag"`
+	FallbackChain *FallbackChainConfig `json:"fallbackChain"`
+}
+
+// FallbackChainConfig is the config of outbounds to try in turn when the
+// picked outbound fails to connect.
+type FallbackChainConfig struct {
+	Outbounds      StringList        `json:"outbounds"`
+	AttemptTimeout duration.Duration `json:"attemptTimeout"`
+}
+
+// Build implements Buildable.
+func (c *FallbackChainConfig) Build() (*router.FallbackChain, error) {
+	if len(c.Outbounds) == 0 {
+		return nil, errors.New("empty outbounds in fallbackChain")
+	}
+	if c.AttemptTimeout < 0 {
+		return nil, errors.New("fallbackChain: negative attemptTimeout")
+	}
+	return &router.FallbackChain{
+		OutboundTag:    c.Outbounds,
+		AttemptTimeout: int64(c.AttemptTimeout),
+	}, nil
 }
 
 // Build builds the balancing rule
@@ -40,7 +63,7 @@ func (r *BalancingRule) Build() (*router.BalancingRule, error) {
 	switch r.Strategy.Type {
 	case "":
 		r.Strategy.Type = strategyRandom
-	case strategyRandom, strategyLeastLoad, strategyLeastPing, strategyRoundRobin:
+	case strategyRandom, strategyLeastLoad, strategyLeastPing, strategyRoundRobin, strategyConsistentHash, strategyWeightedRoundRobin:
 	default:
 		return nil, errors.New("unknown balancing strategy: " + r.Strategy.Type)
 	}
@@ -61,23 +84,49 @@ func (r *BalancingRule) Build() (*router.BalancingRule, error) {
 		}
 	}
 
+	var fallbackChain *router.FallbackChain
+	if r.FallbackChain != nil {
+		if fallbackChain, err = r.FallbackChain.Build(); err != nil {
+			return nil, err
+		}
+	}
+
 	return &router.BalancingRule{
 		Strategy:         r.Strategy.Type,
 		StrategySettings: serial.ToTypedMessage(ts),
 		FallbackTag:      r.FallbackTag,
+		FallbackChain:    fallbackChain,
 		OutboundSelector: r.Selectors,
 		Tag:              r.Tag,
 	}, nil
 }
 
 type RouterConfig struct {
-	RuleList       []json.RawMessage `json:"rules"`
-	DomainStrategy *string           `json:"domainStrategy"`
-	Balancers      []*BalancingRule  `json:"balancers"`
+	RuleList       []json.RawMessage    `json:"rules"`
+	DomainStrategy *string              `json:"domainStrategy"`
+	Balancers      []*BalancingRule     `json:"balancers"`
+	PassiveHealth  *PassiveHealthConfig `json:"passiveHealth"`
 
 	DomainMatcher string `json:"domainMatcher"`
 }
 
+// PassiveHealthConfig is the config of passive health detection of balancer outbounds.
+type PassiveHealthConfig struct {
+	MaxFailures uint32            `json:"maxFailures"`
+	Cooldown    duration.Duration `json:"cooldown"`
+}
+
+// Build implements Buildable.
+func (c *PassiveHealthConfig) Build() (*router.PassiveHealthConfig, error) {
+	if c.Cooldown < 0 {
+		return nil, errors.New("passiveHealth: negative cooldown")
+	}
+	return &router.PassiveHealthConfig{
+		MaxFailures: c.MaxFailures,
+		Cooldown:    int64(c.Cooldown),
+	}, nil
+}
+
 func (c *RouterConfig) getDomainStrategy() router.Config_DomainStrategy {
 	ds := ""
 	if c.DomainStrategy != nil {
@@ -124,6 +173,13 @@ func (c *RouterConfig) Build() (*router.Config, error) {
 		}
 		config.BalancingRule = append(config.BalancingRule, balancer)
 	}
+	if c.PassiveHealth != nil {
+		passiveHealth, err := c.PassiveHealth.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.PassiveHealth = passiveHealth
+	}
 	return config, nil
 }
 
@@ -188,6 +244,88 @@ func loadGeoIP(code string) ([]*router.CIDR, error) {
 	return loadIP("geoip.dat", code)
 }
 
+func loadASN(code string) ([]*router.CIDR, error) {
+	return loadIP("asn.dat", code)
+}
+
+// parseASN normalizes an ASN such as "13335", "AS13335" or "!as13335" into the
+// "AS13335" code used in ASN database files.
+func parseASN(s string) (string, bool, error) {
+	s = strings.TrimSpace(s)
+	isReverseMatch := false
+	if strings.HasPrefix(s, "!") {
+		s = s[1:]
+		isReverseMatch = true
+	}
+	if len(s) > 2 && strings.EqualFold(s[:2], "AS") {
+		s = s[2:]
+	}
+	number, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return "", false, errors.New("invalid ASN: ", s).Base(err)
+	}
+	return "AS" + strconv.FormatUint(number, 10), isReverseMatch, nil
+}
+
+// ASNList is a list of ASNs, given either as numbers or as strings.
+type ASNList []string
+
+// UnmarshalJSON implements encoding/json.Unmarshaler.UnmarshalJSON
+func (v *ASNList) UnmarshalJSON(data []byte) error {
+	var rawList []json.RawMessage
+	if err := json.Unmarshal(data, &rawList); err != nil {
+		rawList = []json.RawMessage{data}
+	}
+	for _, raw := range rawList {
+		var number uint32
+		if err := json.Unmarshal(raw, &number); err == nil {
+			*v = append(*v, strconv.FormatUint(uint64(number), 10))
+			continue
+		}
+		var str string
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return errors.New("unknown format of an ASN list: " + string(data))
+		}
+		*v = append(*v, str)
+	}
+	return nil
+}
+
+// ToASNList loads the prefixes of each ASN from asn.dat, or from an external
+// file when given as "ext:file:ASN".
+func ToASNList(asns ASNList) ([]*router.GeoIP, error) {
+	var asnList []*router.GeoIP
+	for _, asn := range asns {
+		filename := "asn.dat"
+		if strings.HasPrefix(asn, "ext:") {
+			kv := strings.Split(asn[4:], ":")
+			if len(kv) != 2 || len(kv[0]) == 0 {
+				return nil, errors.New("invalid external resource: ", asn)
+			}
+			filename = kv[0]
+			asn = kv[1]
+		}
+		code, isReverseMatch, err := parseASN(asn)
+		if err != nil {
+			return nil, err
+		}
+		cidrs, err := loadIP(filename, code)
+		if err != nil {
+			return nil, errors.New("failed to load ASN: ", code, " from ", filename).Base(err)
+		}
+		countryCode := code
+		if filename != "asn.dat" {
+			countryCode = strings.ToUpper(filename + "_" + code)
+		}
+		asnList = append(asnList, &router.GeoIP{
+			CountryCode:  countryCode,
+			Cidr:         cidrs,
+			ReverseMatch: isReverseMatch,
+		})
+	}
+	return asnList, nil
+}
+
 var (
 	FileCache = make(map[string][]byte)
 	IPCache   = make(map[string]*router.GeoIP)
@@ -470,6 +608,23 @@ func ToCidrList(ips StringList) ([]*router.GeoIP, error) {
 			})
 			continue
 		}
+		if strings.HasPrefix(ip, "asn:") {
+			code, isReverseMatch, err := parseASN(ip[4:])
+			if err != nil {
+				return nil, err
+			}
+			asn, err := loadASN(code)
+			if err != nil {
+				return nil, errors.New("failed to load ASN: ", code).Base(err)
+			}
+
+			geoipList = append(geoipList, &router.GeoIP{
+				CountryCode:  code,
+				Cidr:         asn,
+				ReverseMatch: isReverseMatch,
+			})
+			continue
+		}
 		isExtDatFile := 0
 		{
 			const prefix = "ext:"
@@ -538,10 +693,14 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		Network    *NetworkList      `json:"network"`
 		SourceIP   *StringList       `json:"source"`
 		SourcePort *PortList         `json:"sourcePort"`
+		ASN        *ASNList          `json:"asn"`
+		SourceASN  *ASNList          `json:"sourceAsn"`
 		User       *StringList       `json:"user"`
 		InboundTag *StringList       `json:"inboundTag"`
 		Protocols  *StringList       `json:"protocol"`
 		Attributes map[string]string `json:"attrs"`
+
+		FallbackChain *FallbackChainConfig `json:"fallbackChain"`
 	}
 	rawFieldRule := new(RawFieldRule)
 	err := json.Unmarshal(msg, rawFieldRule)
@@ -616,6 +775,22 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		rule.SourcePortList = rawFieldRule.SourcePort.Build()
 	}
 
+	if rawFieldRule.ASN != nil {
+		asnList, err := ToASNList(*rawFieldRule.ASN)
+		if err != nil {
+			return nil, err
+		}
+		rule.Asn = asnList
+	}
+
+	if rawFieldRule.SourceASN != nil {
+		asnList, err := ToASNList(*rawFieldRule.SourceASN)
+		if err != nil {
+			return nil, err
+		}
+		rule.SourceAsn = asnList
+	}
+
 	if rawFieldRule.User != nil {
 		for _, s := range *rawFieldRule.User {
 			rule.UserEmail = append(rule.UserEmail, s)
@@ -638,6 +813,13 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		rule.Attributes = rawFieldRule.Attributes
 	}
 
+	if rawFieldRule.FallbackChain != nil {
+		rule.FallbackChain, err = rawFieldRule.FallbackChain.Build()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return rule, nil
 }
 
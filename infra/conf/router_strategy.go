@@ -1,10 +1,13 @@
 package conf
 
 import (
+	"strings"
+
 	"google.golang.org/protobuf/proto"
 
 	"github.com/xtls/xray-core/app/observatory/burst"
 	"github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/infra/conf/cfgcommon/duration"
 )
 
@@ -13,6 +16,9 @@ const (
 	strategyLeastPing  string = "leastping"
 	strategyRoundRobin string = "roundrobin"
 	strategyLeastLoad  string = "leastload"
+
+	strategyConsistentHash     string = "consistenthash"
+	strategyWeightedRoundRobin string = "weightedroundrobin"
 )
 
 var (
@@ -21,6 +27,9 @@ var (
 		strategyLeastPing:  func() interface{} { return new(strategyEmptyConfig) },
 		strategyRoundRobin: func() interface{} { return new(strategyEmptyConfig) },
 		strategyLeastLoad:  func() interface{} { return new(strategyLeastLoadConfig) },
+
+		strategyConsistentHash:     func() interface{} { return new(strategyConsistentHashConfig) },
+		strategyWeightedRoundRobin: func() interface{} { return new(strategyWeightedRoundRobinConfig) },
 	}, "type", "settings")
 )
 
@@ -91,3 +100,41 @@ func (v *strategyLeastLoadConfig) Build() (proto.Message, error) {
 	}
 	return config, nil
 }
+
+type strategyWeightedRoundRobinConfig struct {
+	// weight settings, outbounds not matched have weight 1
+	Weights []*router.StrategyWeight `json:"weights,omitempty"`
+}
+
+// Build implements Buildable.
+func (v *strategyWeightedRoundRobinConfig) Build() (proto.Message, error) {
+	for _, w := range v.Weights {
+		if w.Value < 0 {
+			return nil, errors.New("negative weight of weightedRoundRobin strategy: ", w.Match)
+		}
+	}
+	return &router.StrategyWeightedRoundRobinConfig{
+		Weights: v.Weights,
+	}, nil
+}
+
+type strategyConsistentHashConfig struct {
+	// hash key of connections: "sourceIP", "user" or "domain". default "sourceIP"
+	HashKey string `json:"hashKey,omitempty"`
+}
+
+// Build implements Buildable.
+func (v *strategyConsistentHashConfig) Build() (proto.Message, error) {
+	config := &router.StrategyConsistentHashConfig{}
+	switch strings.ToLower(v.HashKey) {
+	case "", "sourceip":
+		config.HashKey = router.StrategyConsistentHashConfig_SourceIp
+	case "user":
+		config.HashKey = router.StrategyConsistentHashConfig_User
+	case "domain":
+		config.HashKey = router.StrategyConsistentHashConfig_Domain
+	default:
+		return nil, errors.New("unknown hash key of consistentHash strategy: ", v.HashKey)
+	}
+	return config, nil
+}
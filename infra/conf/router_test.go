@@ -74,6 +74,60 @@ func TestToCidrList(t *testing.T) {
 	}
 }
 
+func TestToASNList(t *testing.T) {
+	tempDir := t.TempDir()
+	asnList := &router.GeoIPList{
+		Entry: []*router.GeoIP{
+			{
+				CountryCode: "AS13335",
+				Cidr:        []*router.CIDR{{Ip: []byte{1, 1, 1, 0}, Prefix: 24}},
+			},
+			{
+				CountryCode: "AS15169",
+				Cidr:        []*router.CIDR{{Ip: []byte{8, 8, 8, 0}, Prefix: 24}},
+			},
+		},
+	}
+	data, err := proto.Marshal(asnList)
+	common.Must(err)
+	common.Must(os.WriteFile(filepath.Join(tempDir, "asn.dat"), data, 0o644))
+
+	os.Setenv("xray.location.asset", tempDir)
+	defer os.Unsetenv("xray.location.asset")
+
+	var asns ASNList
+	common.Must(json.Unmarshal([]byte(`[13335, "as15169", "!AS13335"]`), &asns))
+
+	geoips, err := ToASNList(asns)
+	if err != nil {
+		t.Fatalf("Failed to parse ASN list, got %s", err)
+	}
+	if len(geoips) != 3 {
+		t.Fatalf("expected 3 ASN entries, got %d", len(geoips))
+	}
+	if geoips[0].CountryCode != "AS13335" || geoips[1].CountryCode != "AS15169" || geoips[2].CountryCode != "AS13335" {
+		t.Error("unexpected ASN codes: ", geoips)
+	}
+	if geoips[0].ReverseMatch || !geoips[2].ReverseMatch {
+		t.Error("unexpected reverse match flags")
+	}
+
+	geoips, err = ToCidrList(StringList([]string{"asn:15169", "1.2.3.4"}))
+	if err != nil {
+		t.Fatalf("Failed to parse IP list with ASN, got %s", err)
+	}
+	if len(geoips) != 2 || geoips[0].CountryCode != "AS15169" {
+		t.Error("unexpected IP list: ", geoips)
+	}
+
+	if _, err := ToASNList(ASNList{"AS64512"}); err == nil {
+		t.Error("expected error for ASN missing from database")
+	}
+	if _, err := ToASNList(ASNList{"cloudflare"}); err == nil {
+		t.Error("expected error for malformed ASN")
+	}
+}
+
 func TestRouterConfig(t *testing.T) {
 	createParser := func() func(string) (proto.Message, error) {
 		return func(s string) (proto.Message, error) {
@@ -148,6 +202,31 @@ func TestRouterConfig(t *testing.T) {
 							}
 						},
 						"fallbackTag": "fall"
+					},
+					{
+						"tag": "b3",
+						"selector": ["test"],
+						"strategy": {
+							"type": "consistentHash",
+							"settings": {
+								"hashKey": "domain"
+							}
+						}
+					},
+					{
+						"tag": "b4",
+						"selector": ["test"],
+						"strategy": {
+							"type": "weightedRoundRobin",
+							"settings": {
+								"weights": [
+									{
+										"match": "test",
+										"value": 70
+									}
+								]
+							}
+						}
 					}
 				]
 			}`,
@@ -183,6 +262,27 @@ func TestRouterConfig(t *testing.T) {
 						}),
 						FallbackTag: "fall",
 					},
+					{
+						Tag:              "b3",
+						OutboundSelector: []string{"test"},
+						Strategy:         "consistenthash",
+						StrategySettings: serial.ToTypedMessage(&router.StrategyConsistentHashConfig{
+							HashKey: router.StrategyConsistentHashConfig_Domain,
+						}),
+					},
+					{
+						Tag:              "b4",
+						OutboundSelector: []string{"test"},
+						Strategy:         "weightedroundrobin",
+						StrategySettings: serial.ToTypedMessage(&router.StrategyWeightedRoundRobinConfig{
+							Weights: []*router.StrategyWeight{
+								{
+									Match: "test",
+									Value: 70,
+								},
+							},
+						}),
+					},
 				},
 				Rule: []*router.RoutingRule{
 					{
@@ -307,5 +407,45 @@ func TestRouterConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			Input: `{
+				"passiveHealth": {
+					"maxFailures": 3,
+					"cooldown": "1m"
+				},
+				"rules": [
+					{
+						"port": 443,
+						"outboundTag": "a",
+						"fallbackChain": {
+							"outbounds": ["b", "c"],
+							"attemptTimeout": "3s"
+						}
+					}
+				]
+			}`,
+			Parser: createParser(),
+			Output: &router.Config{
+				DomainStrategy: router.Config_AsIs,
+				Rule: []*router.RoutingRule{
+					{
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "a",
+						},
+						PortList: &net.PortList{
+							Range: []*net.PortRange{{From: 443, To: 443}},
+						},
+						FallbackChain: &router.FallbackChain{
+							OutboundTag:    []string{"b", "c"},
+							AttemptTimeout: int64(3 * time.Second),
+						},
+					},
+				},
+				PassiveHealth: &router.PassiveHealthConfig{
+					MaxFailures: 3,
+					Cooldown:    int64(time.Minute),
+				},
+			},
+		},
 	})
 }
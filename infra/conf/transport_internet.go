@@ -1,6 +1,7 @@
 package conf
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -18,6 +19,7 @@ import (
 	"github.com/xtls/xray-core/transport/internet"
 	"github.com/xtls/xray-core/transport/internet/httpupgrade"
 	"github.com/xtls/xray-core/transport/internet/kcp"
+	"github.com/xtls/xray-core/transport/internet/quic"
 	"github.com/xtls/xray-core/transport/internet/reality"
 	"github.com/xtls/xray-core/transport/internet/splithttp"
 	"github.com/xtls/xray-core/transport/internet/tcp"
@@ -44,15 +46,34 @@ var (
 )
 
 type KCPConfig struct {
-	Mtu             *uint32         `json:"mtu"`
-	Tti             *uint32         `json:"tti"`
-	UpCap           *uint32         `json:"uplinkCapacity"`
-	DownCap         *uint32         `json:"downlinkCapacity"`
-	Congestion      *bool           `json:"congestion"`
-	ReadBufferSize  *uint32         `json:"readBufferSize"`
-	WriteBufferSize *uint32         `json:"writeBufferSize"`
-	HeaderConfig    json.RawMessage `json:"header"`
-	Seed            *string         `json:"seed"`
+	Mtu             *uint32            `json:"mtu"`
+	Tti             *uint32            `json:"tti"`
+	UpCap           *uint32            `json:"uplinkCapacity"`
+	DownCap         *uint32            `json:"downlinkCapacity"`
+	Congestion      *bool              `json:"congestion"`
+	ReadBufferSize  *uint32            `json:"readBufferSize"`
+	WriteBufferSize *uint32            `json:"writeBufferSize"`
+	HeaderConfig    json.RawMessage    `json:"header"`
+	Seed            *string            `json:"seed"`
+	FEC             *KCPFECConfig      `json:"fec"`
+	PortHopping     *PortHoppingConfig `json:"portHopping"`
+}
+
+type KCPFECConfig struct {
+	DataShards   uint32 `json:"dataShards"`
+	ParityShards uint32 `json:"parityShards"`
+}
+
+// Build implements Buildable.
+func (c *KCPFECConfig) Build() (*kcp.FEC, error) {
+	config := &kcp.FEC{
+		DataShards:   c.DataShards,
+		ParityShards: c.ParityShards,
+	}
+	if data, parity := config.GetDataShardsValue(), config.GetParityShardsValue(); data+parity > 256 {
+		return nil, errors.New("invalid mKCP FEC shards: ", data, "+", parity).AtError()
+	}
+	return config, nil
 }
 
 // Build implements Buildable.
@@ -114,6 +135,22 @@ func (c *KCPConfig) Build() (proto.Message, error) {
 		config.Seed = &kcp.EncryptionSeed{Seed: *c.Seed}
 	}
 
+	if c.FEC != nil {
+		fec, err := c.FEC.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Fec = fec
+	}
+
+	if c.PortHopping != nil {
+		portHopping, err := c.PortHopping.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.PortHopping = portHopping
+	}
+
 	return config, nil
 }
 
@@ -184,6 +221,23 @@ func (c *WebSocketConfig) Build() (proto.Message, error) {
 	return config, nil
 }
 
+type QUICConfig struct {
+	IdleTimeout          uint32 `json:"idleTimeout"`
+	KeepAlivePeriod      uint32 `json:"keepAlivePeriod"`
+	MaxConcurrentStreams uint32 `json:"maxConcurrentStreams"`
+	ZeroRTT              bool   `json:"zeroRtt"`
+}
+
+// Build implements Buildable.
+func (c *QUICConfig) Build() (proto.Message, error) {
+	return &quic.Config{
+		IdleTimeout:          c.IdleTimeout,
+		KeepAlivePeriod:      c.KeepAlivePeriod,
+		MaxConcurrentStreams: c.MaxConcurrentStreams,
+		ZeroRtt:              c.ZeroRTT,
+	}, nil
+}
+
 type HttpUpgradeConfig struct {
 	Host                string            `json:"host"`
 	Path                string            `json:"path"`
@@ -374,6 +428,8 @@ func (c *TLSCertConfig) Build() (*tls.Certificate, error) {
 		certificate.Usage = tls.Certificate_AUTHORITY_VERIFY
 	case "issue":
 		certificate.Usage = tls.Certificate_AUTHORITY_ISSUE
+	case "verifyclient":
+		certificate.Usage = tls.Certificate_AUTHORITY_VERIFY_CLIENT
 	default:
 		certificate.Usage = tls.Certificate_ENCIPHERMENT
 	}
@@ -404,6 +460,86 @@ type TLSConfig struct {
 	PinnedPeerCertificatePublicKeySha256 *[]string        `json:"pinnedPeerCertificatePublicKeySha256"`
 	CurvePreferences                     *StringList      `json:"curvePreferences"`
 	MasterKeyLog                         string           `json:"masterKeyLog"`
+	ECHConfigList                        string           `json:"echConfigList"`
+	ECHDNSName                           string           `json:"echDnsName"`
+	ECHServerKeys                        string           `json:"echServerKeys"`
+	ECHServerKeysFile                    string           `json:"echServerKeysFile"`
+	ClientAuth                           string           `json:"clientAuth"`
+	ClientUsers                          []*TLSClientUser `json:"clientUsers"`
+	ACME                                 *ACMEConfig      `json:"acme"`
+}
+
+type ACMEConfig struct {
+	Domains      *StringList `json:"domains"`
+	Email        string      `json:"email"`
+	DirectoryURL string      `json:"directoryUrl"`
+	StoragePath  string      `json:"storagePath"`
+	Challenge    string      `json:"challenge"`
+	HTTPAddress  string      `json:"httpAddress"`
+	DNSHook      string      `json:"dnsHook"`
+}
+
+// Build implements Buildable.
+func (c *ACMEConfig) Build() (*tls.ACME, error) {
+	if c.Domains == nil || len(*c.Domains) == 0 {
+		return nil, errors.New("no domain of ACME certificate")
+	}
+	config := &tls.ACME{
+		Domain:       []string(*c.Domains),
+		Email:        c.Email,
+		DirectoryUrl: c.DirectoryURL,
+		StoragePath:  c.StoragePath,
+		HttpAddress:  c.HTTPAddress,
+		DnsHook:      c.DNSHook,
+	}
+	switch strings.ToLower(c.Challenge) {
+	case "", "http-01":
+		config.Challenge = tls.ACME_HTTP_01
+		if c.HTTPAddress == "" {
+			return nil, errors.New("no HTTP address of HTTP-01 challenges")
+		}
+	case "tls-alpn-01":
+		config.Challenge = tls.ACME_TLS_ALPN_01
+	case "dns-01":
+		config.Challenge = tls.ACME_DNS_01
+		if c.DNSHook == "" {
+			return nil, errors.New("no DNS hook of DNS-01 challenges")
+		}
+	default:
+		return nil, errors.New("unknown ACME challenge: ", c.Challenge)
+	}
+	return config, nil
+}
+
+// TLSClientUser is the user of client certificates.
+type TLSClientUser struct {
+	Email       string `json:"email"`
+	Level       uint32 `json:"level"`
+	Subject     string `json:"subject"`
+	SAN         string `json:"san"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Build implements Buildable.
+func (c *TLSClientUser) Build() (*tls.ClientUser, error) {
+	user := &tls.ClientUser{
+		Email:   c.Email,
+		Level:   c.Level,
+		Subject: c.Subject,
+		San:     c.SAN,
+	}
+	if c.Fingerprint != "" {
+		// colons between bytes as printed by openssl are allowed
+		fingerprint, err := hex.DecodeString(strings.ReplaceAll(c.Fingerprint, ":", ""))
+		if err != nil || len(fingerprint) != sha256.Size {
+			return nil, errors.New("invalid SHA256 fingerprint of client certificate: ", c.Fingerprint)
+		}
+		user.Fingerprint = fingerprint
+	}
+	if user.Subject == "" && user.San == "" && len(user.Fingerprint) == 0 {
+		return nil, errors.New("no subject, SAN or fingerprint of client certificate of user ", c.Email)
+	}
+	return user, nil
 }
 
 // Build implements Buildable.
@@ -463,6 +599,73 @@ func (c *TLSConfig) Build() (proto.Message, error) {
 
 	config.MasterKeyLog = c.MasterKeyLog
 
+	if c.ECHConfigList != "" {
+		configList, err := base64.StdEncoding.DecodeString(c.ECHConfigList)
+		if err != nil {
+			return nil, errors.New("invalid ECH config list").Base(err)
+		}
+		config.EchConfigList = configList
+	}
+	config.EchDnsName = c.ECHDNSName
+	if len(config.EchConfigList) > 0 || config.EchDnsName != "" {
+		// ECH is only supported by the TLS client of the standard library
+		switch config.Fingerprint {
+		case "":
+			config.Fingerprint = "unsafe"
+		case "unsafe":
+		default:
+			return nil, errors.New("ECH is not supported with fingerprint ", config.Fingerprint)
+		}
+	}
+	if c.ECHServerKeys != "" {
+		if strings.HasPrefix(strings.TrimSpace(c.ECHServerKeys), "-----BEGIN") {
+			config.EchServerKeys = []byte(c.ECHServerKeys)
+		} else {
+			keys, err := base64.StdEncoding.DecodeString(c.ECHServerKeys)
+			if err != nil {
+				return nil, errors.New("invalid ECH server keys").Base(err)
+			}
+			config.EchServerKeys = keys
+		}
+	}
+	config.EchServerKeysPath = c.ECHServerKeysFile
+
+	switch strings.ToLower(c.ClientAuth) {
+	case "", "none":
+		config.ClientAuth = tls.ClientAuth_NoClientCert
+		for _, cert := range config.Certificate {
+			if cert.Usage == tls.Certificate_AUTHORITY_VERIFY_CLIENT {
+				config.ClientAuth = tls.ClientAuth_RequireAndVerifyClientCert
+				break
+			}
+		}
+	case "request":
+		config.ClientAuth = tls.ClientAuth_RequestClientCert
+	case "require":
+		config.ClientAuth = tls.ClientAuth_RequireAnyClientCert
+	case "verifyifgiven":
+		config.ClientAuth = tls.ClientAuth_VerifyClientCertIfGiven
+	case "requireandverify":
+		config.ClientAuth = tls.ClientAuth_RequireAndVerifyClientCert
+	default:
+		return nil, errors.New("unknown client auth: ", c.ClientAuth)
+	}
+	for _, u := range c.ClientUsers {
+		user, err := u.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.ClientUser = append(config.ClientUser, user)
+	}
+
+	if c.ACME != nil {
+		acme, err := c.ACME.Build()
+		if err != nil {
+			return nil, errors.New("failed to build ACME config").Base(err)
+		}
+		config.Acme = acme
+	}
+
 	return config, nil
 }
 
@@ -660,7 +863,7 @@ func (p TransportProtocol) Build() (string, error) {
 	case "h2", "h3", "http":
 		return "", errors.PrintRemovedFeatureError("HTTP transport (without header padding, etc.)", "XHTTP stream-one H2 & H3")
 	case "quic":
-		return "", errors.PrintRemovedFeatureError("QUIC transport (without web service, etc.)", "XHTTP stream-one H3")
+		return "quic", nil
 	default:
 		return "", errors.New("Config: unknown transport protocol: ", p)
 	}
@@ -691,6 +894,69 @@ type SocketConfig struct {
 	Interface            string                 `json:"interface"`
 	TcpMptcp             bool                   `json:"tcpMptcp"`
 	CustomSockopt        []*CustomSockoptConfig `json:"customSockopt"`
+	HappyEyeballs        *HappyEyeballsConfig   `json:"happyEyeballs"`
+	Impairment           *ImpairmentConfig      `json:"impairment"`
+}
+
+type HappyEyeballsConfig struct {
+	PrioritizeIPv6   bool   `json:"prioritizeIPv6"`
+	Interleave       uint32 `json:"interleave"`
+	TryDelayMs       uint64 `json:"tryDelayMs"`
+	MaxConcurrentTry uint32 `json:"maxConcurrentTry"`
+}
+
+// Build converts the config to its proto message.
+func (c *HappyEyeballsConfig) Build() *internet.HappyEyeballsConfig {
+	return &internet.HappyEyeballsConfig{
+		PrioritizeIpv6:   c.PrioritizeIPv6,
+		Interleave:       c.Interleave,
+		TryDelayMs:       c.TryDelayMs,
+		MaxConcurrentTry: c.MaxConcurrentTry,
+	}
+}
+
+type ImpairmentConfig struct {
+	Latency   uint32  `json:"latency"`
+	Jitter    uint32  `json:"jitter"`
+	Bandwidth uint64  `json:"bandwidth"`
+	Loss      float32 `json:"loss"`
+	Reorder   float32 `json:"reorder"`
+	Reset     float32 `json:"reset"`
+	Seed      int64   `json:"seed"`
+}
+
+// Build converts the config to its proto message.
+func (c *ImpairmentConfig) Build() (*internet.ImpairmentConfig, error) {
+	for _, percentage := range []float32{c.Loss, c.Reorder, c.Reset} {
+		if percentage < 0 || percentage > 100 {
+			return nil, errors.New("invalid impairment percentage: ", percentage).AtError()
+		}
+	}
+	return &internet.ImpairmentConfig{
+		LatencyMs: c.Latency,
+		JitterMs:  c.Jitter,
+		Bandwidth: c.Bandwidth,
+		Loss:      c.Loss,
+		Reorder:   c.Reorder,
+		ResetRate: c.Reset,
+		Seed:      c.Seed,
+	}, nil
+}
+
+type PortHoppingConfig struct {
+	Ports    *PortList `json:"ports"`
+	Interval uint32    `json:"interval"`
+}
+
+// Build converts the config to its proto message.
+func (c *PortHoppingConfig) Build() (*internet.PortHoppingConfig, error) {
+	if c.Ports == nil || len(c.Ports.Range) == 0 {
+		return nil, errors.New("no port to hop among").AtError()
+	}
+	return &internet.PortHoppingConfig{
+		Ports:    c.Ports.Build(),
+		Interval: c.Interval,
+	}, nil
 }
 
 // Build implements Buildable.
@@ -760,6 +1026,19 @@ func (c *SocketConfig) Build() (*internet.SocketConfig, error) {
 		customSockopts = append(customSockopts, customSockopt)
 	}
 
+	var happyEyeballs *internet.HappyEyeballsConfig
+	if c.HappyEyeballs != nil {
+		happyEyeballs = c.HappyEyeballs.Build()
+	}
+
+	var impairment *internet.ImpairmentConfig
+	if c.Impairment != nil {
+		var err error
+		if impairment, err = c.Impairment.Build(); err != nil {
+			return nil, err
+		}
+	}
+
 	return &internet.SocketConfig{
 		Mark:                 c.Mark,
 		Tfo:                  tfo,
@@ -778,6 +1057,8 @@ func (c *SocketConfig) Build() (*internet.SocketConfig, error) {
 		Interface:            c.Interface,
 		TcpMptcp:             c.TcpMptcp,
 		CustomSockopt:        customSockopts,
+		HappyEyeballs:        happyEyeballs,
+		Impairment:           impairment,
 	}, nil
 }
 
@@ -792,11 +1073,26 @@ type StreamConfig struct {
 	TCPSettings         *TCPConfig         `json:"tcpSettings"`
 	XHTTPSettings       *SplitHTTPConfig   `json:"xhttpSettings"`
 	SplitHTTPSettings   *SplitHTTPConfig   `json:"splithttpSettings"`
+	QUICSettings        *QUICConfig        `json:"quicSettings"`
 	KCPSettings         *KCPConfig         `json:"kcpSettings"`
 	GRPCSettings        *GRPCConfig        `json:"grpcSettings"`
 	WSSettings          *WebSocketConfig   `json:"wsSettings"`
 	HTTPUPGRADESettings *HttpUpgradeConfig `json:"httpupgradeSettings"`
 	SocketSettings      *SocketConfig      `json:"sockopt"`
+	Prewarm             *PrewarmConfig     `json:"prewarm"`
+}
+
+type PrewarmConfig struct {
+	Size    uint32 `json:"size"`
+	MaxIdle uint32 `json:"maxIdle"`
+}
+
+// Build converts the config to its proto message.
+func (c *PrewarmConfig) Build() *internet.PrewarmConfig {
+	return &internet.PrewarmConfig{
+		Size:    c.Size,
+		MaxIdle: c.MaxIdle,
+	}
 }
 
 // Build implements Buildable.
@@ -817,6 +1113,9 @@ func (c *StreamConfig) Build() (*internet.StreamConfig, error) {
 	}
 	switch strings.ToLower(c.Security) {
 	case "", "none":
+		if config.ProtocolName == "quic" {
+			return nil, errors.New("QUIC transport requires TLS.")
+		}
 	case "tls":
 		tlsSettings := c.TLSSettings
 		if tlsSettings == nil {
@@ -826,6 +1125,9 @@ func (c *StreamConfig) Build() (*internet.StreamConfig, error) {
 		if err != nil {
 			return nil, errors.New("Failed to build TLS config.").Base(err)
 		}
+		if len(ts.(*tls.Config).ClientUser) > 0 && config.ProtocolName != "" && config.ProtocolName != "tcp" {
+			return nil, errors.New("TLS client users only support RAW for now.")
+		}
 		tm := serial.ToTypedMessage(ts)
 		config.SecuritySettings = append(config.SecuritySettings, tm)
 		config.SecurityType = tm.Type
@@ -874,6 +1176,16 @@ func (c *StreamConfig) Build() (*internet.StreamConfig, error) {
 			Settings:     serial.ToTypedMessage(hs),
 		})
 	}
+	if c.QUICSettings != nil {
+		qs, err := c.QUICSettings.Build()
+		if err != nil {
+			return nil, errors.New("Failed to build QUIC config.").Base(err)
+		}
+		config.TransportSettings = append(config.TransportSettings, &internet.TransportConfig{
+			ProtocolName: "quic",
+			Settings:     serial.ToTypedMessage(qs),
+		})
+	}
 	if c.KCPSettings != nil {
 		ts, err := c.KCPSettings.Build()
 		if err != nil {
@@ -921,6 +1233,9 @@ func (c *StreamConfig) Build() (*internet.StreamConfig, error) {
 		}
 		config.SocketSettings = ss
 	}
+	if c.Prewarm != nil {
+		config.Prewarm = c.Prewarm.Build()
+	}
 	return config, nil
 }
 
@@ -4,8 +4,14 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/serial"
 	. "github.com/xtls/xray-core/infra/conf"
 	"github.com/xtls/xray-core/transport/internet"
+	"github.com/xtls/xray-core/transport/internet/kcp"
+	"github.com/xtls/xray-core/transport/internet/quic"
+	"github.com/xtls/xray-core/transport/internet/tls"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -148,4 +154,238 @@ func TestSocketConfig(t *testing.T) {
 	if expectedOutput.ParseTFOValue() != -1 {
 		t.Fatalf("unexpected parsed TFO value, which should be -1")
 	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"impairment": {
+					"latency": 100,
+					"jitter": 20,
+					"bandwidth": 1000000,
+					"loss": 1.5,
+					"reset": 0.1,
+					"seed": 1
+				}
+			}`,
+			Parser: createParser(),
+			Output: &internet.SocketConfig{
+				Impairment: &internet.ImpairmentConfig{
+					LatencyMs: 100,
+					JitterMs:  20,
+					Bandwidth: 1000000,
+					Loss:      1.5,
+					ResetRate: 0.1,
+					Seed:      1,
+				},
+			},
+		},
+	})
+	if _, err := createParser()(`{"impairment": {"loss": 101}}`); err == nil {
+		t.Error("expected error of invalid loss")
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	createParser := func() func(string) (proto.Message, error) {
+		return func(s string) (proto.Message, error) {
+			config := new(TLSConfig)
+			if err := json.Unmarshal([]byte(s), config); err != nil {
+				return nil, err
+			}
+			return config.Build()
+		}
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"serverName": "secret.example.com",
+				"echConfigList": "AAQBAgME",
+				"echDnsName": "example.com"
+			}`,
+			Parser: createParser(),
+			Output: &tls.Config{
+				ServerName:    "secret.example.com",
+				Fingerprint:   "unsafe",
+				EchConfigList: []byte{0, 4, 1, 2, 3, 4},
+				EchDnsName:    "example.com",
+				Certificate:   []*tls.Certificate{},
+			},
+		},
+		{
+			Input: `{
+				"echServerKeys": "AQID",
+				"echServerKeysFile": "/etc/xray/ech.pem"
+			}`,
+			Parser: createParser(),
+			Output: &tls.Config{
+				EchServerKeys:     []byte{1, 2, 3},
+				EchServerKeysPath: "/etc/xray/ech.pem",
+				Certificate:       []*tls.Certificate{},
+			},
+		},
+		{
+			Input: `{
+				"clientAuth": "verifyIfGiven",
+				"clientUsers": [
+					{"email": "alice@example.com", "level": 1, "subject": "alice"},
+					{"email": "bob@example.com", "fingerprint": "01:02:03:04:05:06:07:08:09:0a:0b:0c:0d:0e:0f:10:11:12:13:14:15:16:17:18:19:1a:1b:1c:1d:1e:1f:20"}
+				]
+			}`,
+			Parser: createParser(),
+			Output: &tls.Config{
+				ClientAuth: tls.ClientAuth_VerifyClientCertIfGiven,
+				ClientUser: []*tls.ClientUser{
+					{Email: "alice@example.com", Level: 1, Subject: "alice"},
+					{Email: "bob@example.com", Fingerprint: []byte{
+						1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16,
+						17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32,
+					}},
+				},
+				Certificate: []*tls.Certificate{},
+			},
+		},
+		{
+			Input: `{
+				"acme": {
+					"domains": ["example.com", "www.example.com"],
+					"email": "admin@example.com",
+					"storagePath": "/var/lib/xray/acme",
+					"challenge": "tls-alpn-01"
+				}
+			}`,
+			Parser: createParser(),
+			Output: &tls.Config{
+				Acme: &tls.ACME{
+					Domain:      []string{"example.com", "www.example.com"},
+					Email:       "admin@example.com",
+					StoragePath: "/var/lib/xray/acme",
+					Challenge:   tls.ACME_TLS_ALPN_01,
+				},
+				Certificate: []*tls.Certificate{},
+			},
+		},
+	})
+
+	if _, err := createParser()(`{"acme": {"domains": ["example.com"], "challenge": "dns-01"}}`); err == nil {
+		t.Error("expected error of DNS-01 challenge without hook")
+	}
+	if _, err := createParser()(`{"acme": {"domains": ["example.com"]}}`); err == nil {
+		t.Error("expected error of HTTP-01 challenge without HTTP address")
+	}
+	if _, err := createParser()(`{"fingerprint": "firefox", "echDnsName": "example.com"}`); err == nil {
+		t.Error("expected error of ECH with fingerprint")
+	}
+
+	if _, err := createParser()(`{"clientUsers": [{"email": "alice@example.com"}]}`); err == nil {
+		t.Error("expected error of client user without certificate conditions")
+	}
+}
+
+func TestQUICStreamConfig(t *testing.T) {
+	createParser := func() func(string) (proto.Message, error) {
+		return func(s string) (proto.Message, error) {
+			config := new(StreamConfig)
+			if err := json.Unmarshal([]byte(s), config); err != nil {
+				return nil, err
+			}
+			return config.Build()
+		}
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"network": "quic",
+				"security": "tls",
+				"quicSettings": {
+					"idleTimeout": 60,
+					"zeroRtt": true
+				}
+			}`,
+			Parser: createParser(),
+			Output: &internet.StreamConfig{
+				ProtocolName: "quic",
+				SecurityType: serial.GetMessageType(&tls.Config{}),
+				SecuritySettings: []*serial.TypedMessage{
+					serial.ToTypedMessage(&tls.Config{Certificate: []*tls.Certificate{}}),
+				},
+				TransportSettings: []*internet.TransportConfig{{
+					ProtocolName: "quic",
+					Settings:     serial.ToTypedMessage(&quic.Config{IdleTimeout: 60, ZeroRtt: true}),
+				}},
+			},
+		},
+	})
+
+	if _, err := createParser()(`{"network": "quic"}`); err == nil {
+		t.Error("expected error of QUIC without TLS")
+	}
+}
+
+func TestTLSClientUsersTransport(t *testing.T) {
+	parse := func(s string) error {
+		config := new(StreamConfig)
+		common.Must(json.Unmarshal([]byte(s), config))
+		_, err := config.Build()
+		return err
+	}
+
+	tlsSettings := `"security": "tls", "tlsSettings": {"clientAuth": "requireAndVerify", "clientUsers": [{"email": "alice@example.com", "subject": "alice"}]}`
+	if err := parse(`{"network": "raw", ` + tlsSettings + `}`); err != nil {
+		t.Error(err)
+	}
+	if err := parse(`{` + tlsSettings + `}`); err != nil {
+		t.Error(err)
+	}
+	for _, network := range []string{"ws", "grpc", "httpupgrade", "xhttp"} {
+		if err := parse(`{"network": "` + network + `", ` + tlsSettings + `}`); err == nil {
+			t.Error("expected error of client users on ", network)
+		}
+	}
+}
+
+func TestKCPConfig(t *testing.T) {
+	creator := func() Buildable {
+		return new(KCPConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"mtu": 1350,
+				"fec": {
+					"dataShards": 10,
+					"parityShards": 3
+				}
+			}`,
+			Parser: loadJSON(creator),
+			Output: &kcp.Config{
+				Mtu: &kcp.MTU{Value: 1350},
+				Fec: &kcp.FEC{DataShards: 10, ParityShards: 3},
+			},
+		},
+		{
+			Input: `{
+				"portHopping": {
+					"ports": "20000-20010",
+					"interval": 60
+				}
+			}`,
+			Parser: loadJSON(creator),
+			Output: &kcp.Config{
+				PortHopping: &internet.PortHoppingConfig{
+					Ports:    &net.PortList{Range: []*net.PortRange{{From: 20000, To: 20010}}},
+					Interval: 60,
+				},
+			},
+		},
+	})
+
+	if _, err := loadJSON(creator)(`{"fec": {"dataShards": 250, "parityShards": 10}}`); err == nil {
+		t.Error("expected error of too many FEC shards")
+	}
+	if _, err := loadJSON(creator)(`{"portHopping": {}}`); err == nil {
+		t.Error("expected error of port hopping without ports")
+	}
 }
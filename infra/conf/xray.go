@@ -303,6 +303,12 @@ func (c *OutboundDetourConfig) Build() (*core.OutboundHandlerConfig, error) {
 		if err != nil {
 			return nil, err
 		}
+		// Prewarmed connections are dialed before the targets are known.
+		if ss.Prewarm != nil && ss.Prewarm.Size > 0 {
+			if protocol := strings.ToLower(c.Protocol); protocol != "vless" && protocol != "trojan" {
+				return nil, errors.New("prewarm is not supported by outbound protocol ", c.Protocol)
+			}
+		}
 		senderSettings.StreamSettings = ss
 	}
 
@@ -299,3 +299,26 @@ func TestConfig_Override(t *testing.T) {
 		})
 	}
 }
+
+func TestOutboundPrewarm(t *testing.T) {
+	build := func(s string) error {
+		config := new(OutboundDetourConfig)
+		common.Must(json.Unmarshal([]byte(s), config))
+		_, err := config.Build()
+		return err
+	}
+
+	if err := build(`{
+		"protocol": "trojan",
+		"settings": {"servers": [{"address": "127.0.0.1", "port": 443, "password": "password"}]},
+		"streamSettings": {"security": "tls", "prewarm": {"size": 4, "maxIdle": 60}}
+	}`); err != nil {
+		t.Error(err)
+	}
+	if err := build(`{
+		"protocol": "freedom",
+		"streamSettings": {"prewarm": {"size": 4}}
+	}`); err == nil {
+		t.Error("expected error of prewarm with freedom")
+	}
+}
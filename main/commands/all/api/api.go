@@ -17,6 +17,7 @@ var CmdAPI = &base.Command{
 		cmdSysStats,
 		cmdBalancerInfo,
 		cmdBalancerOverride,
+		cmdBalancerWeight,
 		cmdAddInbounds,
 		cmdAddOutbounds,
 		cmdRemoveInbounds,
@@ -25,6 +26,8 @@ var CmdAPI = &base.Command{
 		cmdInboundUserCount,
 		cmdAddRules,
 		cmdRemoveRules,
+		cmdRouteTest,
+		cmdDNS,
 		cmdSourceIpBlock,
 		cmdOnlineStats,
 	},
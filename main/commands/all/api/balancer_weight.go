@@ -0,0 +1,91 @@
+package api
+
+import (
+	"strconv"
+
+	routerService "github.com/xtls/xray-core/app/router/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdBalancerWeight = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api bw [--server=127.0.0.1:8080] <-b balancer> outboundTag weight",
+	Short:       "balancer weight",
+	Long: `
+Set the weight of an outbound in a balancer with "weightedRoundRobin"
+strategy.
+
+> Make sure you have "RoutingService" set in "config.api.services"
+of server config.
+
+A weight of 0 stops selecting the outbound.
+
+Arguments:
+
+	-b, -balancer
+		Tag of the balancer
+
+	-r, -remove
+		Remove the runtime weight and restore the configured one
+
+	-s, -server
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout
+		Timeout seconds to call API. Default 3
+
+Example:
+
+    {{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -b balancer tag 70
+    {{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -b balancer -r tag
+`,
+	Run: executeBalancerWeight,
+}
+
+func executeBalancerWeight(cmd *base.Command, args []string) {
+	var (
+		balancer string
+		remove   bool
+	)
+	cmd.Flag.StringVar(&balancer, "b", "", "")
+	cmd.Flag.StringVar(&balancer, "balancer", "", "")
+	cmd.Flag.BoolVar(&remove, "r", false, "")
+	cmd.Flag.BoolVar(&remove, "remove", false, "")
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	if balancer == "" {
+		base.Fatalf("balancer tag not specified")
+	}
+	unnamedArgs := cmd.Flag.Args()
+	if len(unnamedArgs) == 0 {
+		base.Fatalf("outbound tag not specified")
+	}
+
+	weight := float64(-1)
+	if !remove {
+		if len(unnamedArgs) < 2 {
+			base.Fatalf("weight not specified")
+		}
+		var err error
+		weight, err = strconv.ParseFloat(unnamedArgs[1], 32)
+		if err != nil || weight < 0 {
+			base.Fatalf("invalid weight: %s", unnamedArgs[1])
+		}
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := routerService.NewRoutingServiceClient(conn)
+	r := &routerService.SetBalancerWeightRequest{
+		BalancerTag: balancer,
+		OutboundTag: unnamedArgs[0],
+		Weight:      float32(weight),
+	}
+
+	_, err := client.SetBalancerWeight(ctx, r)
+	if err != nil {
+		base.Fatalf("failed to set balancer weight: %s", err)
+	}
+}
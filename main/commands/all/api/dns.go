@@ -0,0 +1,24 @@
+package api
+
+import (
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+// cmdDNS groups the commands of DnsService
+var cmdDNS = &base.Command{
+	UsageLine: "{{.Exec}} api dns",
+	Short:     "Manage the DNS of an Xray process",
+	Long: `{{.Exec}} {{.LongName}} provides tools to inspect and manipulate the DNS
+of Xray via its API.
+
+> Make sure you have "DnsService" set in "config.api.services"
+of server config.
+`,
+	Commands: []*base.Command{
+		cmdDNSResolve,
+		cmdDNSCache,
+		cmdDNSFlush,
+		cmdDNSHosts,
+		cmdDNSFakeDNS,
+	},
+}
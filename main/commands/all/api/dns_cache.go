@@ -0,0 +1,106 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	dnsService "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdDNSCache = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api dns cache [--server=127.0.0.1:8080] [domain]",
+	Short:       "List DNS cache",
+	Long: `
+List the answers cached by the name servers, of the domain if given.
+
+Arguments:
+
+	-json
+		Use json output.
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout seconds to call API. Default 3
+
+Example:
+
+    {{.Exec}} {{.LongName}} --server=127.0.0.1:8080
+    {{.Exec}} {{.LongName}} --server=127.0.0.1:8080 www.example.com
+`,
+	Run: executeDNSCache,
+}
+
+var cmdDNSFlush = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api dns flush [--server=127.0.0.1:8080] [domain]",
+	Short:       "Flush DNS cache",
+	Long: `
+Flush the answers cached by the name servers, of the domain if given.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout seconds to call API. Default 3
+
+Example:
+
+    {{.Exec}} {{.LongName}} --server=127.0.0.1:8080
+    {{.Exec}} {{.LongName}} --server=127.0.0.1:8080 www.example.com
+`,
+	Run: executeDNSFlush,
+}
+
+func executeDNSCache(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+	client := dnsService.NewDnsServiceClient(conn)
+	resp, err := client.ListCache(ctx, &dnsService.ListCacheRequest{Domain: cmd.Flag.Arg(0)})
+	if err != nil {
+		base.Fatalf("failed to list DNS cache: %s", err)
+	}
+
+	if apiJSON {
+		showJSONResponse(resp)
+		return
+	}
+
+	const tableIndent = 4
+	sb := new(strings.Builder)
+	writeRow(sb, tableIndent, 0, []string{"Server", "Domain", "Type", "TTL", "Result"},
+		[]string{"%-24s ", "%-30s ", "%-5s ", "%-8s ", "%s"})
+	for i, entry := range resp.Entries {
+		ttl := time.Until(time.Unix(entry.Expire, 0)).Truncate(time.Second)
+		result := strings.Join(formatIPs(entry.Ips), ", ")
+		if entry.Rcode != 0 {
+			result = fmt.Sprintf("rcode: %d", entry.Rcode)
+		}
+		writeRow(sb, tableIndent, i+1, []string{entry.Server, entry.Domain, entry.Type, ttl.String(), result},
+			[]string{"%-24s ", "%-30s ", "%-5s ", "%-8s ", "%s"})
+	}
+	fmt.Print(sb.String())
+}
+
+func executeDNSFlush(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+	client := dnsService.NewDnsServiceClient(conn)
+	resp, err := client.FlushCache(ctx, &dnsService.FlushCacheRequest{Domain: cmd.Flag.Arg(0)})
+	if err != nil {
+		base.Fatalf("failed to flush DNS cache: %s", err)
+	}
+	fmt.Printf("%d domain(s) flushed\n", resp.Flushed)
+}
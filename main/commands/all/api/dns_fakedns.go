@@ -0,0 +1,53 @@
+package api
+
+import (
+	"fmt"
+
+	dnsService "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdDNSFakeDNS = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api dns fakedns [--server=127.0.0.1:8080] <ip>",
+	Short:       "Look up the domain of a fake IP",
+	Long: `
+Look up the domain a fake IP of FakeDNS is assigned to.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout seconds to call API. Default 3
+
+Example:
+
+    {{.Exec}} {{.LongName}} --server=127.0.0.1:8080 198.18.0.1
+`,
+	Run: executeDNSFakeDNS,
+}
+
+func executeDNSFakeDNS(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	ip := net.ParseAddress(cmd.Flag.Arg(0))
+	if !ip.Family().IsIP() {
+		base.Fatalf("not an IP address: %s", cmd.Flag.Arg(0))
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+	client := dnsService.NewDnsServiceClient(conn)
+	resp, err := client.LookupFakeDNS(ctx, &dnsService.LookupFakeDNSRequest{Ip: ip.IP()})
+	if err != nil {
+		base.Fatalf("failed to look up fake IP: %s", err)
+	}
+	if resp.Domain == "" {
+		base.Fatalf("no domain assigned to %s", ip)
+	}
+	fmt.Println(resp.Domain)
+}
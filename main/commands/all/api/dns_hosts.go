@@ -0,0 +1,67 @@
+package api
+
+import (
+	dnsService "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdDNSHosts = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api dns hosts [--server=127.0.0.1:8080] [-r] <domain> [address...]",
+	Short:       "Add or remove static hosts",
+	Long: `
+Map a domain to IPs, or to another domain it is replaced with, in the
+static hosts of the DNS. Mappings added take precedence over the
+configured ones, and are lost on restart.
+
+Arguments:
+
+	-r, -remove
+		Remove the mapping added before
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout seconds to call API. Default 3
+
+Example:
+
+    {{.Exec}} {{.LongName}} --server=127.0.0.1:8080 www.example.com 1.2.3.4 ::1
+    {{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -r www.example.com
+`,
+	Run: executeDNSHosts,
+}
+
+func executeDNSHosts(cmd *base.Command, args []string) {
+	var remove bool
+	cmd.Flag.BoolVar(&remove, "r", false, "")
+	cmd.Flag.BoolVar(&remove, "remove", false, "")
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	unnamedArgs := cmd.Flag.Args()
+	if len(unnamedArgs) == 0 {
+		base.Fatalf("domain not specified")
+	}
+	if !remove && len(unnamedArgs) < 2 {
+		base.Fatalf("address not specified")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+	client := dnsService.NewDnsServiceClient(conn)
+	if remove {
+		if _, err := client.RemoveHosts(ctx, &dnsService.RemoveHostsRequest{Domain: unnamedArgs[0]}); err != nil {
+			base.Fatalf("failed to remove hosts: %s", err)
+		}
+		return
+	}
+	_, err := client.AddHosts(ctx, &dnsService.AddHostsRequest{
+		Domain:    unnamedArgs[0],
+		Addresses: unnamedArgs[1:],
+	})
+	if err != nil {
+		base.Fatalf("failed to add hosts: %s", err)
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	dnsService "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdDNSResolve = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api dns resolve [--server=127.0.0.1:8080] [-strategy=UseIP] [-fake] <domain>",
+	Short:       "Resolve a domain",
+	Long: `
+Resolve a domain through the DNS of Xray, including static hosts and
+the cache, showing which name server answered.
+
+Arguments:
+
+	-json
+		Use json output.
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout seconds to call API. Default 3
+
+	-strategy <UseIP|UseIPv4|UseIPv6>
+		Query strategy of the resolution. Default UseIP
+
+	-fake
+		Allow FakeDNS to answer.
+
+Example:
+
+    {{.Exec}} {{.LongName}} --server=127.0.0.1:8080 www.example.com
+`,
+	Run: executeDNSResolve,
+}
+
+func executeDNSResolve(cmd *base.Command, args []string) {
+	var (
+		strategy string
+		fake     bool
+	)
+	setSharedFlags(cmd)
+	cmd.Flag.StringVar(&strategy, "strategy", "UseIP", "")
+	cmd.Flag.BoolVar(&fake, "fake", false, "")
+	cmd.Flag.Parse(args)
+
+	unnamedArgs := cmd.Flag.Args()
+	if len(unnamedArgs) == 0 {
+		base.Fatalf("domain not specified")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+	client := dnsService.NewDnsServiceClient(conn)
+	resp, err := client.Resolve(ctx, &dnsService.ResolveRequest{
+		Domain:        unnamedArgs[0],
+		QueryStrategy: strategy,
+		FakeEnable:    fake,
+	})
+	if err != nil {
+		base.Fatalf("failed to resolve %s: %s", unnamedArgs[0], err)
+	}
+
+	if apiJSON {
+		showJSONResponse(resp)
+		return
+	}
+
+	sb := new(strings.Builder)
+	sb.WriteString(fmt.Sprintf("  - Domain: %s\n", resp.Domain))
+	sb.WriteString(fmt.Sprintf("  - Server: %s\n", resp.Server))
+	sb.WriteString(fmt.Sprintf("  - IPs: %s\n", strings.Join(formatIPs(resp.Ips), ", ")))
+	fmt.Print(sb.String())
+}
+
+func formatIPs(ips [][]byte) []string {
+	var s []string
+	for _, ip := range ips {
+		s = append(s, net.IPAddress(ip).String())
+	}
+	return s
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	routerService "github.com/xtls/xray-core/app/router/command"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRouteTest = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api route-test [--server=127.0.0.1:8080] [-domain=example.com] [-ip=1.2.3.4] [-port=443] ...",
+	Short:       "Explain the routing result of a connection",
+	Long: `
+Test which outbound a connection would be routed to, showing for
+each evaluated rule whether every condition matched, and the DNS
+lookups performed for IPIfNonMatch and IPOnDemand domain strategy.
+
+> Make sure you have "RoutingService" set in "config.api.services"
+of server config.
+
+Arguments:
+
+	-json
+		Use json output.
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout seconds to call API. Default 3
+
+	-inbound <tag>
+		Inbound tag of the connection.
+
+	-network <tcp|udp>
+		Network of the connection. Default tcp
+
+	-domain <domain>
+		Target domain.
+
+	-ip <ip,ip...>
+		Target IPs.
+
+	-port <port>
+		Target port.
+
+	-source <ip,ip...>
+		Source IPs.
+
+	-sport <port>
+		Source port.
+
+	-protocol <protocol>
+		Sniffed protocol, e.g. http, tls, quic, bittorrent.
+
+	-user <email>
+		Email of the inbound user.
+
+	-attrs <key=value,key=value...>
+		Additional attributes, e.g. HTTP headers.
+
+Example:
+
+    {{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -domain=www.example.com -port=443
+`,
+	Run: executeRouteTest,
+}
+
+func executeRouteTest(cmd *base.Command, args []string) {
+	var (
+		inbound  string
+		network  string
+		domain   string
+		ips      string
+		port     uint
+		sources  string
+		sport    uint
+		protocol string
+		user     string
+		attrs    string
+	)
+	setSharedFlags(cmd)
+	cmd.Flag.StringVar(&inbound, "inbound", "", "")
+	cmd.Flag.StringVar(&network, "network", "tcp", "")
+	cmd.Flag.StringVar(&domain, "domain", "", "")
+	cmd.Flag.StringVar(&ips, "ip", "", "")
+	cmd.Flag.UintVar(&port, "port", 0, "")
+	cmd.Flag.StringVar(&sources, "source", "", "")
+	cmd.Flag.UintVar(&sport, "sport", 0, "")
+	cmd.Flag.StringVar(&protocol, "protocol", "", "")
+	cmd.Flag.StringVar(&user, "user", "", "")
+	cmd.Flag.StringVar(&attrs, "attrs", "", "")
+	cmd.Flag.Parse(args)
+
+	rc := &routerService.RoutingContext{
+		InboundTag:   inbound,
+		TargetDomain: domain,
+		TargetPort:   uint32(port),
+		SourcePort:   uint32(sport),
+		Protocol:     protocol,
+		User:         user,
+	}
+	switch strings.ToLower(network) {
+	case "tcp":
+		rc.Network = net.Network_TCP
+	case "udp":
+		rc.Network = net.Network_UDP
+	default:
+		base.Fatalf("unknown network: %s", network)
+	}
+	rc.TargetIPs = parseIPList(ips)
+	rc.SourceIPs = parseIPList(sources)
+	if len(attrs) > 0 {
+		rc.Attributes = make(map[string]string)
+		for _, kv := range strings.Split(attrs, ",") {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				base.Fatalf("invalid attribute: %s", kv)
+			}
+			rc.Attributes[k] = v
+		}
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+	client := routerService.NewRoutingServiceClient(conn)
+	resp, err := client.ExplainRoute(ctx, &routerService.ExplainRouteRequest{RoutingContext: rc})
+	if err != nil {
+		base.Fatalf("failed to explain route: %s", err)
+	}
+
+	if apiJSON {
+		showJSONResponse(resp)
+		return
+	}
+
+	showRouteExplanation(resp)
+}
+
+func parseIPList(s string) [][]byte {
+	var ips [][]byte
+	for _, ip := range strings.Split(s, ",") {
+		if len(ip) == 0 {
+			continue
+		}
+		addr := net.ParseAddress(ip)
+		if !addr.Family().IsIP() {
+			base.Fatalf("not an IP address: %s", ip)
+		}
+		ips = append(ips, []byte(addr.IP()))
+	}
+	return ips
+}
+
+func showRouteExplanation(resp *routerService.ExplainRouteResponse) {
+	const tableIndent = 4
+	sb := new(strings.Builder)
+	if len(resp.Resolutions) > 0 {
+		sb.WriteString("  - DNS Resolutions:\n")
+		for i, r := range resp.Resolutions {
+			result := r.Error
+			if len(result) == 0 {
+				var ips []string
+				for _, ip := range r.Ips {
+					ips = append(ips, net.IPAddress(ip).String())
+				}
+				result = strings.Join(ips, ", ")
+			}
+			writeRow(sb, tableIndent, i+1, []string{r.Domain, result}, []string{"%-30s ", "%s"})
+		}
+	}
+	sb.WriteString("  - Rules:\n")
+	for i, r := range resp.Rules {
+		target := r.OutboundTag
+		if len(r.BalancerTag) > 0 {
+			target = "balancer:" + r.BalancerTag
+		}
+		status := "not matched"
+		if r.Matched {
+			status = "MATCHED"
+		}
+		if r.AfterResolve {
+			status += " (after resolve)"
+		}
+		var conds []string
+		for _, c := range r.Conditions {
+			conds = append(conds, c.Name+"="+strconv.FormatBool(c.Matched))
+		}
+		writeRow(sb, tableIndent, i+1, []string{r.RuleTag, target, status, strings.Join(conds, " ")},
+			[]string{"%-16s ", "%-20s ", "%-28s ", "%s"})
+	}
+	if resp.Route != nil {
+		sb.WriteString(fmt.Sprintf("  - Outbound: %s\n", resp.Route.OutboundTag))
+		if len(resp.FallbackTags) > 0 {
+			sb.WriteString(fmt.Sprintf("  - Fallbacks: %s\n", strings.Join(resp.FallbackTags, ", ")))
+		}
+	} else {
+		sb.WriteString("  - Outbound: no rule matched, the default outbound is used\n")
+	}
+	os.Stdout.WriteString(sb.String())
+}
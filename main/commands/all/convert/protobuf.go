@@ -58,7 +58,7 @@ func executeConvertConfigsToProtobuf(cmd *base.Command, args []string) {
 
 	pbConfig, err := core.LoadConfig("auto", unnamedArgs)
 	if err != nil {
-		base.Fatalf(err.Error())
+		base.Fatalf("%s", err)
 	}
 
 	if optDump {
@@ -3,6 +3,7 @@ package dns
 import (
 	"context"
 	"io"
+	"strings"
 	"sync"
 	"time"
 
@@ -238,19 +239,24 @@ func (h *Handler) handleIPQuery(id uint16, qType dnsmessage.Type, domain string,
 
 	var ttl uint32 = 600
 
-	switch qType {
-	case dnsmessage.TypeA:
-		ips, err = h.client.LookupIP(domain, dns.IPOption{
-			IPv4Enable: true,
-			IPv6Enable: false,
-			FakeEnable: true,
-		})
-	case dnsmessage.TypeAAAA:
-		ips, err = h.client.LookupIP(domain, dns.IPOption{
-			IPv4Enable: false,
-			IPv6Enable: true,
-			FakeEnable: true,
-		})
+	option := dns.IPOption{
+		IPv4Enable: qType == dnsmessage.TypeA,
+		IPv6Enable: qType == dnsmessage.TypeAAAA,
+		FakeEnable: true,
+	}
+	// cname is the domain resolved instead, if rewritten by the DNS client
+	var cname dnsmessage.Name
+	if resolver, ok := h.client.(dns.Resolver); ok {
+		var resolution *dns.Resolution
+		resolution, err = resolver.Resolve(domain, option)
+		if resolution != nil {
+			ips = resolution.IPs
+			if target := strings.TrimSuffix(resolution.Domain, ".") + "."; resolution.Rewritten && !strings.EqualFold(target, domain) {
+				cname, _ = dnsmessage.NewName(target)
+			}
+		}
+	} else {
+		ips, err = h.client.LookupIP(domain, option)
 	}
 
 	rcode := dns.RCodeFromError(err)
@@ -294,6 +300,10 @@ func (h *Handler) handleIPQuery(id uint16, qType dnsmessage.Type, domain string,
 	common.Must(builder.StartAnswers())
 
 	rHeader := dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(domain), Class: dnsmessage.ClassINET, TTL: ttl}
+	if cname.Length > 0 {
+		common.Must(builder.CNAMEResource(rHeader, dnsmessage.CNAMEResource{CNAME: cname}))
+		rHeader.Name = cname
+	}
 	for _, ip := range ips {
 		if len(ip) == net.IPv4len {
 			var r dnsmessage.AResource
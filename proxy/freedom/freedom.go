@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"io"
 	"math/big"
+	"slices"
 	"time"
 
 	"github.com/pires/go-proxyproto"
@@ -27,6 +28,7 @@ import (
 	"github.com/xtls/xray-core/transport/internet"
 	"github.com/xtls/xray-core/transport/internet/stat"
 	"github.com/xtls/xray-core/transport/internet/tls"
+	"github.com/xtls/xray-core/transport/internet/udp"
 )
 
 var useSplice bool
@@ -70,10 +72,12 @@ func (h *Handler) policy() policy.Session {
 	return p
 }
 
-func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Address) net.Address {
+// lookupIP looks up the IPs of domain by the strategy, or of all the
+// families it allows if all is set.
+func (h *Handler) lookupIP(ctx context.Context, domain string, localAddr net.Address, all bool) []net.IP {
 	ips, err := h.dns.LookupIP(domain, dns.IPOption{
-		IPv4Enable: (localAddr == nil || localAddr.Family().IsIPv4()) && h.config.preferIP4(),
-		IPv6Enable: (localAddr == nil || localAddr.Family().IsIPv6()) && h.config.preferIP6(),
+		IPv4Enable: (localAddr == nil || localAddr.Family().IsIPv4()) && (h.config.preferIP4() || all && h.config.fallbackIP4()),
+		IPv6Enable: (localAddr == nil || localAddr.Family().IsIPv6()) && (h.config.preferIP6() || all && h.config.fallbackIP6()),
 	})
 	{ // Resolve fallback
 		if (len(ips) == 0 || err != nil) && h.config.hasFallback() && localAddr == nil {
@@ -86,6 +90,11 @@ func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Ad
 	if err != nil {
 		errors.LogInfoInner(ctx, err, "failed to get IP address for domain ", domain)
 	}
+	return ips
+}
+
+func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Address) net.Address {
+	ips := h.lookupIP(ctx, domain, localAddr, false)
 	if len(ips) == 0 {
 		return nil
 	}
@@ -101,6 +110,26 @@ func isValidAddress(addr *net.IPOrDomain) bool {
 	return a != net.AnyIP
 }
 
+// proxiedDialer is a Dialer which may forward connections through another
+// outbound handler.
+type proxiedDialer interface {
+	Proxied() bool
+}
+
+// useHappyEyeballs returns whether TCP connections to domains are dialed with
+// Happy Eyeballs. Attempts through another outbound handler are not, which
+// would open a proxied connection each and be closed with the context of the
+// attempt.
+func (h *Handler) useHappyEyeballs(dialer internet.Dialer) bool {
+	if h.config.HappyEyeballs == nil || !h.config.hasStrategy() {
+		return false
+	}
+	if d, ok := dialer.(proxiedDialer); ok && d.Proxied() {
+		return false
+	}
+	return true
+}
+
 // Process implements proxy.Outbound.
 func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer internet.Dialer) error {
 	outbounds := session.OutboundsFromContext(ctx)
@@ -132,7 +161,28 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 	var conn stat.Connection
 	err := retry.ExponentialBackoff(5, 100).On(func() error {
 		dialDest := destination
-		if h.config.hasStrategy() && dialDest.Address.Family().IsDomain() {
+		var rawConn stat.Connection
+		if h.useHappyEyeballs(dialer) && dialDest.Network == net.Network_TCP && dialDest.Address.Family().IsDomain() {
+			ips := h.lookupIP(ctx, dialDest.Address.Domain(), dialer.Address(), true)
+			if len(ips) > 0 {
+				conn, err := internet.DialHappyEyeballs(ctx, ips, dialDest, h.config.HappyEyeballs, func(ctx context.Context, dest net.Destination) (net.Conn, error) {
+					// racing attempts dial with their own copy of the outbound
+					// and submit no dial result, both of which are left to the
+					// winner
+					attempt := *ob
+					ctx = session.ContextWithOutbounds(ctx, append(slices.Clip(outbounds[:len(outbounds)-1]), &attempt))
+					return dialer.Dial(session.ContextWithDialFeedback(ctx, nil), dest)
+				})
+				session.SubmitDialResult(ctx, err)
+				if err != nil {
+					return err
+				}
+				ob.Conn = conn
+				rawConn = conn.(stat.Connection)
+			} else if h.config.forceIP() {
+				return dns.ErrEmptyResponse
+			}
+		} else if h.config.hasStrategy() && dialDest.Address.Family().IsDomain() {
 			ip := h.resolveIP(ctx, dialDest.Address.Domain(), dialer.Address())
 			if ip != nil {
 				dialDest = net.Destination{
@@ -146,9 +196,11 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 			}
 		}
 
-		rawConn, err := dialer.Dial(ctx, dialDest)
-		if err != nil {
-			return err
+		if rawConn == nil {
+			var err error
+			if rawConn, err = dialer.Dial(ctx, dialDest); err != nil {
+				return err
+			}
 		}
 
 		if h.config.ProxyProtocol > 0 && h.config.ProxyProtocol <= 2 {
@@ -156,7 +208,7 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 			srcAddr := inbound.Source.RawNetAddr()
 			dstAddr := rawConn.RemoteAddr()
 			header := proxyproto.HeaderProxyFromAddrs(version, srcAddr, dstAddr)
-			if _, err = header.WriteTo(rawConn); err != nil {
+			if _, err := header.WriteTo(rawConn); err != nil {
 				rawConn.Close()
 				return err
 			}
@@ -325,14 +377,17 @@ func NewPacketWriter(conn net.Conn, h *Handler, ctx context.Context, UDPOverride
 		counter = statConn.WriteCounter
 	}
 	if c, ok := iConn.(*internet.PacketConnWrapper); ok {
-		return &PacketWriter{
+		writer := &PacketWriter{
 			PacketConnWrapper: c,
 			Counter:           counter,
 			Handler:           h,
 			Context:           ctx,
 			UDPOverride:       UDPOverride,
 		}
-
+		if udpConn, ok := c.Conn.(*net.UDPConn); ok {
+			writer.batch = udp.NewBatchConn(udpConn, false)
+		}
+		return writer
 	}
 	return &buf.SequentialWriter{Writer: conn}
 }
@@ -343,9 +398,54 @@ type PacketWriter struct {
 	*Handler
 	context.Context
 	UDPOverride net.Destination
+
+	batch *udp.BatchConn
+}
+
+// destination returns the UDP address to write b to, or nil if it is unknown.
+func (w *PacketWriter) destination(b *buf.Buffer) *net.UDPAddr {
+	if b.UDP == nil {
+		destAddr, _ := w.PacketConnWrapper.Dest.(*net.UDPAddr)
+		return destAddr
+	}
+	if w.UDPOverride.Address != nil {
+		b.UDP.Address = w.UDPOverride.Address
+	}
+	if w.UDPOverride.Port != 0 {
+		b.UDP.Port = w.UDPOverride.Port
+	}
+	if w.Handler.config.hasStrategy() && b.UDP.Address.Family().IsDomain() {
+		ip := w.Handler.resolveIP(w.Context, b.UDP.Address.Domain(), nil)
+		if ip != nil {
+			b.UDP.Address = ip
+		}
+	}
+	destAddr, _ := net.ResolveUDPAddr("udp", b.UDP.NetAddr())
+	return destAddr
 }
 
 func (w *PacketWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if w.batch != nil {
+		defer buf.ReleaseMulti(mb)
+		payloads := make([][]byte, 0, len(mb))
+		addrs := make([]*net.UDPAddr, 0, len(mb))
+		var n int64
+		for _, b := range mb {
+			if destAddr := w.destination(b); destAddr != nil {
+				payloads = append(payloads, b.Bytes())
+				addrs = append(addrs, destAddr)
+				n += int64(b.Len())
+			}
+		}
+		if err := w.batch.WriteBatch(payloads, addrs); err != nil {
+			return err
+		}
+		if w.Counter != nil {
+			w.Counter.Add(n)
+		}
+		return nil
+	}
+
 	for {
 		mb2, b := buf.SplitFirst(mb)
 		mb = mb2
@@ -355,19 +455,7 @@ func (w *PacketWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
 		var n int
 		var err error
 		if b.UDP != nil {
-			if w.UDPOverride.Address != nil {
-				b.UDP.Address = w.UDPOverride.Address
-			}
-			if w.UDPOverride.Port != 0 {
-				b.UDP.Port = w.UDPOverride.Port
-			}
-			if w.Handler.config.hasStrategy() && b.UDP.Address.Family().IsDomain() {
-				ip := w.Handler.resolveIP(w.Context, b.UDP.Address.Domain(), nil)
-				if ip != nil {
-					b.UDP.Address = ip
-				}
-			}
-			destAddr, _ := net.ResolveUDPAddr("udp", b.UDP.NetAddr())
+			destAddr := w.destination(b)
 			if destAddr == nil {
 				b.Release()
 				continue
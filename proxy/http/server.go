@@ -92,8 +92,11 @@ func (s *Server) ProcessWithFirstbyte(ctx context.Context, network net.Network,
 	inbound := session.InboundFromContext(ctx)
 	inbound.Name = "http"
 	inbound.CanSpliceCopy = 2
-	inbound.User = &protocol.MemoryUser{
-		Level: s.config.UserLevel,
+	if inbound.User == nil {
+		// the user may be mapped from the TLS client certificate
+		inbound.User = &protocol.MemoryUser{
+			Level: s.config.UserLevel,
+		}
 	}
 	var reader *bufio.Reader
 	if len(firstbyte) > 0 {
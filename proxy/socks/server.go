@@ -71,8 +71,11 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn stat.Con
 	inbound := session.InboundFromContext(ctx)
 	inbound.Name = "socks"
 	inbound.CanSpliceCopy = 2
-	inbound.User = &protocol.MemoryUser{
-		Level: s.config.UserLevel,
+	if inbound.User == nil {
+		// the user may be mapped from the TLS client certificate
+		inbound.User = &protocol.MemoryUser{
+			Level: s.config.UserLevel,
+		}
 	}
 
 	switch network {
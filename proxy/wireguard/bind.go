@@ -13,7 +13,10 @@ import (
 
 	xnet "github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/features/stats"
 	"github.com/xtls/xray-core/transport/internet"
+	"github.com/xtls/xray-core/transport/internet/stat"
+	"github.com/xtls/xray-core/transport/internet/udp"
 )
 
 type netReadInfo struct {
@@ -134,6 +137,7 @@ func (bind *netBindClient) connectTo(endpoint *netEndpoint) error {
 		return err
 	}
 	endpoint.conn = c
+	endpoint.batch = newBatchWriter(c)
 
 	go func(readQueue <-chan *netReadInfo, endpoint *netEndpoint) {
 		for {
@@ -182,6 +186,11 @@ func (bind *netBindClient) Send(buff [][]byte, endpoint conn.Endpoint) error {
 		if len(buff) > 3 && len(bind.reserved) == 3 {
 			copy(buff[1:], bind.reserved)
 		}
+	}
+	if nend.batch != nil {
+		return nend.batch.write(buff)
+	}
+	for _, buff := range buff {
 		if _, err = nend.conn.Write(buff); err != nil {
 			return err
 		}
@@ -215,8 +224,59 @@ func (bind *netBindServer) Send(buff [][]byte, endpoint conn.Endpoint) error {
 }
 
 type netEndpoint struct {
-	dst  xnet.Destination
-	conn net.Conn
+	dst   xnet.Destination
+	conn  net.Conn
+	batch *batchWriter
+}
+
+// batchWriter writes packets to a UDP socket in batches.
+type batchWriter struct {
+	conn    *udp.BatchConn
+	addr    *net.UDPAddr
+	counter stats.Counter
+}
+
+// newBatchWriter returns the batchWriter of conn if it is a UDP socket, or
+// nil otherwise.
+func newBatchWriter(conn net.Conn) *batchWriter {
+	var counter stats.Counter
+	if statConn, ok := conn.(*stat.CounterConnection); ok {
+		conn = statConn.Connection
+		counter = statConn.WriteCounter
+	}
+	wrapper, ok := conn.(*internet.PacketConnWrapper)
+	if !ok {
+		return nil
+	}
+	udpConn, ok := wrapper.Conn.(*net.UDPConn)
+	if !ok {
+		return nil
+	}
+	addr, ok := wrapper.Dest.(*net.UDPAddr)
+	if !ok {
+		return nil
+	}
+	return &batchWriter{
+		conn:    udp.NewBatchConn(udpConn, false),
+		addr:    addr,
+		counter: counter,
+	}
+}
+
+func (w *batchWriter) write(buffs [][]byte) error {
+	addrs := make([]*net.UDPAddr, len(buffs))
+	var n int64
+	for i, buff := range buffs {
+		addrs[i] = w.addr
+		n += int64(len(buff))
+	}
+	if err := w.conn.WriteBatch(buffs, addrs); err != nil {
+		return err
+	}
+	if w.counter != nil {
+		w.counter.Add(n)
+	}
+	return nil
 }
 
 func (netEndpoint) ClearSrc() {}
@@ -58,6 +58,11 @@ func Dial(ctx context.Context, dest net.Destination, streamSettings *MemoryStrea
 		if dialer == nil {
 			return nil, errors.New(protocol, " dialer not registered").AtError()
 		}
+		if streamSettings.Prewarm.GetSize() > 0 && streamSettings.PrewarmPools != nil {
+			if pool := streamSettings.PrewarmPools.get(dest, streamSettings, dialer); pool != nil {
+				return pool.Dial(ctx)
+			}
+		}
 		return dialer(ctx, dest, streamSettings)
 	}
 
@@ -103,6 +108,19 @@ func lookupIP(domain string, strategy DomainStrategy, localAddr net.Address) ([]
 	return ips, err
 }
 
+// lookupAllIP looks up the IPs of all the families allowed by strategy, for
+// Happy Eyeballs to race.
+func lookupAllIP(domain string, strategy DomainStrategy, localAddr net.Address) ([]net.IP, error) {
+	if dnsClient == nil {
+		return nil, nil
+	}
+
+	return dnsClient.LookupIP(domain, dns.IPOption{
+		IPv4Enable: (localAddr == nil || localAddr.Family().IsIPv4()) && (strategy.preferIP4() || strategy.fallbackIP4()),
+		IPv6Enable: (localAddr == nil || localAddr.Family().IsIPv6()) && (strategy.preferIP6() || strategy.fallbackIP6()),
+	})
+}
+
 func canLookupIP(ctx context.Context, dst net.Destination, sockopt *SocketConfig) bool {
 	if dst.Address.Family().IsIP() || dnsClient == nil {
 		return false
@@ -142,6 +160,14 @@ func redirect(ctx context.Context, dst net.Destination, obt string) net.Conn {
 
 // DialSystem calls system dialer to create a network connection.
 func DialSystem(ctx context.Context, dest net.Destination, sockopt *SocketConfig) (net.Conn, error) {
+	conn, err := dialSystem(ctx, dest, sockopt)
+	if err != nil || sockopt.GetImpairment() == nil {
+		return conn, err
+	}
+	return impair(ctx, conn, dest, sockopt.Impairment), nil
+}
+
+func dialSystem(ctx context.Context, dest net.Destination, sockopt *SocketConfig) (net.Conn, error) {
 	var src net.Address
 	outbounds := session.OutboundsFromContext(ctx)
 	if len(outbounds) > 0 {
@@ -153,7 +179,19 @@ func DialSystem(ctx context.Context, dest net.Destination, sockopt *SocketConfig
 	}
 
 	if canLookupIP(ctx, dest, sockopt) {
-		ips, err := lookupIP(dest.Address.String(), sockopt.DomainStrategy, src)
+		happyEyeballs := sockopt.HappyEyeballs != nil && dest.Network == net.Network_TCP && len(sockopt.DialerProxy) == 0
+		var ips []net.IP
+		var err error
+		if happyEyeballs {
+			ips, err = lookupAllIP(dest.Address.String(), sockopt.DomainStrategy, src)
+		} else {
+			ips, err = lookupIP(dest.Address.String(), sockopt.DomainStrategy, src)
+		}
+		if err == nil && len(ips) > 0 && happyEyeballs {
+			return DialHappyEyeballs(ctx, ips, dest, sockopt.HappyEyeballs, func(ctx context.Context, dest net.Destination) (net.Conn, error) {
+				return effectiveSystemDialer.Dial(ctx, src, dest, sockopt)
+			})
+		}
 		if err == nil && len(ips) > 0 {
 			dest.Address = net.IPAddress(ips[dice.Roll(len(ips))])
 			errors.LogInfo(ctx, "replace destination with "+dest.String())
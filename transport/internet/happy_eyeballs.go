@@ -0,0 +1,127 @@
+package internet
+
+import (
+	"context"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+)
+
+const (
+	defaultHappyEyeballsTryDelay      = 250 * time.Millisecond
+	defaultHappyEyeballsMaxConcurrent = 4
+)
+
+// sortHappyEyeballsIPs orders ips to be tried as RFC 8305, in which the
+// addresses of the families are interleaved after the first interleave ones
+// of the prioritized family.
+func sortHappyEyeballsIPs(ips []net.IP, prioritizeIPv6 bool, interleave int) []net.IP {
+	var ip4, ip6 []net.IP
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			ip4 = append(ip4, ip)
+		} else {
+			ip6 = append(ip6, ip)
+		}
+	}
+	first, second := ip4, ip6
+	if prioritizeIPv6 {
+		first, second = ip6, ip4
+	}
+	interleave = max(interleave, 1)
+
+	sorted := make([]net.IP, 0, len(ips))
+	for len(first) > 0 || len(second) > 0 {
+		n := min(interleave, len(first))
+		sorted = append(sorted, first[:n]...)
+		first = first[n:]
+		if len(second) > 0 {
+			sorted = append(sorted, second[0])
+			second = second[1:]
+		}
+		interleave = 1
+	}
+	return sorted
+}
+
+type happyEyeballsResult struct {
+	conn net.Conn
+	err  error
+}
+
+// DialHappyEyeballs dials dest at each of ips by dial, starting the next
+// attempt when the last one fails or is not done in the try delay, and
+// returns the first connection established while the others are canceled.
+func DialHappyEyeballs(ctx context.Context, ips []net.IP, dest net.Destination, config *HappyEyeballsConfig, dial func(context.Context, net.Destination) (net.Conn, error)) (net.Conn, error) {
+	if len(ips) == 0 {
+		return nil, errors.New("no IP to dial")
+	}
+	ips = sortHappyEyeballsIPs(ips, config.PrioritizeIpv6, int(config.Interleave))
+	tryDelay := time.Duration(config.TryDelayMs) * time.Millisecond
+	if tryDelay <= 0 {
+		tryDelay = defaultHappyEyeballsTryDelay
+	}
+	maxConcurrent := int(config.MaxConcurrentTry)
+	if maxConcurrent <= 0 {
+		maxConcurrent = defaultHappyEyeballsMaxConcurrent
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan happyEyeballsResult, len(ips))
+	next, running := 0, 0
+	start := func() {
+		d := dest
+		d.Address = net.IPAddress(ips[next])
+		next++
+		running++
+		errors.LogDebug(ctx, "happy eyeballs dialing to ", d)
+		go func() {
+			conn, err := dial(ctx, d)
+			results <- happyEyeballsResult{conn: conn, err: err}
+		}()
+	}
+	// closeLosers closes the connections established by the other attempts.
+	closeLosers := func(n int) {
+		go func() {
+			for i := 0; i < n; i++ {
+				if result := <-results; result.conn != nil {
+					result.conn.Close()
+				}
+			}
+		}()
+	}
+
+	start()
+	timer := time.NewTimer(tryDelay)
+	defer timer.Stop()
+	var errs []error
+	for {
+		select {
+		case result := <-results:
+			running--
+			if result.err == nil {
+				closeLosers(running)
+				return result.conn, nil
+			}
+			errs = append(errs, result.err)
+			if next < len(ips) {
+				start()
+				timer.Reset(tryDelay)
+			} else if running == 0 {
+				return nil, errors.New("failed to dial ", dest, " at any IP").Base(errors.Combine(errs...))
+			}
+		case <-timer.C:
+			if next < len(ips) && running < maxConcurrent {
+				start()
+			}
+			if next < len(ips) {
+				timer.Reset(tryDelay)
+			}
+		case <-ctx.Done():
+			closeLosers(running)
+			return nil, ctx.Err()
+		}
+	}
+}
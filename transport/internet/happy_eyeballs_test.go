@@ -0,0 +1,78 @@
+package internet_test
+
+import (
+	"context"
+	gonet "net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	. "github.com/xtls/xray-core/transport/internet"
+)
+
+func TestDialHappyEyeballs(t *testing.T) {
+	ips := []net.IP{
+		net.ParseIP("2001:db8::1"),
+		net.ParseIP("2001:db8::2"),
+		net.ParseIP("192.0.2.1"),
+		net.ParseIP("192.0.2.2"),
+	}
+	dest := net.TCPDestination(net.DomainAddress("example.com"), 443)
+
+	var access sync.Mutex
+	var dialed []string
+	canceled := make(chan string, len(ips))
+	// IPv6 is broken and IPv4 works
+	dial := func(ctx context.Context, dest net.Destination) (net.Conn, error) {
+		access.Lock()
+		dialed = append(dialed, dest.Address.IP().String())
+		access.Unlock()
+		if dest.Address.Family().IsIPv6() {
+			<-ctx.Done()
+			canceled <- dest.Address.IP().String()
+			return nil, ctx.Err()
+		}
+		conn, _ := gonet.Pipe()
+		return conn, nil
+	}
+
+	start := time.Now()
+	conn, err := DialHappyEyeballs(context.Background(), ips, dest, &HappyEyeballsConfig{PrioritizeIpv6: true, TryDelayMs: 50}, dial)
+	common.Must(err)
+	conn.Close()
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Error("unexpected time to connect: ", elapsed)
+	}
+	access.Lock()
+	if r := cmp.Diff(dialed, []string{"2001:db8::1", "192.0.2.1"}); r != "" {
+		t.Error(r)
+	}
+	access.Unlock()
+	if address := <-canceled; address != "2001:db8::1" {
+		t.Error("expected IPv6 attempt canceled, actual ", address)
+	}
+
+	// attempts failing fast are followed without waiting the try delay
+	fail := func(ctx context.Context, dest net.Destination) (net.Conn, error) {
+		if dest.Address.IP().String() != "192.0.2.2" {
+			return nil, errors.New("connection refused")
+		}
+		conn, _ := gonet.Pipe()
+		return conn, nil
+	}
+	start = time.Now()
+	conn, err = DialHappyEyeballs(context.Background(), ips, dest, &HappyEyeballsConfig{TryDelayMs: 1000}, fail)
+	common.Must(err)
+	conn.Close()
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Error("unexpected time to connect: ", elapsed)
+	}
+
+	if _, err := DialHappyEyeballs(context.Background(), ips[:2], dest, &HappyEyeballsConfig{}, fail); err == nil {
+		t.Error("expected error when all attempts fail")
+	}
+}
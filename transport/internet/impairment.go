@@ -0,0 +1,309 @@
+package internet
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/signal/done"
+)
+
+const (
+	// impairmentQueueSize is the maximum number of writes to a stream
+	// connection waiting to be delivered, beyond which writes are blocked.
+	impairmentQueueSize = 64
+	// impairmentMaxBacklog is the maximum time of UDP packets waiting for the
+	// bandwidth, beyond which packets are dropped.
+	impairmentMaxBacklog = time.Second
+	// impairmentReorderDelay is the delay of reordered UDP packets if there
+	// is neither latency nor jitter.
+	impairmentReorderDelay = 10 * time.Millisecond
+	// impairmentCloseTimeout is the maximum time to deliver the remaining
+	// writes to a stream connection after the last one is due on close.
+	impairmentCloseTimeout = time.Second
+)
+
+var errImpairmentReset = errors.New("connection reset by impairment")
+
+// impairment is the state of the impairment of a connection.
+type impairment struct {
+	sync.Mutex
+	config *ImpairmentConfig
+	rand   *rand.Rand
+	// nextWrite and nextRead are the times when the bandwidth is available
+	// for the next write and read.
+	nextWrite time.Time
+	nextRead  time.Time
+}
+
+func newImpairment(config *ImpairmentConfig) *impairment {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &impairment{
+		config: config,
+		rand:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+// roll returns whether an event of the percentage happens.
+func (m *impairment) roll(percentage float32) bool {
+	if percentage <= 0 {
+		return false
+	}
+	m.Lock()
+	defer m.Unlock()
+	return m.rand.Float32()*100 < percentage
+}
+
+// latency returns the latency of a write with jitter.
+func (m *impairment) latency() time.Duration {
+	latency := time.Duration(m.config.LatencyMs) * time.Millisecond
+	if m.config.JitterMs > 0 {
+		m.Lock()
+		latency += time.Duration(m.rand.Int63n(int64(m.config.JitterMs) * int64(time.Millisecond)))
+		m.Unlock()
+	}
+	return latency
+}
+
+// transmit takes the bandwidth for n bytes from next, and returns the time
+// when they are transmitted, or false without taking it if the time is later
+// than backlog if not zero.
+func (m *impairment) transmit(next *time.Time, n int, backlog time.Duration) (time.Time, bool) {
+	now := time.Now()
+	if m.config.Bandwidth == 0 {
+		return now, true
+	}
+	m.Lock()
+	defer m.Unlock()
+	at := *next
+	if at.Before(now) {
+		at = now
+	}
+	at = at.Add(time.Duration(float64(n) / float64(m.config.Bandwidth) * float64(time.Second)))
+	if backlog > 0 && at.Sub(now) > backlog {
+		return at, false
+	}
+	*next = at
+	return at, true
+}
+
+// waitRead waits for the bandwidth to read n bytes.
+func (m *impairment) waitRead(n int) {
+	if n > 0 {
+		at, _ := m.transmit(&m.nextRead, n, 0)
+		time.Sleep(time.Until(at))
+	}
+}
+
+// impair wraps conn dialed to dest by the impairment of config.
+func impair(ctx context.Context, conn net.Conn, dest net.Destination, config *ImpairmentConfig) net.Conn {
+	if wrapper, ok := conn.(*PacketConnWrapper); ok {
+		return &PacketConnWrapper{
+			Conn: &impairedPacketConn{
+				PacketConn: wrapper.Conn,
+				impairment: newImpairment(config),
+			},
+			Dest: wrapper.Dest,
+		}
+	}
+	c := &impairedConn{
+		Conn:       conn,
+		impairment: newImpairment(config),
+		ctx:        ctx,
+		dest:       dest,
+		queue:      make(chan *impairedWrite, impairmentQueueSize),
+		done:       done.New(),
+	}
+	go c.deliver()
+	return c
+}
+
+type impairedWrite struct {
+	b  []byte
+	at time.Time
+}
+
+// impairedConn delays the writes of a stream connection in order, limits its
+// bandwidth, and resets it randomly.
+type impairedConn struct {
+	net.Conn
+	*impairment
+	ctx  context.Context
+	dest net.Destination
+
+	queue chan *impairedWrite
+	// last is the delivery time of the last write, before which the next
+	// write is not delivered.
+	last time.Time
+	err  error
+	// done is closed when the connection is closed, after which the
+	// underlying connection is closed once the remaining writes are delivered.
+	done *done.Instance
+}
+
+func (c *impairedConn) deliver() {
+	defer c.Conn.Close()
+
+	write := func(w *impairedWrite) bool {
+		time.Sleep(time.Until(w.at))
+		if _, err := c.Conn.Write(w.b); err != nil {
+			c.Lock()
+			c.err = err
+			c.Unlock()
+			return false
+		}
+		return true
+	}
+	for {
+		select {
+		case w := <-c.queue:
+			if !write(w) {
+				return
+			}
+		case <-c.done.Wait():
+			for {
+				select {
+				case w := <-c.queue:
+					if !write(w) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// reset closes the connection immediately, by RST if TCP.
+func (c *impairedConn) reset() {
+	errors.LogInfo(c.ctx, "impairment resets connection to ", c.dest)
+	if tcpConn, ok := c.Conn.(*net.TCPConn); ok {
+		tcpConn.SetLinger(0)
+	}
+	c.Lock()
+	c.err = errImpairmentReset
+	c.Unlock()
+	c.done.Close()
+	c.Conn.Close()
+}
+
+// Read implements net.Conn.
+func (c *impairedConn) Read(b []byte) (int, error) {
+	if c.roll(c.config.ResetRate) {
+		c.reset()
+		return 0, errImpairmentReset
+	}
+	n, err := c.Conn.Read(b)
+	c.waitRead(n)
+	return n, err
+}
+
+// Write implements net.Conn, and returns before b is delivered.
+func (c *impairedConn) Write(b []byte) (int, error) {
+	if c.roll(c.config.ResetRate) {
+		c.reset()
+		return 0, errImpairmentReset
+	}
+	c.Lock()
+	err := c.err
+	c.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	at := time.Now().Add(c.latency())
+	if transmitted, _ := c.transmit(&c.nextWrite, len(b), 0); transmitted.After(at) {
+		at = transmitted
+	}
+	c.Lock()
+	if at.Before(c.last) {
+		at = c.last
+	}
+	c.last = at
+	c.Unlock()
+
+	w := &impairedWrite{
+		b:  append([]byte(nil), b...),
+		at: at,
+	}
+	select {
+	case c.queue <- w:
+		return len(b), nil
+	case <-c.done.Wait():
+		return 0, io.ErrClosedPipe
+	}
+}
+
+// Close implements net.Conn. It returns immediately, and the underlying
+// connection is closed in the background after the remaining writes are
+// delivered, or the write deadline set here is exceeded.
+func (c *impairedConn) Close() error {
+	c.Lock()
+	last := c.last
+	c.Unlock()
+	c.Conn.SetWriteDeadline(last.Add(impairmentCloseTimeout))
+	c.done.Close()
+	return nil
+}
+
+// impairedPacketConn delays, drops and reorders the packets written to a UDP
+// connection, drops the ones read from it, and limits its bandwidth.
+type impairedPacketConn struct {
+	net.PacketConn
+	*impairment
+}
+
+// ReadFrom implements net.PacketConn.
+func (c *impairedPacketConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	for {
+		n, addr, err := c.PacketConn.ReadFrom(b)
+		if err != nil {
+			return n, addr, err
+		}
+		if c.roll(c.config.Loss) {
+			continue
+		}
+		c.waitRead(n)
+		return n, addr, nil
+	}
+}
+
+// WriteTo implements net.PacketConn, and returns before b is delivered.
+func (c *impairedPacketConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	if c.roll(c.config.Loss) {
+		return len(b), nil
+	}
+	now := time.Now()
+	delay := c.latency()
+	transmitted, ok := c.transmit(&c.nextWrite, len(b), impairmentMaxBacklog)
+	if !ok {
+		return len(b), nil
+	}
+	if transmitted.Sub(now) > delay {
+		delay = transmitted.Sub(now)
+	}
+	if c.roll(c.config.Reorder) {
+		extra := c.latency()
+		if extra == 0 {
+			extra = impairmentReorderDelay
+		}
+		delay += extra
+	}
+	if delay <= 0 {
+		return c.PacketConn.WriteTo(b, addr)
+	}
+
+	payload := append([]byte(nil), b...)
+	time.AfterFunc(delay, func() {
+		c.PacketConn.WriteTo(payload, addr)
+	})
+	return len(b), nil
+}
@@ -0,0 +1,156 @@
+package internet_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/testing/servers/tcp"
+	. "github.com/xtls/xray-core/transport/internet"
+)
+
+func echo(msg []byte) []byte {
+	return msg
+}
+
+func TestImpairedConn(t *testing.T) {
+	server := tcp.Server{MsgProcessor: echo}
+	dest, err := server.Start()
+	common.Must(err)
+	defer server.Close()
+
+	conn, err := DialSystem(context.Background(), dest, &SocketConfig{
+		Impairment: &ImpairmentConfig{
+			LatencyMs: 100,
+			JitterMs:  50,
+			Seed:      1,
+		},
+	})
+	common.Must(err)
+	defer conn.Close()
+
+	start := time.Now()
+	var payload []byte
+	for i := 0; i < 16; i++ {
+		b := bytes.Repeat([]byte{byte(i)}, 64)
+		payload = append(payload, b...)
+		common.Must2(conn.Write(b))
+	}
+	response := make([]byte, len(payload))
+	common.Must2(io.ReadFull(conn, response))
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Error("unexpected latency ", elapsed)
+	}
+	if !bytes.Equal(response, payload) {
+		t.Error("unexpected response ", response)
+	}
+}
+
+func TestImpairedConnReset(t *testing.T) {
+	server := tcp.Server{MsgProcessor: echo}
+	dest, err := server.Start()
+	common.Must(err)
+	defer server.Close()
+
+	conn, err := DialSystem(context.Background(), dest, &SocketConfig{
+		Impairment: &ImpairmentConfig{
+			ResetRate: 100,
+		},
+	})
+	common.Must(err)
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("test")); err == nil {
+		t.Error("expected reset")
+	}
+	if _, err := conn.Read(make([]byte, 4)); err == nil {
+		t.Error("expected error after reset")
+	}
+}
+
+func TestImpairedConnClose(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		b, _ := io.ReadAll(conn)
+		received <- b
+	}()
+
+	conn, err := DialSystem(context.Background(), net.DestinationFromAddr(listener.Addr()), &SocketConfig{
+		Impairment: &ImpairmentConfig{
+			LatencyMs: 200,
+		},
+	})
+	common.Must(err)
+	common.Must2(conn.Write([]byte("test")))
+
+	start := time.Now()
+	common.Must(conn.Close())
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Error("close waited for queued writes: ", elapsed)
+	}
+	select {
+	case b := <-received:
+		if string(b) != "test" {
+			t.Error("unexpected payload ", b)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("connection not closed after queued writes")
+	}
+}
+
+func TestImpairedPacketConn(t *testing.T) {
+	server, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.LocalHostIP.IP()})
+	common.Must(err)
+	defer server.Close()
+	go func() {
+		b := make([]byte, 16)
+		for {
+			n, addr, err := server.ReadFromUDP(b)
+			if err != nil {
+				return
+			}
+			server.WriteToUDP(b[:n], addr)
+		}
+	}()
+	dest := net.DestinationFromAddr(server.LocalAddr())
+
+	dial := func(config *ImpairmentConfig) net.Conn {
+		conn, err := DialSystem(context.Background(), dest, &SocketConfig{Impairment: config})
+		common.Must(err)
+		return conn
+	}
+
+	conn := dial(&ImpairmentConfig{LatencyMs: 50})
+	defer conn.Close()
+	start := time.Now()
+	common.Must2(conn.Write([]byte("test")))
+	b := make([]byte, 16)
+	n, err := conn.Read(b)
+	common.Must(err)
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Error("unexpected latency ", elapsed)
+	}
+	if string(b[:n]) != "test" {
+		t.Error("unexpected response ", string(b[:n]))
+	}
+
+	lossyConn := dial(&ImpairmentConfig{Loss: 100})
+	defer lossyConn.Close()
+	common.Must2(lossyConn.Write([]byte("test")))
+	lossyConn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	if _, err := lossyConn.Read(b); err == nil {
+		t.Error("expected packet loss")
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/signal"
 	"github.com/xtls/xray-core/common/signal/semaphore"
+	"github.com/xtls/xray-core/features/stats"
 )
 
 var (
@@ -173,6 +174,8 @@ type ConnMetadata struct {
 	LocalAddr    net.Addr
 	RemoteAddr   net.Addr
 	Conversation uint16
+	// Retransmitted counts the data segments sent more than once, if not nil.
+	Retransmitted stats.Counter
 }
 
 // Connection is a KCP connection over UDP.
@@ -2,6 +2,8 @@ package kcp
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/binary"
 	"io"
 	"sync/atomic"
 
@@ -49,13 +51,19 @@ func DialKCP(ctx context.Context, dest net.Destination, streamSettings *internet
 	dest.Network = net.Network_UDP
 	errors.LogInfo(ctx, "dialing mKCP to ", dest)
 
-	rawConn, err := internet.DialSystem(ctx, dest, streamSettings.SocketSettings)
+	kcpSettings := streamSettings.ProtocolSettings.(*Config)
+
+	var rawConn net.Conn
+	var err error
+	if kcpSettings.PortHopping != nil {
+		rawConn, err = internet.DialPortHopping(ctx, dest, streamSettings.SocketSettings, kcpSettings.PortHopping)
+	} else {
+		rawConn, err = internet.DialSystem(ctx, dest, streamSettings.SocketSettings)
+	}
 	if err != nil {
 		return nil, errors.New("failed to dial to dest: ", err).AtWarning().Base(err)
 	}
 
-	kcpSettings := streamSettings.ProtocolSettings.(*Config)
-
 	header, err := kcpSettings.GetPackerHeader()
 	if err != nil {
 		return nil, errors.New("failed to create packet header").Base(err)
@@ -64,21 +72,41 @@ func DialKCP(ctx context.Context, dest net.Destination, streamSettings *internet
 	if err != nil {
 		return nil, errors.New("failed to create security").Base(err)
 	}
+	recovered, retransmitted := getSegmentCounters(ctx)
 	reader := &KCPPacketReader{
 		Header:   header,
 		Security: security,
 	}
-	writer := &KCPPacketWriter{
+	var writer PacketWriter = &KCPPacketWriter{
 		Header:   header,
 		Security: security,
 		Writer:   rawConn,
 	}
+	if kcpSettings.PortHopping != nil {
+		var session [sessionIDSize]byte
+		common.Must2(rand.Read(session[:]))
+		writer = &SessionPacketWriter{
+			Writer:  writer,
+			Session: binary.BigEndian.Uint64(session[:]),
+		}
+	}
+	if kcpSettings.Fec != nil {
+		if reader.FEC, err = NewFECDecoder(kcpSettings.Fec, recovered); err != nil {
+			rawConn.Close()
+			return nil, errors.New("failed to create FEC decoder").Base(err)
+		}
+		if writer, err = NewFECPacketWriter(writer, kcpSettings.Fec); err != nil {
+			rawConn.Close()
+			return nil, errors.New("failed to create FEC writer").Base(err)
+		}
+	}
 
 	conv := uint16(atomic.AddUint32(&globalConv, 1))
 	session := NewConnection(ConnMetadata{
-		LocalAddr:    rawConn.LocalAddr(),
-		RemoteAddr:   rawConn.RemoteAddr(),
-		Conversation: conv,
+		LocalAddr:     rawConn.LocalAddr(),
+		RemoteAddr:    rawConn.RemoteAddr(),
+		Conversation:  conv,
+		Retransmitted: retransmitted,
 	}, writer, rawConn, kcpSettings)
 
 	go fetchInput(ctx, rawConn, reader, session)
@@ -86,7 +114,7 @@ func DialKCP(ctx context.Context, dest net.Destination, streamSettings *internet
 	var iConn stat.Connection = session
 
 	if config := tls.ConfigFromStreamSettings(streamSettings); config != nil {
-		iConn = tls.Client(iConn, config.GetTLSConfig(tls.WithDestination(dest)))
+		iConn = tls.Client(iConn, config.GetTLSConfig(tls.WithContext(ctx), tls.WithDestination(dest)))
 	}
 
 	return iConn, nil
@@ -0,0 +1,434 @@
+package kcp
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/stats"
+)
+
+const (
+	// fecHeaderSize is the size of the sequence number of each FEC packet.
+	fecHeaderSize = 4
+	// fecDataSizeSize is the size of the length prefix of each data shard.
+	fecDataSizeSize = 2
+	// fecGroupWindow is the number of the latest groups kept for recovery.
+	fecGroupWindow = 8
+
+	defaultFECDataShards   = 10
+	defaultFECParityShards = 3
+)
+
+// GetDataShardsValue returns the number of data packets in each FEC group.
+func (c *FEC) GetDataShardsValue() int {
+	if c.GetDataShards() == 0 {
+		return defaultFECDataShards
+	}
+	return int(c.GetDataShards())
+}
+
+// GetParityShardsValue returns the number of parity packets in each FEC group.
+func (c *FEC) GetParityShardsValue() int {
+	if c.GetParityShards() == 0 {
+		return defaultFECParityShards
+	}
+	return int(c.GetParityShards())
+}
+
+var gfExp, gfLog = func() ([512]byte, [256]int) {
+	var exp [512]byte
+	var log [256]int
+	x := 1
+	for i := 0; i < 255; i++ {
+		exp[i] = byte(x)
+		log[x] = i
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	for i := 255; i < 512; i++ {
+		exp[i] = exp[i-255]
+	}
+	return exp, log
+}()
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[gfLog[a]+gfLog[b]]
+}
+
+func gfInv(a byte) byte {
+	return gfExp[255-gfLog[a]]
+}
+
+// gfMulAdd adds c * src to dst.
+func gfMulAdd(dst, src []byte, c byte) {
+	if c == 0 {
+		return
+	}
+	lc := gfLog[c]
+	for i, s := range src {
+		if s != 0 {
+			dst[i] ^= gfExp[gfLog[s]+lc]
+		}
+	}
+}
+
+// reedSolomon is a systematic Reed-Solomon code over GF(2^8), of which the
+// parity rows form a Cauchy matrix so that any dataShards of the shards are
+// enough to reconstruct the others.
+type reedSolomon struct {
+	dataShards   int
+	parityShards int
+	parity       [][]byte
+}
+
+func newReedSolomon(dataShards, parityShards int) (*reedSolomon, error) {
+	if dataShards <= 0 || parityShards <= 0 || dataShards+parityShards > 256 {
+		return nil, errors.New("invalid FEC shards: ", dataShards, "+", parityShards)
+	}
+	rs := &reedSolomon{
+		dataShards:   dataShards,
+		parityShards: parityShards,
+		parity:       make([][]byte, parityShards),
+	}
+	for i := range rs.parity {
+		rs.parity[i] = make([]byte, dataShards)
+		for j := range rs.parity[i] {
+			rs.parity[i][j] = gfInv(byte(dataShards+i) ^ byte(j))
+		}
+	}
+	return rs, nil
+}
+
+// row returns the coefficients of the shard at index over the data shards.
+func (rs *reedSolomon) row(index int) []byte {
+	if index >= rs.dataShards {
+		return rs.parity[index-rs.dataShards]
+	}
+	row := make([]byte, rs.dataShards)
+	row[index] = 1
+	return row
+}
+
+// encode fills the parity shards with the data shards, all of the same size.
+func (rs *reedSolomon) encode(shards [][]byte) {
+	for i, coefficients := range rs.parity {
+		parity := shards[rs.dataShards+i]
+		clear(parity)
+		for j, c := range coefficients {
+			gfMulAdd(parity, shards[j], c)
+		}
+	}
+}
+
+// reconstruct fills the nil data shards with dataShards of the others, all
+// of the same size.
+func (rs *reedSolomon) reconstruct(shards [][]byte) error {
+	present := make([]int, 0, rs.dataShards)
+	for i, shard := range shards {
+		if shard != nil && len(present) < rs.dataShards {
+			present = append(present, i)
+		}
+	}
+	if len(present) < rs.dataShards {
+		return errors.New("not enough FEC shards")
+	}
+
+	// Invert the matrix of the present shards by Gauss-Jordan elimination.
+	n := rs.dataShards
+	matrix := make([][]byte, n)
+	inverse := make([][]byte, n)
+	for i, index := range present {
+		matrix[i] = append([]byte(nil), rs.row(index)...)
+		inverse[i] = make([]byte, n)
+		inverse[i][i] = 1
+	}
+	for col := 0; col < n; col++ {
+		pivot := col
+		for pivot < n && matrix[pivot][col] == 0 {
+			pivot++
+		}
+		if pivot == n {
+			return errors.New("singular FEC matrix")
+		}
+		matrix[col], matrix[pivot] = matrix[pivot], matrix[col]
+		inverse[col], inverse[pivot] = inverse[pivot], inverse[col]
+		if c := gfInv(matrix[col][col]); c != 1 {
+			for j := 0; j < n; j++ {
+				matrix[col][j] = gfMul(matrix[col][j], c)
+				inverse[col][j] = gfMul(inverse[col][j], c)
+			}
+		}
+		for row := 0; row < n; row++ {
+			if c := matrix[row][col]; row != col && c != 0 {
+				gfMulAdd(matrix[row], matrix[col], c)
+				gfMulAdd(inverse[row], inverse[col], c)
+			}
+		}
+	}
+
+	size := len(shards[present[0]])
+	for i := 0; i < n; i++ {
+		if shards[i] != nil {
+			continue
+		}
+		shard := make([]byte, size)
+		for j, index := range present {
+			gfMulAdd(shard, shards[index], inverse[i][j])
+		}
+		shards[i] = shard
+	}
+	return nil
+}
+
+// resize returns b of size n, padded with zeros.
+func resize(b []byte, n int) []byte {
+	size := len(b)
+	if cap(b) < n {
+		b = append(b, make([]byte, n-size)...)
+	}
+	b = b[:n]
+	clear(b[min(size, n):])
+	return b
+}
+
+// FECPacketWriter writes each packet to Writer as a data shard, followed by
+// the parity shards of every group of data shards.
+type FECPacketWriter struct {
+	Writer PacketWriter
+
+	rs     *reedSolomon
+	seq    uint32
+	count  int
+	shards [][]byte
+}
+
+func NewFECPacketWriter(writer PacketWriter, config *FEC) (*FECPacketWriter, error) {
+	rs, err := newReedSolomon(config.GetDataShardsValue(), config.GetParityShardsValue())
+	if err != nil {
+		return nil, err
+	}
+	return &FECPacketWriter{
+		Writer: writer,
+		rs:     rs,
+		shards: make([][]byte, rs.dataShards+rs.parityShards),
+	}, nil
+}
+
+func (w *FECPacketWriter) Overhead() int {
+	return w.Writer.Overhead() + fecHeaderSize + fecDataSizeSize
+}
+
+func (w *FECPacketWriter) Write(b []byte) (int, error) {
+	bb := buf.StackNew()
+	defer bb.Release()
+
+	binary.BigEndian.PutUint32(bb.Extend(fecHeaderSize), w.seq)
+	binary.BigEndian.PutUint16(bb.Extend(fecDataSizeSize), uint16(len(b)))
+	bb.Write(b)
+	w.seq++
+	w.shards[w.count] = append(w.shards[w.count][:0], bb.BytesFrom(fecHeaderSize)...)
+	w.count++
+	if _, err := w.Writer.Write(bb.Bytes()); err != nil {
+		return 0, err
+	}
+	if w.count < w.rs.dataShards {
+		return len(b), nil
+	}
+
+	w.count = 0
+	size := 0
+	for _, shard := range w.shards[:w.rs.dataShards] {
+		size = max(size, len(shard))
+	}
+	for i := range w.shards {
+		w.shards[i] = resize(w.shards[i], size)
+	}
+	w.rs.encode(w.shards)
+	for _, parity := range w.shards[w.rs.dataShards:] {
+		bb.Clear()
+		binary.BigEndian.PutUint32(bb.Extend(fecHeaderSize), w.seq)
+		bb.Write(parity)
+		w.seq++
+		if _, err := w.Writer.Write(bb.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+type fecGroup struct {
+	shards [][]byte
+	count  int
+	done   bool
+}
+
+// FECDecoder parses segments in data shards, and recovers the lost data
+// shards of a group once enough shards of the group are received.
+type FECDecoder struct {
+	rs        *reedSolomon
+	groups    map[uint32]*fecGroup
+	newest    uint32
+	recovered stats.Counter
+}
+
+func NewFECDecoder(config *FEC, recovered stats.Counter) (*FECDecoder, error) {
+	rs, err := newReedSolomon(config.GetDataShardsValue(), config.GetParityShardsValue())
+	if err != nil {
+		return nil, err
+	}
+	return &FECDecoder{
+		rs:        rs,
+		groups:    make(map[uint32]*fecGroup),
+		recovered: recovered,
+	}, nil
+}
+
+func readSegments(b []byte) []Segment {
+	var result []Segment
+	for len(b) > 0 {
+		seg, x := ReadSegment(b)
+		if seg == nil {
+			break
+		}
+		result = append(result, seg)
+		b = x
+	}
+	return result
+}
+
+// shardData returns the data in the data shard.
+func shardData(shard []byte) ([]byte, bool) {
+	if len(shard) < fecDataSizeSize {
+		return nil, false
+	}
+	size := int(binary.BigEndian.Uint16(shard))
+	if len(shard)-fecDataSizeSize < size {
+		return nil, false
+	}
+	return shard[fecDataSizeSize : fecDataSizeSize+size], true
+}
+
+// Decode returns the segments in the packet b, and in the data shards
+// recovered by it.
+func (d *FECDecoder) Decode(b []byte) []Segment {
+	if len(b) < fecHeaderSize {
+		return nil
+	}
+	total := uint32(d.rs.dataShards + d.rs.parityShards)
+	seq := binary.BigEndian.Uint32(b)
+	b = b[fecHeaderSize:]
+	number, index := seq/total, int(seq%total)
+
+	var result []Segment
+	if index < d.rs.dataShards {
+		data, ok := shardData(b)
+		if !ok {
+			return nil
+		}
+		result = readSegments(data)
+	}
+
+	if int32(d.newest-number) >= fecGroupWindow {
+		return result
+	}
+	if int32(number-d.newest) > 0 {
+		d.newest = number
+		for n := range d.groups {
+			if int32(d.newest-n) >= fecGroupWindow {
+				delete(d.groups, n)
+			}
+		}
+	}
+	group, found := d.groups[number]
+	if !found {
+		group = &fecGroup{shards: make([][]byte, total)}
+		d.groups[number] = group
+	}
+	if group.done || group.shards[index] != nil {
+		return result
+	}
+	group.shards[index] = append([]byte(nil), b...)
+	group.count++
+	if group.count < d.rs.dataShards {
+		return result
+	}
+
+	shards := group.shards
+	group.done = true
+	group.shards = nil
+	return append(result, d.recover(shards)...)
+}
+
+// recover returns the segments in the lost data shards of a group.
+func (d *FECDecoder) recover(shards [][]byte) []Segment {
+	var lost []int
+	size := 0
+	for i, shard := range shards {
+		if shard == nil {
+			if i < d.rs.dataShards {
+				lost = append(lost, i)
+			}
+			continue
+		}
+		if i >= d.rs.dataShards {
+			size = len(shard)
+		}
+	}
+	if len(lost) == 0 {
+		return nil
+	}
+	for i, shard := range shards {
+		if shard == nil {
+			continue
+		}
+		if i < d.rs.dataShards && len(shard) > size || i >= d.rs.dataShards && len(shard) != size {
+			return nil
+		}
+		shards[i] = resize(shard, size)
+	}
+	if err := d.rs.reconstruct(shards); err != nil {
+		return nil
+	}
+
+	var result []Segment
+	for _, i := range lost {
+		data, ok := shardData(shards[i])
+		if !ok {
+			continue
+		}
+		segments := readSegments(data)
+		if d.recovered != nil {
+			for _, seg := range segments {
+				if seg.Command() == CommandData {
+					d.recovered.Add(1)
+				}
+			}
+		}
+		result = append(result, segments...)
+	}
+	return result
+}
+
+// getSegmentCounters returns the counters of data segments recovered by FEC
+// and retransmitted by ARQ, if stats is enabled in the instance of ctx.
+func getSegmentCounters(ctx context.Context) (stats.Counter, stats.Counter) {
+	v := core.FromContext(ctx)
+	if v == nil {
+		return nil, nil
+	}
+	statsManager, ok := v.GetFeature(stats.ManagerType()).(stats.Manager)
+	if !ok {
+		return nil, nil
+	}
+	recovered, _ := stats.GetOrRegisterCounter(statsManager, "transport>>>kcp>>>recovered")
+	retransmitted, _ := stats.GetOrRegisterCounter(statsManager, "transport>>>kcp>>>retransmitted")
+	return recovered, retransmitted
+}
@@ -0,0 +1,71 @@
+package kcp_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xtls/xray-core/app/stats"
+	"github.com/xtls/xray-core/common"
+	. "github.com/xtls/xray-core/transport/internet/kcp"
+)
+
+type packetCapture [][]byte
+
+func (*packetCapture) Overhead() int {
+	return 0
+}
+
+func (c *packetCapture) Write(b []byte) (int, error) {
+	*c = append(*c, append([]byte(nil), b...))
+	return len(b), nil
+}
+
+func TestFECRecovery(t *testing.T) {
+	config := &FEC{DataShards: 4, ParityShards: 2}
+	capture := new(packetCapture)
+	writer, err := NewFECPacketWriter(capture, config)
+	common.Must(err)
+
+	for i := 0; i < 8; i++ {
+		seg := NewDataSegment()
+		seg.Conv = 1
+		seg.Number = uint32(i)
+		seg.Data().Write(bytes.Repeat([]byte{byte(i)}, 10+i*100))
+		b := make([]byte, seg.ByteSize())
+		seg.Serialize(b)
+		common.Must2(writer.Write(b))
+	}
+	if len(*capture) != 12 {
+		t.Fatal("expected 12 packets, but got ", len(*capture))
+	}
+
+	recovered := new(stats.Counter)
+	decoder, err := NewFECDecoder(config, recovered)
+	common.Must(err)
+	received := make(map[uint32]bool)
+	for i, packet := range *capture {
+		// Lose two data shards of the first group, and one of the second.
+		if i == 0 || i == 2 || i == 9 {
+			continue
+		}
+		for _, seg := range decoder.Decode(packet) {
+			dataSeg := seg.(*DataSegment)
+			if dataSeg.Data().Len() != 10+int32(dataSeg.Number)*100 || dataSeg.Data().Byte(0) != byte(dataSeg.Number) {
+				t.Error("unexpected data of segment ", dataSeg.Number)
+			}
+			received[dataSeg.Number] = true
+		}
+	}
+	if len(received) != 8 {
+		t.Error("expected 8 segments, but got ", len(received))
+	}
+	if recovered.Value() != 3 {
+		t.Error("expected 3 recovered segments, but got ", recovered.Value())
+	}
+}
+
+func TestFECConfig(t *testing.T) {
+	if _, err := NewFECPacketWriter(new(packetCapture), &FEC{DataShards: 200, ParityShards: 100}); err == nil {
+		t.Error("expected error of too many FEC shards")
+	}
+}
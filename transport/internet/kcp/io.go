@@ -3,6 +3,7 @@ package kcp
 import (
 	"crypto/cipher"
 	"crypto/rand"
+	"encoding/binary"
 	"io"
 
 	"github.com/xtls/xray-core/common"
@@ -22,9 +23,23 @@ type PacketWriter interface {
 type KCPPacketReader struct {
 	Security cipher.AEAD
 	Header   internet.PacketHeader
+	FEC      *FECDecoder
 }
 
 func (r *KCPPacketReader) Read(b []byte) []Segment {
+	b = r.Open(b)
+	if b == nil {
+		return nil
+	}
+	if r.FEC != nil {
+		return r.FEC.Decode(b)
+	}
+	return readSegments(b)
+}
+
+// Open removes the header of b and decrypts it in place, or returns nil if b
+// is invalid.
+func (r *KCPPacketReader) Open(b []byte) []byte {
 	if r.Header != nil {
 		if int32(len(b)) <= r.Header.Size() {
 			return nil
@@ -43,16 +58,7 @@ func (r *KCPPacketReader) Read(b []byte) []Segment {
 		}
 		b = out
 	}
-	var result []Segment
-	for len(b) > 0 {
-		seg, x := ReadSegment(b)
-		if seg == nil {
-			break
-		}
-		result = append(result, seg)
-		b = x
-	}
-	return result
+	return b
 }
 
 type KCPPacketWriter struct {
@@ -93,3 +99,30 @@ func (w *KCPPacketWriter) Write(b []byte) (int, error) {
 	_, err := w.Writer.Write(bb.Bytes())
 	return len(b), err
 }
+
+// sessionIDSize is the size of the session ID of packets with port hopping.
+const sessionIDSize = 8
+
+// SessionPacketWriter prepends the session ID to the packets of a client with
+// port hopping, by which the server tells the sessions from the same IP
+// apart, as the source port changes.
+type SessionPacketWriter struct {
+	Writer  PacketWriter
+	Session uint64
+}
+
+func (w *SessionPacketWriter) Overhead() int {
+	return w.Writer.Overhead() + sessionIDSize
+}
+
+func (w *SessionPacketWriter) Write(b []byte) (int, error) {
+	bb := buf.StackNew()
+	defer bb.Release()
+
+	binary.BigEndian.PutUint64(bb.Extend(sessionIDSize), w.Session)
+	bb.Write(b)
+	if _, err := w.Writer.Write(bb.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"crypto/cipher"
 	gotls "crypto/tls"
+	"encoding/binary"
 	"sync"
 
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/buf"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/features/stats"
 	"github.com/xtls/xray-core/transport/internet"
 	"github.com/xtls/xray-core/transport/internet/stat"
 	"github.com/xtls/xray-core/transport/internet/tls"
@@ -20,6 +22,16 @@ type ConnectionID struct {
 	Remote net.Address
 	Port   net.Port
 	Conv   uint16
+	// Session is the session ID of the client with port hopping, whose port
+	// is zero as it changes.
+	Session uint64
+}
+
+// peerID identifies the client socket a connection is from.
+type peerID struct {
+	remote  net.Address
+	port    net.Port
+	session uint64
 }
 
 // Listener defines a server listening for connections
@@ -29,10 +41,17 @@ type Listener struct {
 	hub       *udp.Hub
 	tlsConfig *gotls.Config
 	config    *Config
-	reader    PacketReader
+	reader    *KCPPacketReader
 	header    internet.PacketHeader
 	security  cipher.AEAD
 	addConn   internet.ConnHandler
+
+	// fecDecoders keeps the FEC state of each peer with connections.
+	fecDecoders map[peerID]*FECDecoder
+	// hoppingWriters keeps the writers of connections with port hopping.
+	hoppingWriters map[ConnectionID]*Writer
+	recovered      stats.Counter
+	retransmitted  stats.Counter
 }
 
 func NewListener(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig, addConn internet.ConnHandler) (*Listener, error) {
@@ -45,6 +64,11 @@ func NewListener(ctx context.Context, address net.Address, port net.Port, stream
 	if err != nil {
 		return nil, errors.New("failed to create security").Base(err).AtError()
 	}
+	if kcpSettings.Fec != nil {
+		if _, err := NewFECDecoder(kcpSettings.Fec, nil); err != nil {
+			return nil, errors.New("failed to create FEC decoder").Base(err).AtError()
+		}
+	}
 	l := &Listener{
 		header:   header,
 		security: security,
@@ -52,16 +76,24 @@ func NewListener(ctx context.Context, address net.Address, port net.Port, stream
 			Header:   header,
 			Security: security,
 		},
-		sessions: make(map[ConnectionID]*Connection),
-		config:   kcpSettings,
-		addConn:  addConn,
+		sessions:    make(map[ConnectionID]*Connection),
+		config:      kcpSettings,
+		addConn:     addConn,
+		fecDecoders: make(map[peerID]*FECDecoder),
+
+		hoppingWriters: make(map[ConnectionID]*Writer),
 	}
+	l.recovered, l.retransmitted = getSegmentCounters(ctx)
 
 	if config := tls.ConfigFromStreamSettings(streamSettings); config != nil {
 		l.tlsConfig = config.GetTLSConfig()
 	}
 
-	hub, err := udp.ListenUDP(ctx, address, port, streamSettings, udp.HubCapacity(1024))
+	options := []udp.HubOption{udp.HubCapacity(1024)}
+	if kcpSettings.PortHopping != nil {
+		options = append(options, udp.HubPorts(kcpSettings.PortHopping.GetPortList(port)...))
+	}
+	hub, err := udp.ListenUDP(ctx, address, port, streamSettings, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -78,13 +110,50 @@ func NewListener(ctx context.Context, address net.Address, port net.Port, stream
 func (l *Listener) handlePackets() {
 	receive := l.hub.Receive()
 	for payload := range receive {
-		l.OnReceive(payload.Payload, payload.Source)
+		l.OnReceive(payload.Payload, payload.Source, payload.Target)
 	}
 }
 
-func (l *Listener) OnReceive(payload *buf.Buffer, src net.Destination) {
-	segments := l.reader.Read(payload.Bytes())
-	payload.Release()
+// fecDecoder returns the FEC decoder of the packets from peer, which is kept
+// for the FEC state of peer once a connection is created from it.
+func (l *Listener) fecDecoder(peer peerID) *FECDecoder {
+	l.Lock()
+	defer l.Unlock()
+
+	if decoder, found := l.fecDecoders[peer]; found {
+		return decoder
+	}
+	decoder, _ := NewFECDecoder(l.config.Fec, l.recovered)
+	return decoder
+}
+
+// OnReceive handles payload from src to the local address target.
+func (l *Listener) OnReceive(payload *buf.Buffer, src net.Destination, target net.Destination) {
+	defer payload.Release()
+
+	b := l.reader.Open(payload.Bytes())
+	peer := peerID{remote: src.Address, port: src.Port}
+	if l.config.PortHopping != nil {
+		// Packets from all ports of a peer hopping among ports are of the
+		// same session, and replied to the latest port.
+		if len(b) < sessionIDSize {
+			b = nil
+		} else {
+			peer.port = 0
+			peer.session = binary.BigEndian.Uint64(b)
+			b = b[sessionIDSize:]
+		}
+	}
+	var segments []Segment
+	var decoder *FECDecoder
+	switch {
+	case b == nil:
+	case l.config.Fec != nil:
+		decoder = l.fecDecoder(peer)
+		segments = decoder.Decode(b)
+	default:
+		segments = readSegments(b)
+	}
 
 	if len(segments) == 0 {
 		errors.LogInfo(context.Background(), "discarding invalid payload from ", src)
@@ -95,9 +164,10 @@ func (l *Listener) OnReceive(payload *buf.Buffer, src net.Destination) {
 	cmd := segments[0].Command()
 
 	id := ConnectionID{
-		Remote: src.Address,
-		Port:   src.Port,
-		Conv:   conv,
+		Remote:  peer.remote,
+		Port:    peer.port,
+		Conv:    conv,
+		Session: peer.session,
 	}
 
 	l.Lock()
@@ -113,22 +183,32 @@ func (l *Listener) OnReceive(payload *buf.Buffer, src net.Destination) {
 			id:       id,
 			hub:      l.hub,
 			dest:     src,
+			port:     target.Port,
 			listener: l,
 		}
+		if l.config.PortHopping != nil {
+			l.hoppingWriters[id] = writer
+		}
 		remoteAddr := &net.UDPAddr{
 			IP:   src.Address.IP(),
 			Port: int(src.Port),
 		}
 		localAddr := l.hub.Addr()
-		conn = NewConnection(ConnMetadata{
-			LocalAddr:    localAddr,
-			RemoteAddr:   remoteAddr,
-			Conversation: conv,
-		}, &KCPPacketWriter{
+		var packetWriter PacketWriter = &KCPPacketWriter{
 			Header:   l.header,
 			Security: l.security,
 			Writer:   writer,
-		}, writer, l.config)
+		}
+		if l.config.Fec != nil {
+			packetWriter, _ = NewFECPacketWriter(packetWriter, l.config.Fec)
+			l.fecDecoders[peer] = decoder
+		}
+		conn = NewConnection(ConnMetadata{
+			LocalAddr:     localAddr,
+			RemoteAddr:    remoteAddr,
+			Conversation:  conv,
+			Retransmitted: l.retransmitted,
+		}, packetWriter, writer, l.config)
 		var netConn stat.Connection = conn
 		if l.tlsConfig != nil {
 			netConn = tls.Server(conn, l.tlsConfig)
@@ -136,6 +216,8 @@ func (l *Listener) OnReceive(payload *buf.Buffer, src net.Destination) {
 
 		l.addConn(netConn)
 		l.sessions[id] = conn
+	} else if writer, found := l.hoppingWriters[id]; found {
+		writer.update(src, target.Port)
 	}
 	conn.Input(segments)
 }
@@ -143,6 +225,8 @@ func (l *Listener) OnReceive(payload *buf.Buffer, src net.Destination) {
 func (l *Listener) Remove(id ConnectionID) {
 	l.Lock()
 	delete(l.sessions, id)
+	delete(l.fecDecoders, peerID{remote: id.Remote, port: id.Port, session: id.Session})
+	delete(l.hoppingWriters, id)
 	l.Unlock()
 }
 
@@ -174,13 +258,30 @@ func (l *Listener) Addr() net.Addr {
 
 type Writer struct {
 	id       ConnectionID
-	dest     net.Destination
 	hub      *udp.Hub
 	listener *Listener
+
+	// access guards the latest source of the peer and the local port it
+	// sends to, which change with port hopping.
+	access sync.Mutex
+	dest   net.Destination
+	port   net.Port
+}
+
+func (w *Writer) update(dest net.Destination, port net.Port) {
+	w.access.Lock()
+	defer w.access.Unlock()
+
+	w.dest = dest
+	w.port = port
 }
 
 func (w *Writer) Write(payload []byte) (int, error) {
-	return w.hub.WriteTo(payload, w.dest)
+	w.access.Lock()
+	dest, port := w.dest, w.port
+	w.access.Unlock()
+
+	return w.hub.WriteToFrom(payload, dest, port)
 }
 
 func (w *Writer) Close() error {
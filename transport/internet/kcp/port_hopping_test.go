@@ -0,0 +1,121 @@
+package kcp_test
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/testing/servers/udp"
+	"github.com/xtls/xray-core/transport/internet"
+	. "github.com/xtls/xray-core/transport/internet/kcp"
+	"github.com/xtls/xray-core/transport/internet/stat"
+)
+
+func TestPortHopping(t *testing.T) {
+	port := udp.PickPort()
+	config := &Config{
+		PortHopping: &internet.PortHoppingConfig{
+			Ports: &net.PortList{Range: []*net.PortRange{
+				net.SinglePortRange(udp.PickPort()),
+				net.SinglePortRange(udp.PickPort()),
+			}},
+			Interval: 1,
+		},
+	}
+	listener, err := NewListener(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName:     "mkcp",
+		ProtocolSettings: config,
+	}, func(conn stat.Connection) {
+		go func() {
+			defer conn.Close()
+			io.Copy(conn, conn)
+		}()
+	})
+	common.Must(err)
+	defer listener.Close()
+
+	conn, err := DialKCP(context.Background(), net.UDPDestination(net.LocalHostIP, port), &internet.MemoryStreamConfig{
+		ProtocolName:     "mkcp",
+		ProtocolSettings: config,
+	})
+	common.Must(err)
+
+	for i := 0; i < 4; i++ {
+		payload := make([]byte, 64*1024)
+		rand.Read(payload)
+		common.Must2(conn.Write(payload))
+		received := make([]byte, len(payload))
+		common.Must2(io.ReadFull(conn, received))
+		if r := cmp.Diff(received, payload); r != "" {
+			t.Fatal(r)
+		}
+		if v := listener.ActiveConnections(); v != 1 {
+			t.Fatal("active connections: ", v)
+		}
+		time.Sleep(700 * time.Millisecond)
+	}
+	conn.Close()
+
+	for i := 0; i < 60 && listener.ActiveConnections() > 0; i++ {
+		time.Sleep(500 * time.Millisecond)
+	}
+	if v := listener.ActiveConnections(); v != 0 {
+		t.Error("active connections: ", v)
+	}
+}
+
+func TestPortHoppingSessions(t *testing.T) {
+	port := udp.PickPort()
+	config := &Config{
+		PortHopping: &internet.PortHoppingConfig{
+			Ports: &net.PortList{Range: []*net.PortRange{net.SinglePortRange(udp.PickPort())}},
+		},
+		Fec: &FEC{DataShards: 2, ParityShards: 1},
+	}
+	var conns []stat.Connection
+	listener, err := NewListener(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName:     "mkcp",
+		ProtocolSettings: config,
+	}, func(conn stat.Connection) {
+		conns = append(conns, conn)
+	})
+	common.Must(err)
+	defer listener.Close()
+
+	header, err := config.GetPackerHeader()
+	common.Must(err)
+	security, err := config.GetSecurity()
+	common.Must(err)
+	// two clients behind the same IP with the same conv
+	ping := &CmdOnlySegment{Conv: 1, Cmd: CommandPing}
+	for session, srcPort := range []net.Port{10000, 10001} {
+		capture := new(packetCapture)
+		writer, err := NewFECPacketWriter(&SessionPacketWriter{
+			Writer:  &KCPPacketWriter{Header: header, Security: security, Writer: capture},
+			Session: uint64(session + 1),
+		}, config.Fec)
+		common.Must(err)
+		b := make([]byte, ping.ByteSize())
+		ping.Serialize(b)
+		common.Must2(writer.Write(b))
+
+		for _, packet := range *capture {
+			payload := buf.New()
+			payload.Write(packet)
+			listener.OnReceive(payload, net.UDPDestination(net.LocalHostIP, srcPort), net.UDPDestination(net.LocalHostIP, port))
+		}
+	}
+
+	if v := listener.ActiveConnections(); v != 2 {
+		t.Error("active connections: ", v)
+	}
+	if len(conns) != 2 {
+		t.Error("accepted connections: ", len(conns))
+	}
+}
@@ -11,6 +11,10 @@ type MemoryStreamConfig struct {
 	SecuritySettings interface{}
 	SocketSettings   *SocketConfig
 	DownloadSettings *MemoryStreamConfig
+	Prewarm          *PrewarmConfig
+	// PrewarmPools are set by the outbound handler owning the settings if
+	// Prewarm is enabled.
+	PrewarmPools *PrewarmPools
 }
 
 // ToMemoryStreamConfig converts a StreamConfig to MemoryStreamConfig. It returns a default non-nil MemoryStreamConfig for nil input.
@@ -34,6 +38,7 @@ func ToMemoryStreamConfig(s *StreamConfig) (*MemoryStreamConfig, error) {
 			}
 		}
 		mss.SocketSettings = s.SocketSettings
+		mss.Prewarm = s.Prewarm
 	}
 
 	if s != nil && s.HasSecuritySettings() {
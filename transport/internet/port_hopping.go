@@ -0,0 +1,143 @@
+package internet
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/xtls/xray-core/common/dice"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/signal/done"
+)
+
+const defaultPortHoppingInterval = 30 * time.Second
+
+// GetIntervalValue returns the interval between hops.
+func (c *PortHoppingConfig) GetIntervalValue() time.Duration {
+	if c.GetInterval() == 0 {
+		return defaultPortHoppingInterval
+	}
+	return time.Duration(c.GetInterval()) * time.Second
+}
+
+// GetPortList returns the ports to hop among, including port.
+func (c *PortHoppingConfig) GetPortList(port net.Port) []net.Port {
+	ports := []net.Port{port}
+	for _, r := range c.GetPorts().GetRange() {
+		for p := r.From; p <= r.To && p <= 65535; p++ {
+			if net.Port(p) != port {
+				ports = append(ports, net.Port(p))
+			}
+		}
+	}
+	return ports
+}
+
+// PortHoppingConn is a UDP connection sending packets to one of the ports of
+// the destination, which is changed periodically, and receiving packets from
+// any port of it.
+type PortHoppingConn struct {
+	conn  net.PacketConn
+	ports []net.Port
+	dest  atomic.Pointer[net.UDPAddr]
+	done  *done.Instance
+}
+
+// DialPortHopping dials dest by the system dialer, and hops among the ports
+// of config every interval of it.
+func DialPortHopping(ctx context.Context, dest net.Destination, sockopt *SocketConfig, config *PortHoppingConfig) (*PortHoppingConn, error) {
+	rawConn, err := DialSystem(ctx, dest, sockopt)
+	if err != nil {
+		return nil, err
+	}
+	wrapper, ok := rawConn.(*PacketConnWrapper)
+	if !ok {
+		rawConn.Close()
+		return nil, errors.New("port hopping is not supported by the dialer to ", dest)
+	}
+	destAddr, ok := wrapper.Dest.(*net.UDPAddr)
+	if !ok {
+		rawConn.Close()
+		return nil, errors.New("unexpected destination address ", wrapper.Dest)
+	}
+
+	c := &PortHoppingConn{
+		conn:  wrapper.Conn,
+		ports: config.GetPortList(dest.Port),
+		done:  done.New(),
+	}
+	c.dest.Store(destAddr)
+	go c.hop(ctx, config.GetIntervalValue())
+	return c, nil
+}
+
+func (c *PortHoppingConn) hop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done.Wait():
+			return
+		case <-ticker.C:
+		}
+		current := c.dest.Load()
+		dest := &net.UDPAddr{
+			IP:   current.IP,
+			Port: int(c.ports[dice.Roll(len(c.ports))]),
+			Zone: current.Zone,
+		}
+		c.dest.Store(dest)
+		errors.LogDebug(ctx, "hopping to ", dest)
+	}
+}
+
+// Read implements net.Conn, and drops packets not from the destination.
+func (c *PortHoppingConn) Read(b []byte) (int, error) {
+	for {
+		n, addr, err := c.conn.ReadFrom(b)
+		if err != nil {
+			return n, err
+		}
+		if udpAddr, ok := addr.(*net.UDPAddr); ok && udpAddr.IP.Equal(c.dest.Load().IP) {
+			return n, nil
+		}
+	}
+}
+
+// Write implements net.Conn.
+func (c *PortHoppingConn) Write(b []byte) (int, error) {
+	return c.conn.WriteTo(b, c.dest.Load())
+}
+
+// Close implements net.Conn.
+func (c *PortHoppingConn) Close() error {
+	c.done.Close()
+	return c.conn.Close()
+}
+
+// LocalAddr implements net.Conn.
+func (c *PortHoppingConn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+// RemoteAddr implements net.Conn, and returns the current destination.
+func (c *PortHoppingConn) RemoteAddr() net.Addr {
+	return c.dest.Load()
+}
+
+// SetDeadline implements net.Conn.
+func (c *PortHoppingConn) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
+}
+
+// SetReadDeadline implements net.Conn.
+func (c *PortHoppingConn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline implements net.Conn.
+func (c *PortHoppingConn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
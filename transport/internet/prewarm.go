@@ -0,0 +1,210 @@
+package internet
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/transport/internet/stat"
+)
+
+const (
+	defaultPrewarmMaxIdle   = 30 * time.Second
+	prewarmHandshakeTimeout = 16 * time.Second
+)
+
+// GetMaxIdleValue returns the maximum time for a connection to be idle in the pool.
+func (c *PrewarmConfig) GetMaxIdleValue() time.Duration {
+	if c.GetMaxIdle() == 0 {
+		return defaultPrewarmMaxIdle
+	}
+	return time.Duration(c.GetMaxIdle()) * time.Second
+}
+
+// PrewarmPools keeps the pools of prewarmed connections of an outbound
+// handler, one for each destination, until closed.
+type PrewarmPools struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	access sync.Mutex
+	pools  map[net.Destination]*prewarmPool
+	closed bool
+}
+
+// NewPrewarmPools creates the pools of an outbound handler. Connections are
+// prewarmed with ctx instead of the contexts of the connections dialed, so ctx
+// should carry only what the transports need, like the Xray instance.
+func NewPrewarmPools(ctx context.Context) *PrewarmPools {
+	ctx, cancel := context.WithCancel(ctx)
+	return &PrewarmPools{
+		ctx:    ctx,
+		cancel: cancel,
+		pools:  make(map[net.Destination]*prewarmPool),
+	}
+}
+
+// get returns the pool of dest, or nil if closed.
+func (p *PrewarmPools) get(dest net.Destination, settings *MemoryStreamConfig, dialer dialFunc) *prewarmPool {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	if p.closed {
+		return nil
+	}
+	pool, found := p.pools[dest]
+	if !found {
+		pool = &prewarmPool{
+			ctx:      p.ctx,
+			dest:     dest,
+			settings: settings,
+			dialer:   dialer,
+			size:     int(settings.Prewarm.GetSize()),
+			maxIdle:  settings.Prewarm.GetMaxIdleValue(),
+		}
+		p.pools[dest] = pool
+	}
+	return pool
+}
+
+// Close implements common.Closable. It cancels the connections being
+// prewarmed, and closes the idle ones.
+func (p *PrewarmPools) Close() error {
+	p.access.Lock()
+	p.closed = true
+	pools := p.pools
+	p.pools = nil
+	p.access.Unlock()
+
+	p.cancel()
+	for _, pool := range pools {
+		pool.close()
+	}
+	return nil
+}
+
+type prewarmConn struct {
+	stat.Connection
+	timer *time.Timer
+}
+
+// prewarmPool keeps connections to dest established in the background, which
+// is refilled after each dial, and drained while not dialed for max idle.
+type prewarmPool struct {
+	sync.Mutex
+	ctx      context.Context
+	dest     net.Destination
+	settings *MemoryStreamConfig
+	dialer   dialFunc
+	size     int
+	maxIdle  time.Duration
+
+	idle     []*prewarmConn
+	dialing  int
+	lastUsed time.Time
+	closed   bool
+}
+
+// Dial returns an idle connection in the pool if any, or dials a new one.
+func (p *prewarmPool) Dial(ctx context.Context) (stat.Connection, error) {
+	p.Lock()
+	p.lastUsed = time.Now()
+	var conn *prewarmConn
+	for conn == nil && len(p.idle) > 0 {
+		conn = p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if !conn.timer.Stop() {
+			// Expiring in remove.
+			conn = nil
+		}
+	}
+	p.fill()
+	p.Unlock()
+
+	if conn != nil {
+		errors.LogDebug(ctx, "using prewarmed connection to ", p.dest)
+		return conn.Connection, nil
+	}
+	return p.dialer(ctx, p.dest, p.settings)
+}
+
+// fill dials the connections missing in the pool.
+func (p *prewarmPool) fill() {
+	if p.closed {
+		return
+	}
+	for ; len(p.idle)+p.dialing < p.size; p.dialing++ {
+		go func() {
+			conn, err := p.dialer(p.ctx, p.dest, p.settings)
+			if err == nil {
+				err = handshake(p.ctx, conn)
+			}
+			p.Lock()
+			defer p.Unlock()
+			p.dialing--
+			if err != nil {
+				errors.LogInfoInner(p.ctx, err, "failed to prewarm connection to ", p.dest)
+				return
+			}
+			if p.closed {
+				conn.Close()
+				return
+			}
+			c := &prewarmConn{Connection: conn}
+			c.timer = time.AfterFunc(p.maxIdle, func() {
+				p.remove(c)
+			})
+			p.idle = append(p.idle, c)
+		}()
+	}
+}
+
+// close closes the idle connections, and stops prewarming.
+func (p *prewarmPool) close() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.closed = true
+	for _, c := range p.idle {
+		c.timer.Stop()
+		c.Close()
+	}
+	p.idle = nil
+}
+
+// remove closes the expired connection, and replaces it if the pool is dialed
+// in max idle.
+func (p *prewarmPool) remove(conn *prewarmConn) {
+	conn.Close()
+
+	p.Lock()
+	defer p.Unlock()
+
+	for i, c := range p.idle {
+		if c == conn {
+			p.idle = append(p.idle[:i], p.idle[i+1:]...)
+			break
+		}
+	}
+	if time.Since(p.lastUsed) < p.maxIdle {
+		p.fill()
+	}
+}
+
+// handshake completes the handshake of the security layer of conn if any,
+// which is otherwise done in the first read or write.
+func handshake(ctx context.Context, conn stat.Connection) error {
+	if c, ok := conn.(interface {
+		HandshakeContext(context.Context) error
+	}); ok {
+		ctx, cancel := context.WithTimeout(ctx, prewarmHandshakeTimeout)
+		defer cancel()
+		if err := c.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package internet_test
+
+import (
+	"context"
+	gonet "net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	. "github.com/xtls/xray-core/transport/internet"
+	"github.com/xtls/xray-core/transport/internet/stat"
+)
+
+type prewarmTestConn struct {
+	gonet.Conn
+	ctx    context.Context
+	closed atomic.Bool
+}
+
+type prewarmTestKey struct{}
+
+func (c *prewarmTestConn) Close() error {
+	c.closed.Store(true)
+	return c.Conn.Close()
+}
+
+func TestPrewarmPool(t *testing.T) {
+	var access sync.Mutex
+	var conns []*prewarmTestConn
+	dialed := func() []*prewarmTestConn {
+		access.Lock()
+		defer access.Unlock()
+		return append([]*prewarmTestConn(nil), conns...)
+	}
+	common.Must(RegisterTransportDialer("prewarmtest", func(ctx context.Context, dest net.Destination, streamSettings *MemoryStreamConfig) (stat.Connection, error) {
+		c, _ := gonet.Pipe()
+		conn := &prewarmTestConn{Conn: c, ctx: ctx}
+		access.Lock()
+		conns = append(conns, conn)
+		access.Unlock()
+		return conn, nil
+	}))
+
+	pools := NewPrewarmPools(context.Background())
+	settings := &MemoryStreamConfig{
+		ProtocolName: "prewarmtest",
+		Prewarm:      &PrewarmConfig{Size: 2, MaxIdle: 1},
+		PrewarmPools: pools,
+	}
+	dest := net.TCPDestination(net.LocalHostIP, 443)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), prewarmTestKey{}, true))
+	first, err := Dial(ctx, dest, settings)
+	common.Must(err)
+	cancel()
+	for i := 0; i < 100 && len(dialed()) < 3; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := len(dialed()); n != 3 {
+		t.Fatal("expected 3 dialed connections, but got ", n)
+	}
+
+	for _, conn := range dialed()[1:] {
+		if conn.ctx.Value(prewarmTestKey{}) != nil || conn.ctx.Err() != nil {
+			t.Error("expected connections prewarmed with the context of the pools")
+		}
+	}
+
+	second, err := Dial(context.Background(), dest, settings)
+	common.Must(err)
+	if second == first || (second != stat.Connection(dialed()[1]) && second != stat.Connection(dialed()[2])) {
+		t.Error("expected a prewarmed connection")
+	}
+
+	time.Sleep(2500 * time.Millisecond)
+	for _, conn := range dialed() {
+		if conn != first && conn != second && !conn.closed.Load() {
+			t.Error("expected idle connections to be closed")
+		}
+	}
+
+	third, err := Dial(context.Background(), dest, settings)
+	common.Must(err)
+	for i := 0; i < 100 && len(dialed()) < 6; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	common.Must(pools.Close())
+	for _, conn := range dialed() {
+		if conn != first && conn != second && conn != third && !conn.closed.Load() {
+			t.Error("expected idle connections to be closed with the pools")
+		}
+	}
+}
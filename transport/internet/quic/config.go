@@ -0,0 +1,62 @@
+package quic
+
+import (
+	gotls "crypto/tls"
+	"time"
+
+	"github.com/xtls/quic-go"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/transport/internet"
+	"github.com/xtls/xray-core/transport/internet/tls"
+)
+
+const (
+	handshakeTimeout            = 8 * time.Second
+	defaultIdleTimeout          = 30 * time.Second
+	defaultKeepAlivePeriod      = 10 * time.Second
+	defaultMaxConcurrentStreams = 1024
+)
+
+func (c *Config) getQUICConfig() *quic.Config {
+	config := &quic.Config{
+		HandshakeIdleTimeout: handshakeTimeout,
+		MaxIdleTimeout:       defaultIdleTimeout,
+		KeepAlivePeriod:      defaultKeepAlivePeriod,
+		MaxIncomingStreams:   defaultMaxConcurrentStreams,
+		Allow0RTT:            c.ZeroRtt,
+	}
+	if c.IdleTimeout > 0 {
+		config.MaxIdleTimeout = time.Duration(c.IdleTimeout) * time.Second
+	}
+	if c.KeepAlivePeriod > 0 {
+		config.KeepAlivePeriod = time.Duration(c.KeepAlivePeriod) * time.Second
+	}
+	if c.MaxConcurrentStreams > 0 {
+		config.MaxIncomingStreams = int64(c.MaxConcurrentStreams)
+	}
+	return config
+}
+
+// getTLSConfig returns the TLS config QUIC runs on, with ALPN h3 unless set.
+func getTLSConfig(streamSettings *internet.MemoryStreamConfig, opts ...tls.Option) (*gotls.Config, error) {
+	config := tls.ConfigFromStreamSettings(streamSettings)
+	if config == nil {
+		return nil, errors.New("QUIC transport requires TLS")
+	}
+	if len(config.NextProtocol) == 0 {
+		opts = append(opts, tls.WithNextProto("h3"))
+	}
+	tlsConfig := config.GetTLSConfig(opts...)
+	tlsConfig.MinVersion = gotls.VersionTLS13
+	// quic-go fails to detect servers with session tickets disabled, and 0-RTT
+	// data is sent with resumed sessions only
+	tlsConfig.SessionTicketsDisabled = false
+	return tlsConfig, nil
+}
+
+func init() {
+	common.Must(internet.RegisterProtocolConfigCreator(protocolName, func() interface{} {
+		return new(Config)
+	}))
+}
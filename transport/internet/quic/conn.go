@@ -0,0 +1,35 @@
+package quic
+
+import (
+	"github.com/xtls/quic-go"
+	"github.com/xtls/xray-core/common/net"
+)
+
+// connection is a stream of a QUIC connection.
+type connection struct {
+	quic.Stream
+	local  net.Addr
+	remote net.Addr
+}
+
+func newConnection(stream quic.Stream, conn quic.Connection) *connection {
+	return &connection{
+		Stream: stream,
+		local:  conn.LocalAddr(),
+		remote: conn.RemoteAddr(),
+	}
+}
+
+// Close closes both directions of the stream.
+func (c *connection) Close() error {
+	c.Stream.CancelRead(0)
+	return c.Stream.Close()
+}
+
+func (c *connection) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *connection) RemoteAddr() net.Addr {
+	return c.remote
+}
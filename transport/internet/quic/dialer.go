@@ -0,0 +1,152 @@
+package quic
+
+import (
+	"context"
+	"sync"
+
+	"github.com/xtls/quic-go"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/transport/internet"
+	"github.com/xtls/xray-core/transport/internet/stat"
+	"github.com/xtls/xray-core/transport/internet/tls"
+)
+
+type connKey struct {
+	dest     net.Destination
+	settings *internet.MemoryStreamConfig
+}
+
+// clientConns are the connections shared by the streams to the same
+// destination with the same settings.
+var clientConns = struct {
+	sync.Mutex
+	m map[connKey]quic.Connection
+}{m: make(map[connKey]quic.Connection)}
+
+func isActive(conn quic.Connection) bool {
+	select {
+	case <-conn.Context().Done():
+		return false
+	default:
+		return true
+	}
+}
+
+func getConnection(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) (quic.Connection, error) {
+	key := connKey{dest: dest, settings: streamSettings}
+	clientConns.Lock()
+	conn, found := clientConns.m[key]
+	clientConns.Unlock()
+	if found && isActive(conn) {
+		return conn, nil
+	}
+
+	conn, err := dialConnection(detachedContext(ctx), dest, streamSettings)
+	if err != nil {
+		return nil, err
+	}
+	clientConns.Lock()
+	if current, found := clientConns.m[key]; found && current != conn && isActive(current) {
+		// another connection is dialed meanwhile
+		clientConns.Unlock()
+		conn.CloseWithError(0, "")
+		return current, nil
+	}
+	clientConns.m[key] = conn
+	clientConns.Unlock()
+	go func() {
+		<-conn.Context().Done()
+		clientConns.Lock()
+		if clientConns.m[key] == conn {
+			delete(clientConns.m, key)
+		}
+		clientConns.Unlock()
+	}()
+	return conn, nil
+}
+
+// detachedContext returns the context to dial a connection shared by requests
+// with. It outlives the request dialing it, and keeps only the instance and the
+// gateway and tag of the outbound.
+func detachedContext(ctx context.Context) context.Context {
+	detached := context.Background()
+	if core.FromContext(ctx) != nil {
+		detached = core.ToBackgroundDetachedContext(ctx)
+	}
+	if outbounds := session.OutboundsFromContext(ctx); len(outbounds) > 0 {
+		ob := outbounds[len(outbounds)-1]
+		detached = session.ContextWithOutbounds(detached, []*session.Outbound{{
+			Gateway: ob.Gateway,
+			Tag:     ob.Tag,
+		}})
+	}
+	return detached
+}
+
+func dialConnection(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) (quic.Connection, error) {
+	tlsConfig, err := getTLSConfig(streamSettings, tls.WithContext(ctx), tls.WithDestination(dest))
+	if err != nil {
+		return nil, err
+	}
+	config := streamSettings.ProtocolSettings.(*Config)
+
+	udpDest := net.UDPDestination(dest.Address, dest.Port)
+	rawConn, err := internet.DialSystem(ctx, udpDest, streamSettings.SocketSettings)
+	if err != nil {
+		return nil, errors.New("failed to dial UDP to ", udpDest).Base(err)
+	}
+	var packetConn net.PacketConn
+	var remote string
+	switch c := rawConn.(type) {
+	case *internet.PacketConnWrapper:
+		packetConn, remote = c.Conn, c.Dest.String()
+	case *net.UDPConn:
+		packetConn, remote = c, c.RemoteAddr().String()
+	default:
+		packetConn, remote = &internet.FakePacketConn{Conn: c}, c.RemoteAddr().String()
+	}
+	udpAddr, err := net.ResolveUDPAddr("udp", remote)
+	if err != nil {
+		rawConn.Close()
+		return nil, err
+	}
+
+	var conn quic.Connection
+	if config.ZeroRtt {
+		conn, err = quic.DialEarly(ctx, packetConn, udpAddr, tlsConfig, config.getQUICConfig())
+	} else {
+		conn, err = quic.Dial(ctx, packetConn, udpAddr, tlsConfig, config.getQUICConfig())
+	}
+	if err != nil {
+		rawConn.Close()
+		return nil, errors.New("failed to dial QUIC to ", dest).Base(err)
+	}
+	// the packet connection is not closed by QUIC as it is not created by it
+	go func() {
+		<-conn.Context().Done()
+		rawConn.Close()
+	}()
+	errors.LogInfo(ctx, "QUIC connection opened to ", dest)
+	return conn, nil
+}
+
+// Dial opens a stream to dest, on the connection shared with other streams.
+func Dial(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) (stat.Connection, error) {
+	conn, err := getConnection(ctx, dest, streamSettings)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		return nil, errors.New("failed to open QUIC stream to ", dest).Base(err)
+	}
+	return stat.Connection(newConnection(stream, conn)), nil
+}
+
+func init() {
+	common.Must(internet.RegisterTransportDialer(protocolName, Dial))
+}
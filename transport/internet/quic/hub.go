@@ -0,0 +1,91 @@
+package quic
+
+import (
+	"context"
+
+	"github.com/xtls/quic-go"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/transport/internet"
+	"github.com/xtls/xray-core/transport/internet/stat"
+)
+
+// Listener accepts the streams of QUIC connections.
+type Listener struct {
+	rawConn  net.PacketConn
+	listener *quic.EarlyListener
+	addConn  internet.ConnHandler
+}
+
+func (l *Listener) keepAccepting() {
+	for {
+		conn, err := l.listener.Accept(context.Background())
+		if err != nil {
+			errors.LogInfoInner(context.Background(), err, "QUIC listener closed")
+			return
+		}
+		go l.acceptStreams(conn)
+	}
+}
+
+func (l *Listener) acceptStreams(conn quic.Connection) {
+	for {
+		stream, err := conn.AcceptStream(context.Background())
+		if err != nil {
+			errors.LogDebugInner(context.Background(), err, "QUIC connection from ", conn.RemoteAddr(), " closed")
+			return
+		}
+		l.addConn(stat.Connection(newConnection(stream, conn)))
+	}
+}
+
+// Addr implements internet.Listener.
+func (l *Listener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// Close implements internet.Listener.
+func (l *Listener) Close() error {
+	err := l.listener.Close()
+	l.rawConn.Close()
+	return err
+}
+
+// Listen listens for QUIC connections on UDP address:port.
+func Listen(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig, addConn internet.ConnHandler) (internet.Listener, error) {
+	if address.Family().IsDomain() {
+		return nil, errors.New("QUIC transport does not support domain socket")
+	}
+	tlsConfig, err := getTLSConfig(streamSettings)
+	if err != nil {
+		return nil, err
+	}
+	config := streamSettings.ProtocolSettings.(*Config)
+
+	rawConn, err := internet.ListenSystemPacket(context.Background(), &net.UDPAddr{
+		IP:   address.IP(),
+		Port: int(port),
+	}, streamSettings.SocketSettings)
+	if err != nil {
+		return nil, errors.New("failed to listen UDP for QUIC on ", address, ":", port).Base(err)
+	}
+	listener, err := quic.ListenEarly(rawConn, tlsConfig, config.getQUICConfig())
+	if err != nil {
+		rawConn.Close()
+		return nil, errors.New("failed to listen QUIC on ", address, ":", port).Base(err)
+	}
+	errors.LogInfo(ctx, "listening QUIC on ", address, ":", port)
+
+	l := &Listener{
+		rawConn:  rawConn,
+		listener: listener,
+		addConn:  addConn,
+	}
+	go l.keepAccepting()
+	return l, nil
+}
+
+func init() {
+	common.Must(internet.RegisterTransportListener(protocolName, Listen))
+}
@@ -0,0 +1,3 @@
+package quic
+
+const protocolName = "quic"
@@ -0,0 +1,88 @@
+package quic_test
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol/tls/cert"
+	"github.com/xtls/xray-core/testing/servers/udp"
+	"github.com/xtls/xray-core/transport/internet"
+	. "github.com/xtls/xray-core/transport/internet/quic"
+	"github.com/xtls/xray-core/transport/internet/stat"
+	"github.com/xtls/xray-core/transport/internet/tls"
+)
+
+func TestQUICStreams(t *testing.T) {
+	port := udp.PickPort()
+	listener, err := Listen(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName:     "quic",
+		ProtocolSettings: &Config{ZeroRtt: true},
+		SecurityType:     "tls",
+		SecuritySettings: &tls.Config{
+			Certificate: []*tls.Certificate{tls.ParseCertificate(cert.MustGenerate(nil, cert.DNSNames("www.example.com")))},
+		},
+	}, func(conn stat.Connection) {
+		go func() {
+			defer conn.Close()
+			b := make([]byte, 1024)
+			for {
+				n, err := conn.Read(b)
+				if err != nil {
+					return
+				}
+				if _, err := conn.Write(b[:n]); err != nil {
+					return
+				}
+			}
+		}()
+	})
+	common.Must(err)
+	defer listener.Close()
+
+	dest := net.TCPDestination(net.LocalHostIP, port)
+	// the latter resumes the session of the former with 0-RTT
+	for _, config := range []*Config{{}, {ZeroRtt: true}} {
+		streamSettings := &internet.MemoryStreamConfig{
+			ProtocolName:     "quic",
+			ProtocolSettings: config,
+			SecurityType:     "tls",
+			SecuritySettings: &tls.Config{
+				ServerName:              "www.example.com",
+				AllowInsecure:           true,
+				EnableSessionResumption: true,
+			},
+		}
+
+		var localAddr string
+		for i := 0; i < 3; i++ {
+			// the shared connection outlives the request dialing it
+			ctx, cancel := context.WithCancel(context.Background())
+			conn, err := Dial(ctx, dest, streamSettings)
+			common.Must(err)
+			// streams share the connection
+			if localAddr == "" {
+				localAddr = conn.LocalAddr().String()
+			} else if conn.LocalAddr().String() != localAddr {
+				t.Error("expected shared connection, actual local address ", conn.LocalAddr())
+			}
+
+			payload := make([]byte, 10240)
+			common.Must2(rand.Read(payload))
+			common.Must2(conn.Write(payload))
+			response := make([]byte, len(payload))
+			conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			common.Must2(io.ReadFull(conn, response))
+			if r := cmp.Diff(response, payload); r != "" {
+				t.Error(r)
+			}
+			common.Must(conn.Close())
+			cancel()
+		}
+	}
+}
@@ -0,0 +1,463 @@
+package tls
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/platform"
+	"github.com/xtls/xray-core/common/platform/filesystem"
+	"golang.org/x/crypto/acme"
+)
+
+const (
+	// acmeRenewBefore is the longest time before expiry certificates are
+	// renewed, which is shortened to a third of the lifetime of short-lived
+	// ones.
+	acmeRenewBefore = 30 * 24 * time.Hour
+	// acmeCheckInterval is the interval certificates are checked for renewal.
+	acmeCheckInterval = 12 * time.Hour
+	// acmeRetryInterval is the interval failed issuance is retried.
+	acmeRetryInterval = 10 * time.Minute
+	// acmeTimeout is the timeout of issuing a certificate.
+	acmeTimeout = 5 * time.Minute
+)
+
+// acmeManagers are the started managers, which are shared by the TLS configs
+// of the same domains and storage.
+var acmeManagers = struct {
+	sync.Mutex
+	m map[string]*acmeManager
+}{m: make(map[string]*acmeManager)}
+
+// acmeManager obtains and renews the certificate of some domains by ACME.
+type acmeManager struct {
+	key     string
+	config  *ACME
+	storage string
+	client  *acme.Client
+	// refs is the number of the holders started the manager, guarded by
+	// acmeManagers.
+	refs   int
+	cancel context.CancelFunc
+
+	access     sync.RWMutex
+	cert       *tls.Certificate
+	httpTokens map[string][]byte
+	alpnCerts  map[string]*tls.Certificate
+}
+
+func acmeStorage(config *ACME) string {
+	if config.StoragePath == "" {
+		return platform.GetAssetLocation("acme")
+	}
+	return config.StoragePath
+}
+
+func acmeKey(config *ACME) string {
+	return acmeStorage(config) + "|" + strings.Join(config.Domain, ",")
+}
+
+// StartACME starts obtaining and renewing the ACME certificate of the config
+// if any, which is served by the TLS configs got from it until the returned
+// closer is closed. It returns nil if the config has no ACME.
+func (c *Config) StartACME() common.Closable {
+	if c == nil || c.Acme == nil || len(c.Acme.Domain) == 0 {
+		return nil
+	}
+	key := acmeKey(c.Acme)
+
+	acmeManagers.Lock()
+	defer acmeManagers.Unlock()
+	m, found := acmeManagers.m[key]
+	if !found {
+		directory := c.Acme.DirectoryUrl
+		if directory == "" {
+			directory = acme.LetsEncryptURL
+		}
+		m = &acmeManager{
+			key:        key,
+			config:     c.Acme,
+			storage:    acmeStorage(c.Acme),
+			client:     &acme.Client{DirectoryURL: directory},
+			httpTokens: make(map[string][]byte),
+			alpnCerts:  make(map[string]*tls.Certificate),
+		}
+		if err := m.load(); err != nil {
+			errors.LogInfoInner(context.Background(), err, "no stored ACME certificate of ", c.Acme.Domain)
+		}
+		var ctx context.Context
+		ctx, m.cancel = context.WithCancel(context.Background())
+		go m.run(ctx)
+		acmeManagers.m[key] = m
+	}
+	m.refs++
+	return &acmeHolder{manager: m}
+}
+
+// getACMEManager returns the started manager of config, or nil if there is
+// none.
+func getACMEManager(config *ACME) *acmeManager {
+	acmeManagers.Lock()
+	defer acmeManagers.Unlock()
+	return acmeManagers.m[acmeKey(config)]
+}
+
+// acmeHolder stops the manager when the last holder is closed.
+type acmeHolder struct {
+	manager *acmeManager
+	once    sync.Once
+}
+
+func (h *acmeHolder) Close() error {
+	h.once.Do(func() {
+		acmeManagers.Lock()
+		defer acmeManagers.Unlock()
+		m := h.manager
+		if m.refs--; m.refs == 0 {
+			m.cancel()
+			delete(acmeManagers.m, m.key)
+		}
+	})
+	return nil
+}
+
+// certPath returns the path of the certificate chain and its key, which are
+// stored in the same file so that they are replaced at once.
+func (m *acmeManager) certPath() string {
+	return filepath.Join(m.storage, m.config.Domain[0]+".pem")
+}
+
+// load loads the stored certificate.
+func (m *acmeManager) load() error {
+	data, err := os.ReadFile(m.certPath())
+	if err != nil {
+		return err
+	}
+	cert, err := tls.X509KeyPair(data, data)
+	if err != nil {
+		return err
+	}
+	for _, domain := range m.config.Domain {
+		if err := cert.Leaf.VerifyHostname(domain); err != nil {
+			return errors.New("stored certificate is not of the domains").Base(err)
+		}
+	}
+	m.access.Lock()
+	m.cert = &cert
+	m.access.Unlock()
+	return nil
+}
+
+func (m *acmeManager) certificate() *tls.Certificate {
+	m.access.RLock()
+	defer m.access.RUnlock()
+	return m.cert
+}
+
+// renewAt returns the time the certificate should be renewed at.
+func (m *acmeManager) renewAt() time.Time {
+	cert := m.certificate()
+	if cert == nil {
+		return time.Time{}
+	}
+	before := min(acmeRenewBefore, cert.Leaf.NotAfter.Sub(cert.Leaf.NotBefore)/3)
+	return cert.Leaf.NotAfter.Add(-before)
+}
+
+// run renews the certificate when due until ctx is done.
+func (m *acmeManager) run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		wait := acmeCheckInterval
+		if renewAt := m.renewAt(); time.Now().After(renewAt) {
+			obtainCtx, cancel := context.WithTimeout(ctx, acmeTimeout)
+			if err := m.obtain(obtainCtx); err != nil && ctx.Err() == nil {
+				errors.LogWarningInner(ctx, err, "failed to obtain ACME certificate of ", m.config.Domain)
+				wait = acmeRetryInterval
+			}
+			cancel()
+		} else {
+			wait = min(wait, time.Until(renewAt))
+		}
+		timer.Reset(wait)
+	}
+}
+
+// accountKey loads the stored account key, or generates one.
+func (m *acmeManager) accountKey() (crypto.Signer, error) {
+	path := filepath.Join(m.storage, "account.key")
+	if data, err := os.ReadFile(path); err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("invalid ACME account key ", path)
+		}
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := filesystem.WriteFileAtomicPerm(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return nil, errors.New("failed to store ACME account key").Base(err)
+	}
+	return key, nil
+}
+
+func (m *acmeManager) register(ctx context.Context) error {
+	if m.client.Key != nil {
+		return nil
+	}
+	if err := os.MkdirAll(m.storage, 0o700); err != nil {
+		return errors.New("failed to create ACME storage").Base(err)
+	}
+	key, err := m.accountKey()
+	if err != nil {
+		return err
+	}
+	m.client.Key = key
+	account := &acme.Account{}
+	if m.config.Email != "" {
+		account.Contact = []string{"mailto:" + m.config.Email}
+	}
+	if _, err := m.client.Register(ctx, account, acme.AcceptTOS); err != nil && err != acme.ErrAccountAlreadyExists {
+		m.client.Key = nil
+		return errors.New("failed to register ACME account").Base(err)
+	}
+	return nil
+}
+
+// obtain issues a certificate and stores it.
+func (m *acmeManager) obtain(ctx context.Context) error {
+	errors.LogInfo(ctx, "obtaining ACME certificate of ", m.config.Domain)
+	if err := m.register(ctx); err != nil {
+		return err
+	}
+	if m.config.Challenge == ACME_HTTP_01 {
+		server, err := m.serveHTTP()
+		if err != nil {
+			return err
+		}
+		defer server.Close()
+	}
+
+	order, err := m.client.AuthorizeOrder(ctx, acme.DomainIDs(m.config.Domain...))
+	if err != nil {
+		return errors.New("failed to create ACME order").Base(err)
+	}
+	for _, url := range order.AuthzURLs {
+		authz, err := m.client.GetAuthorization(ctx, url)
+		if err != nil {
+			return errors.New("failed to get ACME authorization").Base(err)
+		}
+		if authz.Status == acme.StatusValid {
+			continue
+		}
+		if err := m.authorize(ctx, authz); err != nil {
+			return errors.New("failed to authorize ", authz.Identifier.Value).Base(err)
+		}
+	}
+	if order, err = m.client.WaitOrder(ctx, order.URI); err != nil {
+		return errors.New("failed to wait ACME order").Base(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: m.config.Domain[0]},
+		DNSNames: m.config.Domain,
+	}, key)
+	if err != nil {
+		return err
+	}
+	chain, _, err := m.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return errors.New("failed to finalize ACME order").Base(err)
+	}
+
+	var data []byte
+	for _, der := range chain {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})...)
+	if err := filesystem.WriteFileAtomicPerm(m.certPath(), data, 0o600); err != nil {
+		return errors.New("failed to store ACME certificate").Base(err)
+	}
+	if err := m.load(); err != nil {
+		return err
+	}
+	errors.LogInfo(ctx, "obtained ACME certificate of ", m.config.Domain)
+	return nil
+}
+
+// authorize fulfils a challenge of the authorization.
+func (m *acmeManager) authorize(ctx context.Context, authz *acme.Authorization) error {
+	challengeType := map[ACME_Challenge]string{
+		ACME_HTTP_01:     "http-01",
+		ACME_TLS_ALPN_01: "tls-alpn-01",
+		ACME_DNS_01:      "dns-01",
+	}[m.config.Challenge]
+	var challenge *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == challengeType {
+			challenge = c
+			break
+		}
+	}
+	if challenge == nil {
+		return errors.New("no ", challengeType, " challenge offered")
+	}
+
+	domain := authz.Identifier.Value
+	switch m.config.Challenge {
+	case ACME_HTTP_01:
+		body, err := m.client.HTTP01ChallengeResponse(challenge.Token)
+		if err != nil {
+			return err
+		}
+		path := m.client.HTTP01ChallengePath(challenge.Token)
+		m.access.Lock()
+		m.httpTokens[path] = []byte(body)
+		m.access.Unlock()
+		defer func() {
+			m.access.Lock()
+			delete(m.httpTokens, path)
+			m.access.Unlock()
+		}()
+	case ACME_TLS_ALPN_01:
+		cert, err := m.client.TLSALPN01ChallengeCert(challenge.Token, domain)
+		if err != nil {
+			return err
+		}
+		m.access.Lock()
+		m.alpnCerts[domain] = &cert
+		m.access.Unlock()
+		defer func() {
+			m.access.Lock()
+			delete(m.alpnCerts, domain)
+			m.access.Unlock()
+		}()
+	case ACME_DNS_01:
+		value, err := m.client.DNS01ChallengeRecord(challenge.Token)
+		if err != nil {
+			return err
+		}
+		name := "_acme-challenge." + strings.TrimPrefix(domain, "*.")
+		if err := m.runDNSHook(ctx, "present", name, value); err != nil {
+			return err
+		}
+		defer func() {
+			if err := m.runDNSHook(context.Background(), "cleanup", name, value); err != nil {
+				errors.LogWarningInner(ctx, err, "failed to clean up DNS-01 challenge")
+			}
+		}()
+	}
+
+	if _, err := m.client.Accept(ctx, challenge); err != nil {
+		return err
+	}
+	_, err := m.client.WaitAuthorization(ctx, authz.URI)
+	return err
+}
+
+func (m *acmeManager) runDNSHook(ctx context.Context, action, name, value string) error {
+	if m.config.DnsHook == "" {
+		return errors.New("no DNS hook of DNS-01 challenges")
+	}
+	output, err := exec.CommandContext(ctx, m.config.DnsHook, action, name, value).CombinedOutput()
+	if err != nil {
+		return errors.New("DNS hook failed: ", strings.TrimSpace(string(output))).Base(err)
+	}
+	return nil
+}
+
+// serveHTTP serves HTTP-01 challenges on the HTTP address, to which the
+// inbound on port 80 falls back, until the returned server is closed.
+func (m *acmeManager) serveHTTP() (*http.Server, error) {
+	address := m.config.HttpAddress
+	if address == "" {
+		return nil, errors.New("no HTTP address of HTTP-01 challenges")
+	}
+	network := "tcp"
+	if strings.HasPrefix(address, "/") || strings.HasPrefix(address, "@") {
+		network = "unix"
+		if address[0] == '/' {
+			os.Remove(address)
+		}
+	}
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, errors.New("failed to listen for HTTP-01 challenges").Base(err)
+	}
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m.access.RLock()
+			body, found := m.httpTokens[r.URL.Path]
+			m.access.RUnlock()
+			if !found {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(body)
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go server.Serve(listener)
+	return server, nil
+}
+
+// getCertificateFunc returns the ACME certificate, or the ones of fallback
+// before it is obtained.
+func (m *acmeManager) getCertificateFunc(fallback func(*tls.ClientHelloInfo) (*tls.Certificate, error)) func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if len(hello.SupportedProtos) == 1 && hello.SupportedProtos[0] == acme.ALPNProto {
+			m.access.RLock()
+			cert := m.alpnCerts[strings.ToLower(hello.ServerName)]
+			m.access.RUnlock()
+			if cert == nil {
+				return nil, errors.New("no TLS-ALPN-01 challenge of ", hello.ServerName)
+			}
+			return cert, nil
+		}
+		if cert := m.certificate(); cert != nil {
+			return cert, nil
+		}
+		if fallback != nil {
+			return fallback(hello)
+		}
+		return nil, errors.New("ACME certificate of ", m.config.Domain, " is not obtained yet")
+	}
+}
@@ -0,0 +1,218 @@
+package tls_test
+
+import (
+	"crypto/rand"
+	gotls "crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol/tls/cert"
+	"github.com/xtls/xray-core/testing/servers/tcp"
+	. "github.com/xtls/xray-core/transport/internet/tls"
+)
+
+// fakeACME is an ACME server validating HTTP-01 challenges at httpAddress
+// and issuing certificates by ca, without verifying request signatures.
+type fakeACME struct {
+	*httptest.Server
+	ca          *cert.Certificate
+	httpAddress string
+
+	access sync.Mutex
+	valid  bool
+	issued []byte
+}
+
+func newFakeACME(t *testing.T, httpAddress string) *fakeACME {
+	s := &fakeACME{
+		ca:          cert.MustGenerate(nil, cert.Authority(true), cert.KeyUsage(x509.KeyUsageCertSign)),
+		httpAddress: httpAddress,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeACME) payload(r *http.Request) []byte {
+	var jws struct {
+		Payload string `json:"payload"`
+	}
+	common.Must(json.NewDecoder(r.Body).Decode(&jws))
+	payload, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	common.Must(err)
+	return payload
+}
+
+func (s *fakeACME) reply(w http.ResponseWriter, status int, location string, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if location != "" {
+		w.Header().Set("Location", s.URL+location)
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (s *fakeACME) order() map[string]interface{} {
+	s.access.Lock()
+	defer s.access.Unlock()
+	order := map[string]interface{}{
+		"status":         "pending",
+		"identifiers":    []map[string]string{{"type": "dns", "value": "example.com"}},
+		"authorizations": []string{s.URL + "/authz"},
+		"finalize":       s.URL + "/finalize",
+	}
+	switch {
+	case s.issued != nil:
+		order["status"] = "valid"
+		order["certificate"] = s.URL + "/cert"
+	case s.valid:
+		order["status"] = "ready"
+	}
+	return order
+}
+
+func (s *fakeACME) authz() map[string]interface{} {
+	s.access.Lock()
+	defer s.access.Unlock()
+	status := "pending"
+	if s.valid {
+		status = "valid"
+	}
+	return map[string]interface{}{
+		"status":     status,
+		"identifier": map[string]string{"type": "dns", "value": "example.com"},
+		"challenges": []map[string]string{{"type": "http-01", "url": s.URL + "/challenge", "token": "token", "status": status}},
+	}
+}
+
+func (s *fakeACME) handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Replay-Nonce", "nonce")
+	switch r.URL.Path {
+	case "/directory":
+		s.reply(w, http.StatusOK, "", map[string]string{
+			"newNonce":   s.URL + "/nonce",
+			"newAccount": s.URL + "/account",
+			"newOrder":   s.URL + "/order",
+		})
+	case "/nonce":
+		w.WriteHeader(http.StatusOK)
+	case "/account":
+		s.payload(r)
+		s.reply(w, http.StatusCreated, "/account/1", map[string]string{"status": "valid"})
+	case "/order":
+		s.payload(r)
+		s.reply(w, http.StatusCreated, "/order/1", s.order())
+	case "/order/1":
+		s.reply(w, http.StatusOK, "", s.order())
+	case "/authz":
+		s.reply(w, http.StatusOK, "", s.authz())
+	case "/challenge":
+		s.payload(r)
+		resp, err := http.Get("http://" + s.httpAddress + "/.well-known/acme-challenge/token")
+		if err == nil {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			s.access.Lock()
+			s.valid = strings.HasPrefix(string(body), "token.")
+			s.access.Unlock()
+		}
+		s.reply(w, http.StatusOK, "", map[string]string{"type": "http-01", "url": s.URL + "/challenge", "token": "token", "status": "processing"})
+	case "/finalize":
+		var request struct {
+			CSR string `json:"csr"`
+		}
+		common.Must(json.Unmarshal(s.payload(r), &request))
+		der, err := base64.RawURLEncoding.DecodeString(request.CSR)
+		common.Must(err)
+		csr, err := x509.ParseCertificateRequest(der)
+		common.Must(err)
+		caCert, err := x509.ParseCertificate(s.ca.Certificate)
+		common.Must(err)
+		caKey, err := x509.ParsePKCS8PrivateKey(s.ca.PrivateKey)
+		common.Must(err)
+		certDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			DNSNames:     csr.DNSNames,
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}, caCert, csr.PublicKey, caKey)
+		common.Must(err)
+		s.access.Lock()
+		s.issued = certDER
+		s.access.Unlock()
+		s.reply(w, http.StatusOK, "/order/1", s.order())
+	case "/cert":
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		s.access.Lock()
+		w.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.issued}))
+		s.access.Unlock()
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestACME(t *testing.T) {
+	httpAddress := net.LocalHostIP.String() + ":" + tcp.PickPort().String()
+	server := newFakeACME(t, httpAddress)
+	storage := t.TempDir()
+
+	config := &Config{
+		Acme: &ACME{
+			Domain:       []string{"example.com"},
+			DirectoryUrl: server.URL + "/directory",
+			StoragePath:  storage,
+			Challenge:    ACME_HTTP_01,
+			HttpAddress:  httpAddress,
+		},
+	}
+	acme := config.StartACME()
+	tlsConfig := config.GetTLSConfig()
+
+	var certificate *gotls.Certificate
+	for i := 0; i < 100; i++ {
+		var err error
+		if certificate, err = tlsConfig.GetCertificate(&gotls.ClientHelloInfo{ServerName: "example.com"}); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if certificate == nil {
+		t.Fatal("ACME certificate is not obtained")
+	}
+	if err := certificate.Leaf.VerifyHostname("example.com"); err != nil {
+		t.Error(err)
+	}
+
+	for _, file := range []string{"account.key", "example.com.pem"} {
+		if _, err := os.Stat(filepath.Join(storage, file)); err != nil {
+			t.Error(err)
+		}
+	}
+
+	common.Must(acme.Close())
+	if _, err := config.GetTLSConfig().GetCertificate(&gotls.ClientHelloInfo{ServerName: "example.com"}); err == nil {
+		t.Error("expected no ACME certificate after ACME is closed")
+	}
+
+	// the stored certificate is loaded when started again
+	acme = config.StartACME()
+	defer acme.Close()
+	if _, err := config.GetTLSConfig().GetCertificate(&gotls.ClientHelloInfo{ServerName: "example.com"}); err != nil {
+		t.Error(err)
+	}
+}
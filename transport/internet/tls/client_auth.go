@@ -0,0 +1,89 @@
+package tls
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"strings"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/protocol"
+)
+
+// getClientCertPool returns the pool of certificates to verify client
+// certificates with.
+func (c *Config) getClientCertPool() *x509.CertPool {
+	var pool *x509.CertPool
+	for _, cert := range c.Certificate {
+		if cert.Usage != Certificate_AUTHORITY_VERIFY_CLIENT {
+			continue
+		}
+		if pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(cert.Certificate) {
+			errors.LogWarning(context.Background(), "failed to append client CA certificate")
+		}
+	}
+	return pool
+}
+
+// match checks whether the client certificate is of the user, in which the
+// subject and the SAN are only matched if the certificate is verified, as
+// anyone may issue a certificate of them otherwise.
+func (u *ClientUser) match(cert *x509.Certificate, verified bool) bool {
+	if verified && u.Subject != "" && (u.Subject == cert.Subject.CommonName || u.Subject == cert.Subject.String()) {
+		return true
+	}
+	if verified && u.San != "" {
+		for _, name := range cert.DNSNames {
+			if strings.EqualFold(u.San, name) {
+				return true
+			}
+		}
+		for _, email := range cert.EmailAddresses {
+			if strings.EqualFold(u.San, email) {
+				return true
+			}
+		}
+		for _, ip := range cert.IPAddresses {
+			if u.San == ip.String() {
+				return true
+			}
+		}
+		for _, uri := range cert.URIs {
+			if u.San == uri.String() {
+				return true
+			}
+		}
+	}
+	if len(u.Fingerprint) > 0 {
+		fingerprint := sha256.Sum256(cert.Raw)
+		if subtle.ConstantTimeCompare(u.Fingerprint, fingerprint[:]) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientUserOf returns the user the client certificate of a handshaken
+// connection is mapped to, or nil if there is none.
+func (c *Config) ClientUserOf(state tls.ConnectionState) *protocol.MemoryUser {
+	if c == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	cert := state.PeerCertificates[0]
+	verified := len(state.VerifiedChains) > 0
+	for _, user := range c.ClientUser {
+		if user.match(cert, verified) {
+			// a copy so that the inbound may change it for the connection
+			return &protocol.MemoryUser{
+				Email: user.Email,
+				Level: user.Level,
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package tls_test
+
+import (
+	"crypto/sha256"
+	gotls "crypto/tls"
+	"crypto/x509"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/protocol/tls/cert"
+	. "github.com/xtls/xray-core/transport/internet/tls"
+)
+
+func handshakeClientCert(t *testing.T, server *Config, clientCert *cert.Certificate) (gotls.ConnectionState, error) {
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	result := make(chan error, 1)
+	var state gotls.ConnectionState
+	go func() {
+		conn := gotls.Server(serverConn, server.GetTLSConfig())
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		err := conn.Handshake()
+		state = conn.ConnectionState()
+		result <- err
+		conn.Close()
+	}()
+
+	config := &gotls.Config{InsecureSkipVerify: true}
+	if clientCert != nil {
+		certPEM, keyPEM := clientCert.ToPEM()
+		keyPair, err := gotls.X509KeyPair(certPEM, keyPEM)
+		common.Must(err)
+		config.Certificates = []gotls.Certificate{keyPair}
+	}
+	conn := gotls.Client(clientConn, config)
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	conn.Handshake()
+	// read to receive the alert of TLS 1.3 servers rejecting the certificate
+	conn.Read(make([]byte, 1))
+	err := <-result
+	return state, err
+}
+
+func TestClientAuth(t *testing.T) {
+	ca := cert.MustGenerate(nil, cert.Authority(true), cert.KeyUsage(x509.KeyUsageCertSign), cert.ExtKeyUsage())
+	caPEM, _ := ca.ToPEM()
+	clientCert := cert.MustGenerate(ca, cert.CommonName("alice"), cert.DNSNames("alice.example.com"), cert.ExtKeyUsage(x509.ExtKeyUsageClientAuth))
+	otherCert := cert.MustGenerate(ca, cert.CommonName("bob"), cert.ExtKeyUsage(x509.ExtKeyUsageClientAuth))
+	fingerprint := sha256.Sum256(otherCert.Certificate)
+
+	server := &Config{
+		Certificate: []*Certificate{
+			ParseCertificate(cert.MustGenerate(nil, cert.DNSNames("example.com"))),
+			{Certificate: caPEM, Usage: Certificate_AUTHORITY_VERIFY_CLIENT},
+		},
+		ClientAuth: ClientAuth_RequireAndVerifyClientCert,
+		ClientUser: []*ClientUser{
+			{Email: "alice@example.com", Level: 1, San: "alice.example.com"},
+			{Email: "bob@example.com", Fingerprint: fingerprint[:]},
+		},
+	}
+
+	state, err := handshakeClientCert(t, server, clientCert)
+	common.Must(err)
+	if user := server.ClientUserOf(state); user == nil || user.Email != "alice@example.com" || user.Level != 1 {
+		t.Error("unexpected user ", user)
+	}
+
+	state, err = handshakeClientCert(t, server, otherCert)
+	common.Must(err)
+	if user := server.ClientUserOf(state); user == nil || user.Email != "bob@example.com" {
+		t.Error("unexpected user ", user)
+	}
+
+	if _, err := handshakeClientCert(t, server, nil); err == nil {
+		t.Error("expected handshake failure without client certificate")
+	}
+
+	untrusted := cert.MustGenerate(nil, cert.CommonName("alice"), cert.ExtKeyUsage(x509.ExtKeyUsageClientAuth))
+	if _, err := handshakeClientCert(t, server, untrusted); err == nil {
+		t.Error("expected handshake failure with untrusted client certificate")
+	}
+}
+
+func TestClientAuthUnverified(t *testing.T) {
+	selfSigned := cert.MustGenerate(nil, cert.CommonName("alice"), cert.DNSNames("alice.example.com"), cert.ExtKeyUsage(x509.ExtKeyUsageClientAuth))
+	other := cert.MustGenerate(nil, cert.CommonName("bob"), cert.ExtKeyUsage(x509.ExtKeyUsageClientAuth))
+	fingerprint := sha256.Sum256(other.Certificate)
+
+	server := &Config{
+		Certificate: []*Certificate{
+			ParseCertificate(cert.MustGenerate(nil, cert.DNSNames("example.com"))),
+		},
+		ClientAuth: ClientAuth_RequireAnyClientCert,
+		ClientUser: []*ClientUser{
+			{Email: "alice@example.com", Subject: "alice"},
+			{Email: "alice@example.com", San: "alice.example.com"},
+			{Email: "bob@example.com", Fingerprint: fingerprint[:]},
+		},
+	}
+
+	state, err := handshakeClientCert(t, server, selfSigned)
+	common.Must(err)
+	if user := server.ClientUserOf(state); user != nil {
+		t.Error("unexpected user of unverified subject and SAN ", user)
+	}
+
+	state, err = handshakeClientCert(t, server, other)
+	common.Must(err)
+	if user := server.ClientUserOf(state); user == nil || user.Email != "bob@example.com" {
+		t.Error("unexpected user ", user)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/xtls/xray-core/common/platform/filesystem"
 	"github.com/xtls/xray-core/common/protocol/tls/cert"
 	"github.com/xtls/xray-core/transport/internet"
+	"golang.org/x/crypto/acme"
 )
 
 var globalSessionCache = tls.NewLRUClientSessionCache(128)
@@ -329,8 +330,9 @@ func (c *Config) GetTLSConfig(opts ...Option) *tls.Config {
 		VerifyPeerCertificate:  c.verifyPeerCert,
 	}
 
+	o := &options{Config: config, ctx: context.Background()}
 	for _, opt := range opts {
-		opt(config)
+		opt(o)
 	}
 
 	caCerts := c.getCustomCA()
@@ -352,6 +354,17 @@ func (c *Config) GetTLSConfig(opts ...Option) *tls.Config {
 		config.NextProtos = []string{"h2", "http/1.1"}
 	}
 
+	if c.Acme != nil && len(c.Acme.Domain) > 0 {
+		if m := getACMEManager(c.Acme); m != nil {
+			config.GetCertificate = m.getCertificateFunc(config.GetCertificate)
+			if c.Acme.Challenge == ACME_TLS_ALPN_01 {
+				config.NextProtos = append(config.NextProtos, acme.ALPNProto)
+			}
+		} else {
+			errors.LogWarning(context.Background(), "ACME of ", c.Acme.Domain, " is not started")
+		}
+	}
+
 	switch c.MinVersion {
 	case "1.0":
 		config.MinVersion = tls.VersionTLS10
@@ -386,6 +399,18 @@ func (c *Config) GetTLSConfig(opts ...Option) *tls.Config {
 		}
 	}
 
+	if len(c.EchConfigList) > 0 || c.EchDnsName != "" {
+		c.applyECHConfig(o.ctx, config)
+	}
+	if len(c.EchServerKeys) > 0 || c.EchServerKeysPath != "" {
+		config.GetEncryptedClientHelloKeys = c.newECHServerKeys().get
+	}
+
+	if c.ClientAuth != ClientAuth_NoClientCert {
+		config.ClientAuth = tls.ClientAuthType(c.ClientAuth)
+		config.ClientCAs = c.getClientCertPool()
+	}
+
 	if len(c.MasterKeyLog) > 0 && c.MasterKeyLog != "none" {
 		writer, err := os.OpenFile(c.MasterKeyLog, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
 		if err != nil {
@@ -399,14 +424,27 @@ func (c *Config) GetTLSConfig(opts ...Option) *tls.Config {
 }
 
 // Option for building TLS config.
-type Option func(*tls.Config)
+type Option func(*options)
+
+type options struct {
+	*tls.Config
+	ctx context.Context
+}
+
+// WithContext sets the context of the connection, with the DNS client of whose
+// Xray instance ECH configs are looked up.
+func WithContext(ctx context.Context) Option {
+	return func(config *options) {
+		config.ctx = ctx
+	}
+}
 
 // WithDestination sets the server name in TLS config.
 // Due to the incorrect structure of GetTLSConfig(), the config.ServerName will always be empty.
 // So the real logic for SNI is:
 // set it to dest -> overwrite it with servername(if it's len>0).
 func WithDestination(dest net.Destination) Option {
-	return func(config *tls.Config) {
+	return func(config *options) {
 		if config.ServerName == "" {
 			config.ServerName = dest.Address.String()
 		}
@@ -415,7 +453,7 @@ func WithDestination(dest net.Destination) Option {
 
 // WithNextProto sets the ALPN values in TLS config.
 func WithNextProto(protocol ...string) Option {
-	return func(config *tls.Config) {
+	return func(config *options) {
 		if len(config.NextProtos) == 0 {
 			config.NextProtos = protocol
 		}
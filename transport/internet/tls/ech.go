@@ -0,0 +1,201 @@
+package tls
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/platform/filesystem"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/dns"
+	"golang.org/x/crypto/cryptobyte"
+)
+
+const (
+	// echKeysReloadInterval is the interval ECH key files are checked for changes.
+	echKeysReloadInterval = time.Minute
+	// echConfigMinTTL and echConfigMaxTTL bound the time ECH configs looked up are cached.
+	echConfigMinTTL = time.Minute
+	echConfigMaxTTL = time.Hour
+)
+
+var (
+	echConfigAccess sync.Mutex
+	echConfigCache  = make(map[string]*echConfigEntry)
+)
+
+type echConfigEntry struct {
+	config []byte
+	expire time.Time
+}
+
+// cacheECHConfig caches the config of domain for ttl, and evicts the expired
+// configs of other domains.
+func cacheECHConfig(domain string, config []byte, ttl time.Duration) {
+	now := time.Now()
+	echConfigAccess.Lock()
+	defer echConfigAccess.Unlock()
+	for d, entry := range echConfigCache {
+		if !entry.expire.After(now) {
+			delete(echConfigCache, d)
+		}
+	}
+	echConfigCache[domain] = &echConfigEntry{config: config, expire: now.Add(ttl)}
+}
+
+// cachedECHConfig returns the cached config of domain, or nil if expired.
+func cachedECHConfig(domain string) []byte {
+	echConfigAccess.Lock()
+	defer echConfigAccess.Unlock()
+	entry, found := echConfigCache[domain]
+	if !found {
+		return nil
+	}
+	if !entry.expire.After(time.Now()) {
+		delete(echConfigCache, domain)
+		return nil
+	}
+	return entry.config
+}
+
+// lookupECHConfig returns the ECHConfigList in the DNS HTTPS record of domain,
+// looked up with the DNS client of the Xray instance in ctx.
+func lookupECHConfig(ctx context.Context, domain string) ([]byte, error) {
+	if config := cachedECHConfig(domain); config != nil {
+		return config, nil
+	}
+
+	var r dns.ECHConfigResolver
+	if v := core.FromContext(ctx); v != nil {
+		r, _ = v.GetFeature(dns.ClientType()).(dns.ECHConfigResolver)
+	}
+	if r == nil {
+		return nil, errors.New("no DNS resolver of ECH configs")
+	}
+	config, ttl, err := r.LookupECHConfig(domain)
+	if err != nil {
+		return nil, errors.New("failed to lookup ECH configs of ", domain).Base(err)
+	}
+	if len(config) == 0 {
+		return nil, errors.New("no ECH configs in HTTPS record of ", domain)
+	}
+	cacheECHConfig(domain, config, min(max(ttl, echConfigMinTTL), echConfigMaxTTL))
+	return config, nil
+}
+
+// applyECHConfig sets the ECHConfigList clients encrypt the ClientHello with.
+func (c *Config) applyECHConfig(ctx context.Context, config *tls.Config) {
+	list := c.EchConfigList
+	if len(list) == 0 {
+		var err error
+		if list, err = lookupECHConfig(ctx, c.EchDnsName); err != nil {
+			errors.LogErrorInner(ctx, err, "ECH is not available")
+			// an empty list fails the handshake instead of leaking the server name
+			list = []byte{0, 0}
+		}
+	}
+	config.EncryptedClientHelloConfigList = list
+	config.MinVersion = tls.VersionTLS13
+}
+
+// parseECHKeys parses ECH key sets, each of which is a private key followed by
+// an ECHConfig, both prefixed with their uint16 lengths. Key sets in PEM are
+// in "ECH KEYS" blocks.
+func parseECHKeys(data []byte) ([]tls.EncryptedClientHelloKey, error) {
+	raw := data
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		raw = nil
+		for {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+			if block.Type == "ECH KEYS" {
+				raw = append(raw, block.Bytes...)
+			}
+		}
+	}
+
+	var keys []tls.EncryptedClientHelloKey
+	s := cryptobyte.String(raw)
+	for !s.Empty() {
+		var sk, config cryptobyte.String
+		if !s.ReadUint16LengthPrefixed(&sk) || !s.ReadUint16LengthPrefixed(&config) {
+			return nil, errors.New("invalid ECH key set")
+		}
+		keys = append(keys, tls.EncryptedClientHelloKey{
+			Config:      config,
+			PrivateKey:  sk,
+			SendAsRetry: len(keys) == 0,
+		})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no ECH key set")
+	}
+	return keys, nil
+}
+
+// echServerKeys are the ECH keys of a server, reloaded from the file if any.
+type echServerKeys struct {
+	access  sync.Mutex
+	path    string
+	keys    []tls.EncryptedClientHelloKey
+	modTime time.Time
+	checked time.Time
+}
+
+func (c *Config) newECHServerKeys() *echServerKeys {
+	k := &echServerKeys{path: c.EchServerKeysPath}
+	if k.path != "" {
+		k.reload()
+		return k
+	}
+	keys, err := parseECHKeys(c.EchServerKeys)
+	if err != nil {
+		errors.LogErrorInner(context.Background(), err, "failed to load ECH keys")
+	}
+	k.keys = keys
+	return k
+}
+
+func (k *echServerKeys) reload() {
+	k.checked = time.Now()
+	info, err := os.Stat(k.path)
+	if err != nil {
+		errors.LogErrorInner(context.Background(), err, "failed to load ECH keys from ", k.path)
+		return
+	}
+	if info.ModTime().Equal(k.modTime) {
+		return
+	}
+	data, err := filesystem.ReadFile(k.path)
+	if err == nil {
+		var keys []tls.EncryptedClientHelloKey
+		if keys, err = parseECHKeys(data); err == nil {
+			k.keys = keys
+			k.modTime = info.ModTime()
+			errors.LogInfo(context.Background(), "loaded ", len(keys), " ECH keys from ", k.path)
+			return
+		}
+	}
+	errors.LogErrorInner(context.Background(), err, "failed to load ECH keys from ", k.path)
+}
+
+// get implements tls.Config.GetEncryptedClientHelloKeys.
+func (k *echServerKeys) get(*tls.ClientHelloInfo) ([]tls.EncryptedClientHelloKey, error) {
+	k.access.Lock()
+	defer k.access.Unlock()
+	if k.path != "" && time.Since(k.checked) >= echKeysReloadInterval {
+		k.reload()
+	}
+	if len(k.keys) == 0 {
+		return nil, errors.New("no ECH keys")
+	}
+	return k.keys, nil
+}
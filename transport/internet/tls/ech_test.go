@@ -0,0 +1,137 @@
+package tls_test
+
+import (
+	"context"
+	gotls "crypto/tls"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OmarTariq612/goech"
+	"github.com/cloudflare/circl/hpke"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/protocol/tls/cert"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/dns"
+	. "github.com/xtls/xray-core/transport/internet/tls"
+)
+
+func generateECHKeys(t *testing.T) (configList, keys []byte) {
+	keySet, err := goech.GenerateECHKeySet(0, "public.example.com", hpke.KEM_X25519_HKDF_SHA256)
+	common.Must(err)
+	configList, err = keySet.ECHConfig.MarshalBinary()
+	common.Must(err)
+	keys, err = keySet.MarshalBinary()
+	common.Must(err)
+	return configList, keys
+}
+
+func handshakeECH(t *testing.T, server, client *Config, opts ...Option) (gotls.ConnectionState, error) {
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	go func() {
+		conn := gotls.Server(serverConn, server.GetTLSConfig())
+		conn.Handshake()
+		conn.Close()
+	}()
+
+	conn := gotls.Client(clientConn, client.GetTLSConfig(opts...))
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	err := conn.Handshake()
+	return conn.ConnectionState(), err
+}
+
+type stubECHResolver struct {
+	configList []byte
+}
+
+func (*stubECHResolver) Type() interface{} {
+	return dns.ClientType()
+}
+
+func (*stubECHResolver) Start() error {
+	return nil
+}
+
+func (*stubECHResolver) Close() error {
+	return nil
+}
+
+func (*stubECHResolver) LookupIP(domain string, option dns.IPOption) ([]net.IP, error) {
+	return nil, dns.ErrEmptyResponse
+}
+
+func (r *stubECHResolver) LookupECHConfig(domain string) ([]byte, time.Duration, error) {
+	return r.configList, time.Minute, nil
+}
+
+func TestECH(t *testing.T) {
+	configList, keys := generateECHKeys(t)
+	certificate := ParseCertificate(cert.MustGenerate(nil, cert.DNSNames("public.example.com", "secret.example.com")))
+	server := &Config{
+		Certificate:   []*Certificate{certificate},
+		EchServerKeys: keys,
+	}
+
+	state, err := handshakeECH(t, server, &Config{
+		AllowInsecure: true,
+		ServerName:    "secret.example.com",
+		EchConfigList: configList,
+	})
+	common.Must(err)
+	if !state.ECHAccepted {
+		t.Error("expected ECH accepted")
+	}
+
+	instance := new(core.Instance)
+	common.Must(instance.AddFeature(&stubECHResolver{configList: configList}))
+	ctx := context.WithValue(context.Background(), core.XrayKey(1), instance)
+	state, err = handshakeECH(t, server, &Config{
+		AllowInsecure: true,
+		ServerName:    "secret.example.com",
+		EchDnsName:    "secret.example.com",
+	}, WithContext(ctx))
+	common.Must(err)
+	if !state.ECHAccepted {
+		t.Error("expected ECH accepted with configs looked up")
+	}
+
+	if _, err := handshakeECH(t, server, &Config{
+		AllowInsecure: true,
+		ServerName:    "secret.example.com",
+		EchDnsName:    "unknown.example.com",
+	}); err == nil {
+		t.Error("expected handshake failure without ECH configs")
+	}
+}
+
+func TestECHServerKeysFile(t *testing.T) {
+	oldConfigList, oldKeys := generateECHKeys(t)
+	newConfigList, newKeys := generateECHKeys(t)
+	path := filepath.Join(t.TempDir(), "ech.pem")
+	keysPEM := append(pem.EncodeToMemory(&pem.Block{Type: "ECH KEYS", Bytes: newKeys}), pem.EncodeToMemory(&pem.Block{Type: "ECH KEYS", Bytes: oldKeys})...)
+	common.Must(os.WriteFile(path, keysPEM, 0o600))
+
+	certificate := ParseCertificate(cert.MustGenerate(nil, cert.DNSNames("public.example.com", "secret.example.com")))
+	server := &Config{
+		Certificate:       []*Certificate{certificate},
+		EchServerKeysPath: path,
+	}
+
+	// both keys are accepted while rotating
+	for _, configList := range [][]byte{oldConfigList, newConfigList} {
+		state, err := handshakeECH(t, server, &Config{
+			AllowInsecure: true,
+			ServerName:    "secret.example.com",
+			EchConfigList: configList,
+		})
+		common.Must(err)
+		if !state.ECHAccepted {
+			t.Error("expected ECH accepted")
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package udp
+
+import (
+	"sync/atomic"
+
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/net"
+	"golang.org/x/net/ipv4"
+)
+
+const (
+	// batchSize is the maximum number of messages in a batch.
+	batchSize = 16
+	// maxGSOSegments is the maximum number of segments of a GSO message.
+	maxGSOSegments = 64
+	// maxGSOSize is the maximum size of a GSO message, or a GRO one.
+	maxGSOSize = 65507
+)
+
+// batchPacketConn is implemented by ipv4.PacketConn and ipv6.PacketConn.
+type batchPacketConn interface {
+	ReadBatch(ms []ipv4.Message, flags int) (int, error)
+	WriteBatch(ms []ipv4.Message, flags int) (int, error)
+}
+
+// BatchConn reads and writes packets of a UDP socket in batches, by
+// recvmmsg and sendmmsg with UDP GRO and GSO if the kernel supports them, or
+// one by one otherwise.
+type BatchConn struct {
+	conn  *net.UDPConn
+	batch batchPacketConn
+	// ipv6 is whether the socket is an IPv6 one, to which IPv4 packets are
+	// written one by one as they can not be batched.
+	ipv6 bool
+	gro  bool
+	gso  atomic.Bool
+
+	readMsgs []ipv4.Message
+}
+
+// NewBatchConn returns a BatchConn of conn, which enables GRO if gro and
+// supported, in which case each read takes more memory for a batch.
+func NewBatchConn(conn *net.UDPConn, gro bool) *BatchConn {
+	c := &BatchConn{
+		conn: conn,
+	}
+	setupBatchConn(c, gro)
+	return c
+}
+
+// ReadMultiBuffer reads a batch of packets, with their sources as UDP of the
+// buffers.
+func (c *BatchConn) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	if c.batch == nil {
+		b := buf.New()
+		n, addr, err := c.conn.ReadFromUDP(b.Extend(buf.Size))
+		if err != nil {
+			b.Release()
+			return nil, err
+		}
+		b.Resize(0, int32(n))
+		b.UDP = &net.Destination{
+			Address: net.IPAddress(addr.IP),
+			Port:    net.Port(addr.Port),
+			Network: net.Network_UDP,
+		}
+		return buf.MultiBuffer{b}, nil
+	}
+
+	if c.readMsgs == nil {
+		size := buf.Size
+		if c.gro {
+			size = maxGSOSize
+		}
+		c.readMsgs = make([]ipv4.Message, batchSize)
+		for i := range c.readMsgs {
+			c.readMsgs[i].Buffers = [][]byte{make([]byte, size)}
+			c.readMsgs[i].OOB = make([]byte, 0, 64)
+		}
+	}
+	for i := range c.readMsgs {
+		c.readMsgs[i].OOB = c.readMsgs[i].OOB[:cap(c.readMsgs[i].OOB)]
+	}
+	n, err := c.batch.ReadBatch(c.readMsgs, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var mb buf.MultiBuffer
+	for _, msg := range c.readMsgs[:n] {
+		addr, ok := msg.Addr.(*net.UDPAddr)
+		if !ok {
+			continue
+		}
+		dest := net.UDPDestination(net.IPAddress(addr.IP), net.Port(addr.Port))
+		data := msg.Buffers[0][:msg.N]
+		segmentSize := len(data)
+		if size := groSegmentSize(msg.OOB[:msg.NN]); size > 0 {
+			segmentSize = size
+		}
+		for len(data) > 0 {
+			size := min(segmentSize, len(data))
+			var b *buf.Buffer
+			if size > buf.Size {
+				b = buf.NewWithSize(int32(size))
+			} else {
+				b = buf.New()
+			}
+			b.Write(data[:size])
+			b.UDP = &dest
+			mb = append(mb, b)
+			data = data[size:]
+		}
+	}
+	return mb, nil
+}
+
+func sameUDPAddr(a, b *net.UDPAddr) bool {
+	return a.Port == b.Port && a.IP.Equal(b.IP) && a.Zone == b.Zone
+}
+
+// WriteBatch writes each of payloads to the address of the same index, in
+// which consecutive payloads of the same size to the same address are sent
+// as one message by GSO.
+func (c *BatchConn) WriteBatch(payloads [][]byte, addrs []*net.UDPAddr) error {
+	if c.batch == nil {
+		for i, payload := range payloads {
+			if _, err := c.conn.WriteToUDP(payload, addrs[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	gso := c.gso.Load()
+	msgs := make([]ipv4.Message, 0, min(len(payloads), batchSize))
+	for i := 0; i < len(payloads); {
+		addr := addrs[i]
+		if c.ipv6 && addr.IP.To4() != nil {
+			if err := c.writeMessages(msgs); err != nil {
+				return err
+			}
+			msgs = msgs[:0]
+			if _, err := c.conn.WriteToUDP(payloads[i], addr); err != nil {
+				return err
+			}
+			i++
+			continue
+		}
+
+		j, size := i+1, len(payloads[i])
+		for gso && j < len(payloads) && j-i < maxGSOSegments && sameUDPAddr(addrs[j], addr) &&
+			len(payloads[j-1]) == len(payloads[i]) && len(payloads[j]) <= len(payloads[i]) && size+len(payloads[j]) <= maxGSOSize {
+			size += len(payloads[j])
+			j++
+		}
+		msg := ipv4.Message{
+			Buffers: payloads[i:j],
+			Addr:    addr,
+		}
+		if j-i > 1 {
+			msg.OOB = appendGSOSize(nil, len(payloads[i]))
+		}
+		msgs = append(msgs, msg)
+		i = j
+	}
+	return c.writeMessages(msgs)
+}
+
+func (c *BatchConn) writeMessages(msgs []ipv4.Message) error {
+	for len(msgs) > 0 {
+		n, err := c.batch.WriteBatch(msgs, 0)
+		if err != nil {
+			if !isGSOError(err) || !c.gso.CompareAndSwap(true, false) {
+				return err
+			}
+			// The device does not support GSO, so the rest are written
+			// one by one.
+			var payloads [][]byte
+			var addrs []*net.UDPAddr
+			for _, msg := range msgs[n:] {
+				for _, payload := range msg.Buffers {
+					payloads = append(payloads, payload)
+					addrs = append(addrs, msg.Addr.(*net.UDPAddr))
+				}
+			}
+			return c.WriteBatch(payloads, addrs)
+		}
+		msgs = msgs[n:]
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+//go:build linux
+// +build linux
+
+package udp
+
+import (
+	"encoding/binary"
+	"errors"
+	"unsafe"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+	"golang.org/x/sys/unix"
+)
+
+func setupBatchConn(c *BatchConn, gro bool) {
+	rawConn, err := c.conn.SyscallConn()
+	if err != nil {
+		return
+	}
+	var domain int
+	var gso bool
+	rawConn.Control(func(fd uintptr) {
+		if domain, err = unix.GetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_DOMAIN); err != nil {
+			return
+		}
+		_, errSegment := unix.GetsockoptInt(int(fd), unix.SOL_UDP, unix.UDP_SEGMENT)
+		gso = errSegment == nil
+		if gro {
+			gro = unix.SetsockoptInt(int(fd), unix.SOL_UDP, unix.UDP_GRO, 1) == nil
+		}
+	})
+	if err != nil {
+		return
+	}
+	switch domain {
+	case unix.AF_INET:
+		c.batch = ipv4.NewPacketConn(c.conn)
+	case unix.AF_INET6:
+		c.batch = ipv6.NewPacketConn(c.conn)
+		c.ipv6 = true
+	default:
+		return
+	}
+	c.gro = gro
+	c.gso.Store(gso)
+}
+
+// groSegmentSize returns the size of the segments coalesced by GRO in oob, or
+// 0 if not coalesced.
+func groSegmentSize(oob []byte) int {
+	if len(oob) == 0 {
+		return 0
+	}
+	msgs, err := unix.ParseSocketControlMessage(oob)
+	if err != nil {
+		return 0
+	}
+	for _, msg := range msgs {
+		if msg.Header.Level == unix.SOL_UDP && msg.Header.Type == unix.UDP_GRO && len(msg.Data) >= 4 {
+			return int(binary.NativeEndian.Uint32(msg.Data))
+		}
+	}
+	return 0
+}
+
+// appendGSOSize appends the control message of the GSO segment size to oob.
+func appendGSOSize(oob []byte, size int) []byte {
+	start := len(oob)
+	oob = append(oob, make([]byte, unix.CmsgSpace(2))...)
+	header := (*unix.Cmsghdr)(unsafe.Pointer(&oob[start]))
+	header.Level = unix.SOL_UDP
+	header.Type = unix.UDP_SEGMENT
+	header.SetLen(unix.CmsgLen(2))
+	binary.NativeEndian.PutUint16(oob[start+unix.CmsgLen(0):], uint16(size))
+	return oob
+}
+
+// isGSOError returns whether err is caused by the device not supporting GSO.
+func isGSOError(err error) bool {
+	return errors.Is(err, unix.EIO)
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+// +build !linux
+
+package udp
+
+func setupBatchConn(c *BatchConn, gro bool) {
+}
+
+func groSegmentSize(oob []byte) int {
+	return 0
+}
+
+func appendGSOSize(oob []byte, size int) []byte {
+	return oob
+}
+
+func isGSOError(err error) bool {
+	return false
+}
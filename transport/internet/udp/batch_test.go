@@ -0,0 +1,151 @@
+package udp_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/net"
+	. "github.com/xtls/xray-core/transport/internet/udp"
+)
+
+func listenLocalUDP() *net.UDPConn {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.LocalHostIP.IP()})
+	common.Must(err)
+	return conn
+}
+
+func TestBatchConn(t *testing.T) {
+	sender, receiver := listenLocalUDP(), listenLocalUDP()
+	defer sender.Close()
+	defer receiver.Close()
+
+	var payloads [][]byte
+	var addrs []*net.UDPAddr
+	for i := 0; i < 40; i++ {
+		size := 1200
+		if i%10 == 9 {
+			size = 100
+		}
+		payloads = append(payloads, bytes.Repeat([]byte{byte(i)}, size))
+		addrs = append(addrs, receiver.LocalAddr().(*net.UDPAddr))
+	}
+	common.Must(NewBatchConn(sender, false).WriteBatch(payloads, addrs))
+
+	reader := NewBatchConn(receiver, true)
+	receiver.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var received buf.MultiBuffer
+	for len(received) < len(payloads) {
+		mb, err := reader.ReadMultiBuffer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		received = append(received, mb...)
+	}
+	defer buf.ReleaseMulti(received)
+
+	for i, b := range received {
+		if !bytes.Equal(b.Bytes(), payloads[i]) {
+			t.Error("unexpected packet ", i, " of ", b.Len(), " bytes")
+		}
+		if b.UDP == nil || b.UDP.Port != net.Port(sender.LocalAddr().(*net.UDPAddr).Port) {
+			t.Error("unexpected source of packet ", i, ": ", b.UDP)
+		}
+	}
+}
+
+func benchmarkUDPWrite(b *testing.B, write func(conn *net.UDPConn, payloads [][]byte, addr *net.UDPAddr)) {
+	sender, receiver := listenLocalUDP(), listenLocalUDP()
+	defer sender.Close()
+	defer receiver.Close()
+	go func() {
+		reader := NewBatchConn(receiver, true)
+		for {
+			mb, err := reader.ReadMultiBuffer()
+			if err != nil {
+				return
+			}
+			buf.ReleaseMulti(mb)
+		}
+	}()
+
+	payloads := make([][]byte, 64)
+	for i := range payloads {
+		payloads[i] = make([]byte, 1200)
+	}
+	addr := receiver.LocalAddr().(*net.UDPAddr)
+	b.SetBytes(int64(len(payloads) * 1200))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		write(sender, payloads, addr)
+	}
+}
+
+func BenchmarkWriteToUDP(b *testing.B) {
+	benchmarkUDPWrite(b, func(conn *net.UDPConn, payloads [][]byte, addr *net.UDPAddr) {
+		for _, payload := range payloads {
+			common.Must2(conn.WriteToUDP(payload, addr))
+		}
+	})
+}
+
+func BenchmarkBatchConnWrite(b *testing.B) {
+	var batch *BatchConn
+	var addrs []*net.UDPAddr
+	benchmarkUDPWrite(b, func(conn *net.UDPConn, payloads [][]byte, addr *net.UDPAddr) {
+		if batch == nil {
+			batch = NewBatchConn(conn, false)
+			for range payloads {
+				addrs = append(addrs, addr)
+			}
+		}
+		common.Must(batch.WriteBatch(payloads, addrs))
+	})
+}
+
+func benchmarkUDPRead(b *testing.B, gro bool) {
+	sender, receiver := listenLocalUDP(), listenLocalUDP()
+	defer sender.Close()
+	defer receiver.Close()
+	common.Must(receiver.SetReadBuffer(4 * 1024 * 1024))
+
+	payloads := make([][]byte, 64)
+	addrs := make([]*net.UDPAddr, 64)
+	for i := range payloads {
+		payloads[i] = make([]byte, 1200)
+		addrs[i] = receiver.LocalAddr().(*net.UDPAddr)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		writer := NewBatchConn(sender, false)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			writer.WriteBatch(payloads, addrs)
+		}
+	}()
+
+	reader := NewBatchConn(receiver, gro)
+	b.SetBytes(1200)
+	b.ResetTimer()
+	for n := 0; n < b.N; {
+		mb, err := reader.ReadMultiBuffer()
+		common.Must(err)
+		n += len(mb)
+		buf.ReleaseMulti(mb)
+	}
+}
+
+func BenchmarkBatchConnRead(b *testing.B) {
+	benchmarkUDPRead(b, false)
+}
+
+func BenchmarkBatchConnReadGRO(b *testing.B) {
+	benchmarkUDPRead(b, true)
+}
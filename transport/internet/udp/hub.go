@@ -2,6 +2,7 @@ package udp
 
 import (
 	"context"
+	"sync"
 
 	"github.com/xtls/xray-core/common/buf"
 	"github.com/xtls/xray-core/common/errors"
@@ -24,11 +25,21 @@ func HubReceiveOriginalDestination(r bool) HubOption {
 	}
 }
 
+// HubPorts makes the hub listen on the ports besides the one of ListenUDP,
+// and set the Target of packets to the local address they are received on.
+func HubPorts(ports ...net.Port) HubOption {
+	return func(h *Hub) {
+		h.ports = ports
+	}
+}
+
 type Hub struct {
 	conn         *net.UDPConn
 	cache        chan *udp.Packet
 	capacity     int
 	recvOrigDest bool
+	ports        []net.Port
+	conns        map[net.Port]*net.UDPConn
 }
 
 func ListenUDP(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig, options ...HubOption) (*Hub, error) {
@@ -59,13 +70,56 @@ func ListenUDP(ctx context.Context, address net.Address, port net.Port, streamSe
 	hub.conn = udpConn.(*net.UDPConn)
 	hub.cache = make(chan *udp.Packet, hub.capacity)
 
-	go hub.start()
+	if len(hub.ports) == 0 {
+		go func() {
+			if hub.recvOrigDest {
+				hub.start(hub.conn, false)
+			} else {
+				hub.startBatch()
+			}
+			close(hub.cache)
+		}()
+		return hub, nil
+	}
+
+	hub.conns = map[net.Port]*net.UDPConn{port: hub.conn}
+	for _, p := range hub.ports {
+		if _, found := hub.conns[p]; found {
+			continue
+		}
+		conn, err := internet.ListenSystemPacket(ctx, &net.UDPAddr{
+			IP:   address.IP(),
+			Port: int(p),
+		}, sockopt)
+		if err != nil {
+			hub.Close()
+			return nil, errors.New("failed to listen UDP on ", address, ":", p).Base(err)
+		}
+		hub.conns[p] = conn.(*net.UDPConn)
+	}
+	errors.LogInfo(ctx, "listening UDP on ", address, " at ", len(hub.conns), " ports")
+
+	var wg sync.WaitGroup
+	for _, conn := range hub.conns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hub.start(conn, true)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(hub.cache)
+	}()
 	return hub, nil
 }
 
 // Close implements net.Listener.
 func (h *Hub) Close() error {
 	h.conn.Close()
+	for _, conn := range h.conns {
+		conn.Close()
+	}
 	return nil
 }
 
@@ -76,11 +130,52 @@ func (h *Hub) WriteTo(payload []byte, dest net.Destination) (int, error) {
 	})
 }
 
-func (h *Hub) start() {
-	c := h.cache
-	defer close(c)
+// WriteToFrom writes payload to dest from the local port of the hub, or from
+// the port of ListenUDP if the hub does not listen on it.
+func (h *Hub) WriteToFrom(payload []byte, dest net.Destination, port net.Port) (int, error) {
+	conn, found := h.conns[port]
+	if !found {
+		conn = h.conn
+	}
+	return conn.WriteToUDP(payload, &net.UDPAddr{
+		IP:   dest.Address.IP(),
+		Port: int(dest.Port),
+	})
+}
 
+// startBatch reads packets from the conn to the cache in batches.
+func (h *Hub) startBatch() {
+	conn := NewBatchConn(h.conn, true)
+	for {
+		mb, err := conn.ReadMultiBuffer()
+		if err != nil {
+			errors.LogInfoInner(context.Background(), err, "failed to read UDP msg")
+			break
+		}
+		for _, b := range mb {
+			payload := &udp.Packet{
+				Payload: b,
+				Source:  *b.UDP,
+			}
+			b.UDP = nil
+			select {
+			case h.cache <- payload:
+			default:
+				b.Release()
+			}
+		}
+	}
+}
+
+// start reads packets from conn to the cache, with their local addresses
+// as targets if setTarget.
+func (h *Hub) start(conn *net.UDPConn, setTarget bool) {
+	c := h.cache
 	oobBytes := make([]byte, 256)
+	var localAddr net.Destination
+	if setTarget {
+		localAddr = net.DestinationFromAddr(conn.LocalAddr())
+	}
 
 	for {
 		buffer := buf.New()
@@ -88,7 +183,7 @@ func (h *Hub) start() {
 		var addr *net.UDPAddr
 		rawBytes := buffer.Extend(buf.Size)
 
-		n, noob, _, addr, err := ReadUDPMsg(h.conn, rawBytes, oobBytes)
+		n, noob, _, addr, err := ReadUDPMsg(conn, rawBytes, oobBytes)
 		if err != nil {
 			errors.LogInfoInner(context.Background(), err, "failed to read UDP msg")
 			buffer.Release()
@@ -104,6 +199,7 @@ func (h *Hub) start() {
 		payload := &udp.Packet{
 			Payload: buffer,
 			Source:  net.UDPDestination(net.IPAddress(addr.IP), net.Port(addr.Port)),
+			Target:  localAddr,
 		}
 		if h.recvOrigDest && noob > 0 {
 			payload.Target = RetrieveOriginalDest(oobBytes[:noob])